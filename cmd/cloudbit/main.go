@@ -2,9 +2,14 @@ package main
 
 import (
 	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/internal/commands/auth"
 	"github.com/cloudbit-ch/cli/v2/internal/commands/common"
 	"github.com/cloudbit-ch/cli/v2/internal/commands/compute"
+	"github.com/cloudbit-ch/cli/v2/internal/commands/config"
+	"github.com/cloudbit-ch/cli/v2/internal/commands/dev"
 	"github.com/cloudbit-ch/cli/v2/internal/commands/kubernetes"
+	"github.com/cloudbit-ch/cli/v2/internal/commands/macbaremetal"
+	"github.com/cloudbit-ch/cli/v2/internal/commands/manifest"
 	"github.com/cloudbit-ch/cli/v2/internal/commands/objectstorage"
 )
 
@@ -20,11 +25,22 @@ func main() {
 		Modules: []commands.ModuleFactory{
 			common.Location,
 			common.Module,
+			common.Order,
 			common.Product,
 
 			compute.Module,
 			kubernetes.Module,
+			macbaremetal.Module,
 			objectstorage.Module,
+
+			manifest.ApplyCommand,
+			manifest.DiffCommand,
+
+			config.Module,
+			dev.Module,
+			auth.LoginCommand,
+			auth.LogoutCommand,
+			auth.WhoamiCommand,
 		},
 	}
 
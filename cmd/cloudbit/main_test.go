@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudbit-ch/cli/v2/pkg/apitest"
+)
+
+// runMainEnv makes the test binary run the cli instead of the tests, which allows the tests to run the commands as a
+// separate process, just like the users do.
+const runMainEnv = "CLOUDBIT_TEST_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) != "" {
+		main()
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// sandbox runs the cli against a new in-memory api.
+type sandbox struct {
+	t        *testing.T
+	endpoint string
+	home     string
+}
+
+func newSandbox(t *testing.T) *sandbox {
+	server := apitest.NewTestServer(apitest.Options{
+		Token:       "sandbox",
+		OrderDelay:  100 * time.Millisecond,
+		ActionDelay: 100 * time.Millisecond,
+	})
+	t.Cleanup(server.Close)
+
+	return &sandbox{t: t, endpoint: server.URL + "/", home: t.TempDir()}
+}
+
+// run runs the cli with the arguments and returns its output. The test fails if the command fails.
+func (s *sandbox) run(args ...string) string {
+	s.t.Helper()
+
+	stdout, stderr, err := s.exec(args...)
+	if err != nil {
+		s.t.Fatalf("cloudbit %s: %v\n%s", strings.Join(args, " "), err, stderr)
+	}
+
+	return stdout
+}
+
+func (s *sandbox) exec(args ...string) (string, string, error) {
+	args = append(args, "--endpoint", s.endpoint, "--token", "sandbox")
+
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), runMainEnv+"=1", "HOME="+s.home)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestObjectRoundTrip(t *testing.T) {
+	s := newSandbox(t)
+
+	s.run("object-storage", "instance", "create", "--location", "ALP1")
+	s.run("object-storage", "bucket", "create", "roundtrip")
+
+	local := t.TempDir()
+	files := map[string][]byte{
+		"index.html":     []byte("<h1>hello</h1>"),
+		"css/site.css":   []byte("body { margin: 0; }"),
+		"img/large.bin":  randomBytes(t, 11<<20),
+		"img/remove.bin": randomBytes(t, 1024),
+	}
+	writeFiles(t, local, files)
+
+	// a single file larger than the part size is uploaded in multiple parts
+	s.run("object-storage", "object", "cp", "--part-size", "5", filepath.Join(local, "img/large.bin"), "s3://roundtrip/large.bin")
+
+	// a trailing separator downloads into the directory using the name of the object
+	download := t.TempDir()
+	s.run("object-storage", "object", "cp", "s3://roundtrip/large.bin", filepath.Join(download, "copy.bin"))
+	s.run("object-storage", "object", "cp", "s3://roundtrip/large.bin", download+string(filepath.Separator))
+	checkFile(t, filepath.Join(download, "copy.bin"), files["img/large.bin"])
+	checkFile(t, filepath.Join(download, "large.bin"), files["img/large.bin"])
+
+	s.run("object-storage", "object", "sync", local, "s3://roundtrip/site/")
+	checkNames(t, s.run("object-storage", "object", "ls", "-r", "-o", "name", "s3://roundtrip/site/"),
+		"site/css/site.css", "site/img/large.bin", "site/img/remove.bin", "site/index.html")
+
+	// deleted files are only removed from the bucket using --delete
+	if err := os.Remove(filepath.Join(local, "img/remove.bin")); err != nil {
+		t.Fatal(err)
+	}
+	delete(files, "img/remove.bin")
+
+	s.run("object-storage", "object", "sync", "--delete", "--force", local, "s3://roundtrip/site/")
+
+	mirror := t.TempDir()
+	s.run("object-storage", "object", "sync", "s3://roundtrip/site/", mirror)
+	for name, data := range files {
+		checkFile(t, filepath.Join(mirror, name), data)
+	}
+	if _, err := os.Stat(filepath.Join(mirror, "img/remove.bin")); !os.IsNotExist(err) {
+		t.Errorf("expected deleted file not to be synchronized, got %v", err)
+	}
+
+	s.run("object-storage", "object", "rm", "--force", "s3://roundtrip/large.bin")
+	checkNames(t, s.run("object-storage", "object", "ls", "-r", "-o", "name", "s3://roundtrip"),
+		"site/css/site.css", "site/img/large.bin", "site/index.html")
+
+	s.run("object-storage", "object", "rm", "--recursive", "--force", "s3://roundtrip/site/")
+	checkNames(t, s.run("object-storage", "object", "ls", "-r", "-o", "name", "s3://roundtrip"))
+
+	s.run("object-storage", "bucket", "delete", "--force", "roundtrip")
+}
+
+func randomBytes(t *testing.T, size int) []byte {
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func writeFiles(t *testing.T, dir string, files map[string][]byte) {
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func checkFile(t *testing.T, path string, expected []byte) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !bytes.Equal(data, expected) {
+		t.Errorf("%s: expected %d bytes matching the source, got %d bytes", path, len(expected), len(data))
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPublicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAdxbXuS+RAes83GvPqQ04kWRrwOzbfsgF+HSP3Sfv4D test"
+
+func TestServer(t *testing.T) {
+	s := newSandbox(t)
+
+	publicKey := filepath.Join(t.TempDir(), "id_ed25519.pub")
+	if err := os.WriteFile(publicKey, []byte(testPublicKey), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s.run("compute", "key-pair", "create", "--name", "test", "--public-key", publicKey)
+	checkNames(t, s.run("compute", "key-pair", "list", "-o", "name"), "test")
+
+	// creating a server places an order, which is waited for
+	s.run("compute", "server", "create", "--name", "web", "--location", "ALP1", "--image", "linux-ubuntu-22.04-lts", "--product", "b1.1x1", "--key-pair", "test")
+	checkNames(t, s.run("order", "list", "-o", "custom-columns=STATUS:status.name", "--no-headers"), "Succeeded")
+	checkField(t, s.run("compute", "server", "list", "-o", "json"), "status", "Running")
+
+	s.run("compute", "server", "stop", "web")
+	s.run("compute", "server", "wait", "web", "--for", "status=stopped")
+
+	s.run("compute", "server", "delete", "--force", "web")
+	checkNames(t, s.run("compute", "server", "list", "-o", "name"))
+
+	s.run("compute", "key-pair", "delete", "--force", "test")
+	checkNames(t, s.run("compute", "key-pair", "list", "-o", "name"))
+}
+
+func TestNetwork(t *testing.T) {
+	s := newSandbox(t)
+
+	s.run("compute", "network", "create", "--name", "backend", "--location", "ALP1", "--cidr", "10.0.0.0/24")
+	checkField(t, s.run("compute", "network", "list", "-o", "json"), "cidr", "10.0.0.0/24")
+
+	s.run("compute", "network", "delete", "--force", "backend")
+	checkNames(t, s.run("compute", "network", "list", "-o", "name"))
+}
+
+func TestVolume(t *testing.T) {
+	s := newSandbox(t)
+
+	s.run("compute", "volume", "create", "--name", "data", "--location", "ALP1", "--size", "20")
+	s.run("compute", "volume", "wait", "data", "--for", "status=available")
+
+	s.run("compute", "volume", "expand", "data", "--size", "30")
+	checkField(t, s.run("compute", "volume", "list", "-o", "json"), "size", 30.0)
+
+	s.run("compute", "volume", "delete", "--force", "data")
+	checkNames(t, s.run("compute", "volume", "list", "-o", "name"))
+}
+
+func TestKubernetesCluster(t *testing.T) {
+	s := newSandbox(t)
+
+	s.run("kubernetes", "cluster", "create", "--name", "apps", "--location", "ALP1", "--worker-count", "2", "--worker-product", "k1.2x4")
+	checkNames(t, s.run("kubernetes", "cluster", "list", "-o", "name"), "apps")
+	checkField(t, s.run("kubernetes", "cluster", "list", "-o", "json"), "status", "Healthy")
+
+	// the upgrade waits until the cluster has been scaled
+	s.run("kubernetes", "cluster", "upgrade", "apps", "--worker-count", "3", "--worker-product", "k1.2x4")
+	checkField(t, s.run("kubernetes", "cluster", "list", "-o", "json"), "status", "Healthy")
+	if workers := s.run("kubernetes", "cluster", "list", "-o", "jsonpath={.items[0].node_count.current.worker}"); workers != "3\n" {
+		t.Errorf("expected 3 workers after the upgrade, got %q", workers)
+	}
+
+	s.run("kubernetes", "cluster", "delete", "--force", "apps")
+	checkNames(t, s.run("kubernetes", "cluster", "list", "-o", "name"))
+}
+
+func TestObjectStorageInstance(t *testing.T) {
+	s := newSandbox(t)
+
+	s.run("object-storage", "instance", "create", "--location", "ALP1")
+	checkNames(t, s.run("object-storage", "instance", "list", "-o", "name"), "Object Storage ALP1")
+
+	s.run("object-storage", "instance", "delete", "--force", "Object Storage ALP1")
+	checkNames(t, s.run("object-storage", "instance", "list", "-o", "name"))
+}
+
+func TestWhoami(t *testing.T) {
+	s := newSandbox(t)
+
+	output := s.run("whoami", "-o", "json")
+	if !strings.Contains(output, `"organization"`) {
+		t.Errorf("expected organization of the token, got %s", output)
+	}
+}
+
+// checkNames checks the output of a command using the name format.
+func checkNames(t *testing.T, output string, expected ...string) {
+	t.Helper()
+
+	var actual []string
+	if output = strings.TrimSpace(output); output != "" {
+		actual = strings.Split(output, "\n")
+	}
+
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+// checkField checks the field of the single item in the json output of a list command.
+func checkField(t *testing.T, output string, field string, expected interface{}) {
+	t.Helper()
+
+	var items []map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &items); err != nil {
+		t.Fatalf("parse %s: %v", output, err)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("expected a single item, got %d", len(items))
+	}
+
+	actual, ok := items[0][field]
+	if !ok {
+		t.Fatalf("expected field %q in %v", field, items[0])
+	}
+
+	if nested, ok := actual.(map[string]interface{}); ok {
+		actual = nested["name"]
+	}
+
+	if actual != expected {
+		t.Errorf("expected %s %v, got %v", field, expected, actual)
+	}
+}
@@ -0,0 +1,319 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/common"
+	"github.com/cloudbit-ch/cli/v2/pkg/console"
+	"github.com/cloudbit-ch/cli/v2/pkg/keyring"
+)
+
+func LoginCommand(app commands.Application) *cobra.Command {
+	return (&loginCommand{}).Build(app)
+}
+
+func LogoutCommand(app commands.Application) *cobra.Command {
+	return (&logoutCommand{}).Build(app)
+}
+
+func WhoamiCommand(app commands.Application) *cobra.Command {
+	return (&whoamiCommand{}).Build(app)
+}
+
+type loginCommand struct {
+	app        commands.Application
+	tokenStdin bool
+	keyring    bool
+}
+
+func (l *loginCommand) Run(cmd *cobra.Command, args []string) error {
+	token, err := l.readToken()
+	if err != nil {
+		return err
+	}
+
+	endpoint := viper.GetString(commands.FlagEndpoint)
+	client := commands.NewClient(l.app, endpoint, token)
+
+	progress := console.NewProgress("Validating token")
+	go progress.Display(commands.Stderr)
+
+	_, err = common.Locations(cmd.Context(), client)
+	progress.Done()
+	if err != nil {
+		return fmt.Errorf("validate token: %w", err)
+	}
+
+	file, err := commands.LoadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	stored := token
+	if l.keyring {
+		ring, passphrase, err := commands.OpenKeyring(true)
+		if err != nil {
+			return err
+		}
+
+		ring.Set(commands.KeyringEntry(), token)
+		if err = ring.Save(passphrase); err != nil {
+			return err
+		}
+
+		stored = ""
+	}
+
+	err = storeCredentials(file, commands.ActiveProfile(), stored, l.keyring, endpointOverride(cmd))
+	if err != nil {
+		return err
+	}
+
+	if err = file.Save(); err != nil {
+		return err
+	}
+
+	location := file.Path
+	if l.keyring {
+		location = commands.KeyringPath()
+	}
+
+	commands.Stderr.Printf("logged in, the token is stored in %s.\n", location)
+	return nil
+}
+
+func (l *loginCommand) readToken() (string, error) {
+	if l.tokenStdin {
+		token, err := console.ReadLine()
+		if err != nil {
+			return "", fmt.Errorf("read token: %w", err)
+		}
+
+		return strings.TrimSpace(token), checkToken(token)
+	}
+
+	if !commands.Config.Terminal {
+		return "", errors.New("cannot prompt for the token without a terminal, use --token-stdin instead")
+	}
+
+	return console.Password(commands.Stderr, "Application Token", checkToken)
+}
+
+func (l *loginCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (l *loginCommand) Build(app commands.Application) *cobra.Command {
+	l.app = app
+
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Authenticate with an application token",
+		Long: commands.FormatHelp(fmt.Sprintf(`
+			Asks for an application token, verifies it and stores it for further use.
+
+			By default, the token is stored in plain text in the config file, which is only readable by the current
+			user. Using the --keyring flag, the token is stored in a local keyring file instead, which is encrypted
+			with a passphrase. The passphrase is asked for every time the token is used, unless it is provided by the
+			%s_KEYRING_PASSPHRASE environment variable.
+
+			If a profile is selected, the token is stored in the profile.
+		`, strings.ToUpper(app.Name))),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Log in and store the token in the config file
+      %[1]s login
+
+      # Log in to the staging profile and store the token in the encrypted keyring
+      %[1]s login --profile staging --keyring
+
+      # Log in non-interactively, e.g. within a pipeline
+      echo "$TOKEN" | %[1]s login --token-stdin
+		`, app.Name)),
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: l.CompleteArg,
+		RunE:              l.Run,
+		Annotations: map[string]string{
+			commands.AnnotationNoAuthentication: "true",
+		},
+	}
+
+	cmd.Flags().BoolVar(&l.tokenStdin, "token-stdin", false, "read the token from stdin instead of prompting for it")
+	cmd.Flags().BoolVar(&l.keyring, "keyring", false, "store the token in the encrypted keyring instead of the config file")
+
+	return cmd
+}
+
+type logoutCommand struct{}
+
+func (l *logoutCommand) Run(cmd *cobra.Command, args []string) error {
+	file, err := commands.LoadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	if usesKeyring(file, commands.ActiveProfile()) && keyring.Exists(commands.KeyringPath()) {
+		ring, passphrase, err := commands.OpenKeyring(false)
+		if err != nil {
+			return err
+		}
+
+		ring.Delete(commands.KeyringEntry())
+		if err = ring.Save(passphrase); err != nil {
+			return err
+		}
+	}
+
+	if err = storeCredentials(file, commands.ActiveProfile(), "", false, ""); err != nil {
+		return err
+	}
+
+	if err = file.Save(); err != nil {
+		return err
+	}
+
+	commands.Stderr.Println("logged out.")
+	return nil
+}
+
+func (l *logoutCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (l *logoutCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "logout",
+		Short:             "Remove the stored token",
+		Long:              "Removes the token of the selected profile from the config file and the keyring.",
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: l.CompleteArg,
+		RunE:              l.Run,
+		Annotations: map[string]string{
+			commands.AnnotationNoAuthentication: "true",
+		},
+	}
+
+	return cmd
+}
+
+type identity struct {
+	Profile      string              `json:"profile"`
+	Endpoint     string              `json:"endpoint"`
+	Organization common.Organization `json:"organization"`
+}
+
+func (i identity) Columns() []string {
+	return []string{"profile", "endpoint", "organization id", "organization"}
+}
+
+func (i identity) Values() map[string]interface{} {
+	return map[string]interface{}{
+		"profile":         i.Profile,
+		"endpoint":        i.Endpoint,
+		"organization id": i.Organization.ID,
+		"organization":    i.Organization.Name,
+	}
+}
+
+type whoamiCommand struct{}
+
+func (w *whoamiCommand) Run(cmd *cobra.Command, args []string) error {
+	organization, err := common.CurrentOrganization(cmd.Context(), commands.Config.Client)
+	if err != nil {
+		return fmt.Errorf("fetch organization: %w", err)
+	}
+
+	return commands.PrintStdout(identity{
+		Profile:      commands.ActiveProfile(),
+		Endpoint:     viper.GetString(commands.FlagEndpoint),
+		Organization: organization,
+	})
+}
+
+func (w *whoamiCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (w *whoamiCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "whoami",
+		Short:             "Show the organization of the token",
+		Long:              "Shows the profile in use and the organization the authentication token belongs to.",
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: w.CompleteArg,
+		RunE:              w.Run,
+	}
+
+	return cmd
+}
+
+// storeCredentials writes the token into the profile or, if no profile is selected, into the top level of the config
+// file. An empty token removes the token.
+func storeCredentials(file *commands.ConfigFile, profileName string, token string, useKeyring bool, endpoint string) error {
+	if profileName == "" {
+		file.Set(commands.FlagToken, nilIfEmpty(token))
+		file.Set(commands.KeyKeyring, nilIfEmpty(useKeyring))
+
+		if endpoint != "" {
+			file.Set(commands.FlagEndpoint, endpoint)
+		}
+
+		return nil
+	}
+
+	profile, _, err := file.Profile(profileName)
+	if err != nil {
+		return err
+	}
+
+	profile.Token = token
+	profile.Keyring = useKeyring
+
+	if endpoint != "" {
+		profile.Endpoint = endpoint
+	}
+
+	return file.SetProfile(profileName, profile)
+}
+
+func usesKeyring(file *commands.ConfigFile, profileName string) bool {
+	if profileName == "" {
+		enabled, _ := file.Get(commands.KeyKeyring).(bool)
+		return enabled
+	}
+
+	profile, _, err := file.Profile(profileName)
+	return err == nil && profile.Keyring
+}
+
+// endpointOverride returns the endpoint passed as flag, so it is stored alongside the token.
+func endpointOverride(cmd *cobra.Command) string {
+	flag := cmd.Flags().Lookup(commands.FlagEndpoint)
+	if flag == nil || !flag.Changed {
+		return ""
+	}
+
+	return flag.Value.String()
+}
+
+func nilIfEmpty[T comparable](val T) interface{} {
+	var empty T
+	if val == empty {
+		return nil
+	}
+
+	return val
+}
+
+func checkToken(token string) error {
+	if len(strings.TrimSpace(token)) == 0 {
+		return errors.New("the token must not be empty")
+	}
+
+	return nil
+}
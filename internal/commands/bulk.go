@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/pkg/console"
+	"github.com/cloudbit-ch/cli/v2/pkg/filter"
+)
+
+const defaultBulkConcurrency = 4
+
+// BulkOptions holds the flags of commands which operate on multiple resources at once. The resources are either
+// addressed by the arguments of the command or selected using --all, optionally restricted by --filter.
+type BulkOptions struct {
+	All         bool
+	Filter      string
+	Concurrency int
+}
+
+// AddBulkFlags registers the --all, --filter and --concurrency flags and validates the arguments of the command
+// against them.
+func AddBulkFlags(cmd *cobra.Command, opts *BulkOptions, kind string) {
+	cmd.Flags().BoolVar(&opts.All, "all", false, fmt.Sprintf("select all %ss, optionally restricted by --filter", kind))
+	cmd.Flags().StringVar(&opts.Filter, "filter", "", "custom term to filter the items selected by --all")
+	cmd.Flags().IntVar(&opts.Concurrency, "concurrency", defaultBulkConcurrency, "maximum number of requests sent in parallel")
+
+	cmd.Args = opts.validateArgs
+}
+
+func (b *BulkOptions) validateArgs(cmd *cobra.Command, args []string) error {
+	switch {
+	case b.All && len(args) != 0:
+		return fmt.Errorf("either pass the items as arguments or use --all, not both")
+	case !b.All && len(args) == 0:
+		return fmt.Errorf("requires at least 1 arg(s) or --all, only received 0")
+	case !b.All && b.Filter != "":
+		return fmt.Errorf("--filter can only be used together with --all")
+	case b.Concurrency < 1:
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	return nil
+}
+
+// SelectBulk resolves the items addressed by the arguments or, if --all is set, all items matching the filter. Items
+// addressed by multiple arguments are only returned once.
+func SelectBulk[T filter.Filterable](items []T, args []string, opts BulkOptions) ([]T, error) {
+	if opts.All {
+		if opts.Filter != "" {
+			items = filter.Find(items, opts.Filter)
+		}
+
+		if len(items) == 0 {
+			return nil, fmt.Errorf("no item found searching for the term %q", opts.Filter)
+		}
+
+		return items, nil
+	}
+
+	selected := make([]T, 0, len(args))
+	seen := map[string]bool{}
+
+	for _, arg := range args {
+		item, err := filter.FindOne(items, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		key := strings.Join(item.Keys(), "\x00")
+		if !seen[key] {
+			seen[key] = true
+			selected = append(selected, item)
+		}
+	}
+
+	return selected, nil
+}
+
+// ConfirmBulk asks for a single confirmation to run the action on all items. A single item is confirmed with the same
+// question as before bulk operations existed.
+func ConfirmBulk[T fmt.Stringer](action string, kind string, items []T) bool {
+	if len(items) == 1 {
+		return Confirm(fmt.Sprintf("Are you sure you want to %s the %s %q?", action, kind, items[0]))
+	}
+
+	Stderr.Printf("The following %d %ss are selected:\n", len(items), kind)
+	for _, item := range items {
+		Stderr.Printf("  - %s\n", item)
+	}
+
+	return Confirm(fmt.Sprintf("Are you sure you want to %s these %d %ss?", action, len(items), kind))
+}
+
+// RunBulk runs the action on every item with at most concurrency calls in parallel. The outcome of every item is
+// reported on stderr as soon as it is known, followed by a summary. An error is returned if any of the calls failed, or
+// ErrDryRun if the calls were only simulated.
+func RunBulk[T fmt.Stringer](ctx context.Context, action string, kind string, items []T, concurrency int, run func(ctx context.Context, item T) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed int
+		dryRun int
+	)
+
+	semaphore := make(chan struct{}, concurrency)
+
+	for _, item := range items {
+		semaphore <- struct{}{}
+		wg.Add(1)
+
+		go func(item T) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			err := run(ctx, item)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if errors.Is(err, ErrDryRun) {
+				dryRun++
+				Stderr.Color(console.Yellow).Printf("dry run").Reset().Printf(" %s %s\n", kind, item)
+				return
+			}
+
+			if err != nil {
+				failed++
+				Stderr.Color(console.Red).Printf("failed").Reset().Printf("  %s %s: %v\n", kind, item, err)
+				return
+			}
+
+			Stderr.Color(console.Green).Printf("ok").Reset().Printf("      %s %s\n", kind, item)
+		}(item)
+	}
+
+	wg.Wait()
+
+	if len(items) > 1 && dryRun == 0 {
+		Stderr.Printf("%d succeeded, %d failed.\n", len(items)-failed, failed)
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("failed to %s %d of %d %ss", action, failed, len(items), kind)
+	}
+
+	if dryRun != 0 {
+		return ErrDryRun
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/cloudbit-ch/cli/v2/pkg/api/common"
 	"github.com/cloudbit-ch/cli/v2/pkg/console"
@@ -45,12 +46,18 @@ func FormatExamples(examples string) string {
 	return FormatAndIndent(examples, 1)
 }
 
+// Confirm asks the user to confirm an action. Nothing is modified in dry run mode, which is why the confirmation is
+// skipped there.
 func Confirm(message string) bool {
+	if viper.GetBool(FlagDryRun) {
+		return true
+	}
+
 	return console.Confirm(Stderr, message)
 }
 
 func ConfirmDeletion(kind string, item fmt.Stringer) bool {
-	return console.Confirm(Stderr, fmt.Sprintf("Are you sure you want to delete the %s %q?", kind, item))
+	return Confirm(fmt.Sprintf("Are you sure you want to delete the %s %q?", kind, item))
 }
 
 func WaitForOrder(ctx context.Context, action string, ordering common.Ordering) (common.Order, error) {
@@ -61,3 +68,13 @@ func WaitForOrder(ctx context.Context, action string, ordering common.Ordering)
 
 	return common.WaitForOrder(ctx, Config.Client, ordering)
 }
+
+// PrintOrdering prints the reference of an order which is not waited for, so it can be passed to the order commands.
+func PrintOrdering(ordering common.Ordering) error {
+	reference, err := common.NewOrderReference(ordering)
+	if err != nil {
+		return fmt.Errorf("parse order reference: %w", err)
+	}
+
+	return PrintStdout(reference)
+}
@@ -0,0 +1,224 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/common"
+	"github.com/cloudbit-ch/cli/v2/pkg/filter"
+)
+
+func Order(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "order",
+		Aliases: []string{"orders"},
+		Short:   "Manage orders",
+		Long: commands.FormatHelp(fmt.Sprintf(`
+			Orders are placed by the commands creating or upgrading servers, load balancers, kubernetes clusters and
+			mac bare metal devices. Using --no-wait these commands print the order reference instead of waiting for the
+			order, which allows to wait for it later using "%[1]s order wait".
+		`, app.Name)),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Create two servers in parallel and wait for both of them
+      first=$(%[1]s compute server create --name web-1 --location ALP1 --image ubuntu-22.04 --product b1.1x1 --key-pair default --no-wait -o name)
+      second=$(%[1]s compute server create --name web-2 --location ALP1 --image ubuntu-22.04 --product b1.1x1 --key-pair default --no-wait -o name)
+      %[1]s order wait "$first" "$second"
+		`, app.Name)),
+	}
+
+	commands.Add(app, cmd,
+		&orderListCommand{},
+		&orderGetCommand{},
+		&orderWaitCommand{},
+	)
+
+	return cmd
+}
+
+type orderListCommand struct {
+	filter string
+}
+
+func (o *orderListCommand) Run(cmd *cobra.Command, args []string) error {
+	items, err := common.NewOrderService(commands.Config.Client).List(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("fetch orders: %w", err)
+	}
+
+	if len(o.filter) != 0 {
+		items = filter.Find(items, o.filter)
+	}
+
+	return commands.PrintStdout(items)
+}
+
+func (o *orderListCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (o *orderListCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "list",
+		Short:             "List orders",
+		Long:              "Lists all orders of the current organization.",
+		ValidArgsFunction: o.CompleteArg,
+		RunE:              o.Run,
+	}
+
+	cmd.Flags().StringVar(&o.filter, "filter", "", "custom term to filter the results")
+
+	return cmd
+}
+
+type orderGetCommand struct {
+}
+
+func (o *orderGetCommand) Run(cmd *cobra.Command, args []string) error {
+	id, err := common.ParseOrderIdentifier(args[0])
+	if err != nil {
+		return err
+	}
+
+	order, err := common.NewOrderService(commands.Config.Client).Get(cmd.Context(), id)
+	if err != nil {
+		return fmt.Errorf("fetch order: %w", err)
+	}
+
+	return commands.PrintStdout(order)
+}
+
+func (o *orderGetCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeOrder(cmd.Context(), toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (o *orderGetCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "get ORDER",
+		Aliases:           []string{"show"},
+		Short:             "Show an order",
+		Long:              "Shows the status of an order. The order is identified either by its id or its reference.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: o.CompleteArg,
+		RunE:              o.Run,
+	}
+
+	return cmd
+}
+
+type orderWaitCommand struct {
+}
+
+func (o *orderWaitCommand) Run(cmd *cobra.Command, args []string) error {
+	ids := make([]int, len(args))
+	for idx, arg := range args {
+		id, err := common.ParseOrderIdentifier(arg)
+		if err != nil {
+			return err
+		}
+
+		ids[idx] = id
+	}
+
+	service := common.NewOrderService(commands.Config.Client)
+	orders := make([]common.Order, len(ids))
+	processed := 0
+
+	action := fmt.Sprintf("Waiting for %d orders", len(ids))
+	if len(ids) == 1 {
+		action = fmt.Sprintf("Waiting for order %d", ids[0])
+	}
+
+	err := commands.WaitFor(cmd.Context(), action, func(ctx context.Context) (bool, error) {
+		for idx, id := range ids {
+			if orders[idx].Processed() {
+				continue
+			}
+
+			order, err := service.Get(ctx, id)
+			if err != nil {
+				if ctx.Err() != nil {
+					return false, nil
+				}
+
+				return false, fmt.Errorf("fetch order %d: %w", id, err)
+			}
+
+			orders[idx] = order
+			if order.Processed() {
+				processed++
+			}
+		}
+
+		return processed == len(ids), nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timed out with %d of %d orders processed", processed, len(ids))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = commands.PrintStdout(orders); err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		if order.Status.ID == common.OrderStatusFailed {
+			return fmt.Errorf("order %d: %w", order.ID, common.ErrOrderFailed)
+		}
+	}
+
+	return nil
+}
+
+func (o *orderWaitCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeOrder(cmd.Context(), toComplete)
+}
+
+func (o *orderWaitCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait ORDER...",
+		Short: "Wait for orders to be processed",
+		Long: commands.FormatHelp(`
+			Blocks until all given orders have been processed or the timeout expires. The orders are identified either by
+			their id or their reference. The command fails if any of the orders failed.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Wait for the order with id 1234
+      %[1]s order wait 1234
+
+      # Wait for an order using its reference
+      %[1]s order wait /v4/orders/1234 --timeout 15m
+		`, app.Name)),
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: o.CompleteArg,
+		RunE:              o.Run,
+	}
+
+	return cmd
+}
+
+func completeOrder(ctx context.Context, term string) ([]string, cobra.ShellCompDirective) {
+	orders, err := common.NewOrderService(commands.Config.Client).List(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	filtered := filter.Find(orders, term)
+
+	names := make([]string, len(filtered))
+	for i, order := range filtered {
+		names[i] = fmt.Sprint(order.ID)
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -120,6 +120,7 @@ func (e *elasticIPCreateCommand) Build(app commands.Application) *cobra.Command
 
 type elasticIPDeleteCommand struct {
 	force bool
+	bulk  commands.BulkOptions
 }
 
 func (e *elasticIPDeleteCommand) Run(cmd *cobra.Command, args []string) error {
@@ -130,63 +131,62 @@ func (e *elasticIPDeleteCommand) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("fetch elastic ips: %w", err)
 	}
 
-	elasticIP, err := filter.FindOne(elasticIPs, args[0])
+	elasticIPs, err = commands.SelectBulk(elasticIPs, args, e.bulk)
 	if err != nil {
 		return fmt.Errorf("find elastic ip: %w", err)
 	}
 
-	if elasticIP.Attachment.ID != 0 {
-		commands.Stderr.Errorf("WARNING: The elastic ip is still attached to a server. Active connections to the server might get disturbed.\n")
+	for _, elasticIP := range elasticIPs {
+		if elasticIP.Attachment.ID != 0 {
+			commands.Stderr.Errorf("WARNING: The elastic ip %s is still attached to a server. Active connections to the server might get disturbed.\n", elasticIP)
+		}
 	}
 
-	if !e.force && !commands.ConfirmDeletion("elastic ip", elasticIP) {
+	if !e.force && !commands.ConfirmBulk("delete", "elastic ip", elasticIPs) {
 		commands.Stderr.Println("aborted.")
 		return nil
 	}
 
-	if elasticIP.Attachment.ID != 0 {
-		err = service.Detach(cmd.Context(), elasticIP.Attachment.ID, elasticIP.ID)
-		if err != nil {
-			return fmt.Errorf("detach elastic ip: %w", err)
+	return commands.RunBulk(cmd.Context(), "delete", "elastic ip", elasticIPs, e.bulk.Concurrency, func(ctx context.Context, elasticIP compute.ElasticIP) error {
+		if elasticIP.Attachment.ID != 0 {
+			err := service.Detach(ctx, elasticIP.Attachment.ID, elasticIP.ID)
+			if err != nil {
+				return fmt.Errorf("detach elastic ip: %w", err)
+			}
 		}
-	}
-
-	err = service.Delete(cmd.Context(), elasticIP.ID)
-	if err != nil {
-		return fmt.Errorf("delete elastic ip: %w", err)
-	}
 
-	return nil
+		return service.Delete(ctx, elasticIP.ID)
+	})
 }
 
 func (e *elasticIPDeleteCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	if len(args) == 0 {
-		return completeElasticIP(cmd.Context(), toComplete, nil)
-	}
-
-	return nil, cobra.ShellCompDirectiveNoFileComp
+	return completeElasticIP(cmd.Context(), toComplete, nil)
 }
 
 func (e *elasticIPDeleteCommand) Build(app commands.Application) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "delete ELASTIC-IP",
+		Use:     "delete ELASTIC-IP...",
 		Aliases: []string{"del", "remove", "rm"},
-		Short:   "Delete elastic ip",
-		Long:    "Deletes a compute elastic ip.",
+		Short:   "Delete elastic ips",
+		Long:    "Deletes one or more compute elastic ips.",
 		Example: commands.FormatExamples(fmt.Sprintf(`
       # Delete a compute elastic ip
       %[1]s compute elastic-ip delete 1.1.1.1
       
       # Force the deletion a compute elastic ip without confirmation
       %[1]s compute elastic-ip delete 1.1.1.1 --force
+
+      # Delete multiple elastic ips with a single confirmation
+      %[1]s compute elastic-ip delete 1.1.1.1 2.2.2.2
 		`, app.Name)),
-		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: e.CompleteArg,
 		RunE:              e.Run,
 	}
 
 	cmd.Flags().BoolVar(&e.force, "force", false, "force the deletion of the elastic ip without asking for confirmation")
 
+	commands.AddBulkFlags(cmd, &e.bulk, "elastic ip")
+
 	return cmd
 }
 
@@ -24,6 +24,7 @@ func LoadBalancerCommand(app commands.Application) *cobra.Command {
 		&loadBalancerCreateCommand{},
 		&loadBalancerUpdateCommand{},
 		&loadBalancerDeleteCommand{},
+		&loadBalancerWaitCommand{},
 	)
 
 	commands.Add(app, cmd,
@@ -78,6 +79,7 @@ type loadBalancerCreateCommand struct {
 	internal  bool
 	network   string
 	privateIP net.IP
+	noWait    bool
 }
 
 func (l *loadBalancerCreateCommand) Run(cmd *cobra.Command, args []string) error {
@@ -104,6 +106,10 @@ func (l *loadBalancerCreateCommand) Run(cmd *cobra.Command, args []string) error
 		return fmt.Errorf("create load balancer: %w", err)
 	}
 
+	if l.noWait {
+		return commands.PrintOrdering(ordering)
+	}
+
 	order, err := commands.WaitForOrder(cmd.Context(), "Creating load balancer", ordering)
 	if err != nil {
 		return fmt.Errorf("wait for order: %w", err)
@@ -134,6 +140,7 @@ func (l *loadBalancerCreateCommand) Build(app commands.Application) *cobra.Comma
 	cmd.Flags().BoolVar(&l.internal, "internal", false, "do not attach a public elastic ip to the load balancer")
 	cmd.Flags().StringVar(&l.network, "network", "", "network to create the load balancer in")
 	cmd.Flags().IPVar(&l.privateIP, "private-ip", net.IP{}, "private ip of the load balancer within the network")
+	cmd.Flags().BoolVar(&l.noWait, "no-wait", false, "print the order reference instead of waiting for the load balancer to be created")
 
 	_ = cmd.MarkFlagRequired("name")
 	_ = cmd.MarkFlagRequired("location")
@@ -340,6 +347,60 @@ func (l *loadBalancerHealthCheckTypeListCommand) Build(app commands.Application)
 	return cmd
 }
 
+type loadBalancerWaitCommand struct {
+	opts commands.WaitOptions
+}
+
+func (l *loadBalancerWaitCommand) Run(cmd *cobra.Command, args []string) error {
+	loadBalancer, err := findLoadBalancer(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	loadBalancer, err = commands.WaitUntil(cmd.Context(), "load balancer", loadBalancer.Name, l.opts, func(ctx context.Context) (compute.LoadBalancer, error) {
+		return compute.NewLoadBalancerService(commands.Config.Client).Get(ctx, loadBalancer.ID)
+	})
+	if err != nil || l.opts.Deletion() {
+		return err
+	}
+
+	return commands.PrintStdout(loadBalancer)
+}
+
+func (l *loadBalancerWaitCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeLoadBalancer(cmd.Context(), toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (l *loadBalancerWaitCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait LOAD-BALANCER",
+		Short: "Wait for a load balancer to reach a state",
+		Long: commands.FormatHelp(`
+			Blocks until the load balancer matches the condition given by --for or the timeout expires. The condition is either
+			COLUMN=VALUE, which is compared with the column of the load balancer as shown by the list command, or delete to wait
+			until the load balancer no longer exists.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Wait until the load balancer is running
+      %[1]s compute load-balancer wait my-load-balancer --for status=running --timeout 10m
+
+      # Wait until the load balancer has been deleted
+      %[1]s compute load-balancer wait my-load-balancer --for delete
+		`, app.Name)),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: l.CompleteArg,
+		RunE:              l.Run,
+	}
+
+	commands.AddWaitFlags(cmd, &l.opts)
+
+	return cmd
+}
+
 func completeLoadBalancer(ctx context.Context, term string) ([]string, cobra.ShellCompDirective) {
 	loadBalancers, err := compute.NewLoadBalancerService(commands.Config.Client).List(ctx)
 	if err != nil {
@@ -2,18 +2,19 @@ package compute
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"strings"
-	"unicode"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/cloudbit-ch/cli/v2/internal/commands"
 	"github.com/cloudbit-ch/cli/v2/pkg/api/common"
 	"github.com/cloudbit-ch/cli/v2/pkg/api/compute"
+	"github.com/cloudbit-ch/cli/v2/pkg/cloudinit"
 	"github.com/cloudbit-ch/cli/v2/pkg/console"
 	"github.com/cloudbit-ch/cli/v2/pkg/filter"
 )
@@ -41,12 +42,16 @@ func ServerCommand(app commands.Application) *cobra.Command {
 		&serverUpdateCommand{},
 		&serverUpgradeCommand{},
 		&serverDeleteCommand{},
+		&serverWaitCommand{},
+		&serverSSHCommand{},
+		&serverCopyCommand{},
+		&serverInventoryCommand{},
 	)
 
 	commands.Add(app, cmd,
-		serverActionRunCommandPreset("start"),
-		serverActionRunCommandPreset("stop"),
-		serverActionRunCommandPreset("restart"),
+		&serverActionRunCommandPreset{action: "start"},
+		&serverActionRunCommandPreset{action: "stop"},
+		&serverActionRunCommandPreset{action: "restart"},
 	)
 
 	cmd.AddCommand(NetworkInterfaceCommand(app), ServerActionCommand(app), ServerVolumeCommand(app))
@@ -99,8 +104,10 @@ type serverCreateCommand struct {
 	privateIP        net.IP
 	keyPair          string
 	password         string
-	cloudInitFile    string
+	cloudInitFiles   []string
+	cloudInitVars    []string
 	attachExternalIP bool
+	noWait           bool
 }
 
 func (s *serverCreateCommand) Run(cmd *cobra.Command, args []string) error {
@@ -188,25 +195,20 @@ func (s *serverCreateCommand) Run(cmd *cobra.Command, args []string) error {
 	password := s.password
 	if image.IsWindows() {
 		if len(password) == 0 {
-			password, err = console.Password(commands.Stderr, "Windows User Password", checkWindowsPassword)
+			password, err = console.Password(commands.Stderr, "Windows User Password", compute.CheckWindowsPassword)
 			if err != nil {
 				return fmt.Errorf("read user password: %w", err)
 			}
 		}
 
-		if err = checkWindowsPassword(password); err != nil {
+		if err = compute.CheckWindowsPassword(password); err != nil {
 			return fmt.Errorf("check user password: %w", err)
 		}
 	}
 
-	cloudInit := ""
-	if len(s.cloudInitFile) != 0 {
-		data, err := os.ReadFile(s.cloudInitFile)
-		if err != nil {
-			return fmt.Errorf("read cloud init file: %w", err)
-		}
-
-		cloudInit = base64.StdEncoding.EncodeToString(data)
+	cloudInit, err := s.buildCloudInit(location.Name)
+	if err != nil {
+		return err
 	}
 
 	data := compute.ServerCreate{
@@ -229,6 +231,10 @@ func (s *serverCreateCommand) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("create server: %w", err)
 	}
 
+	if s.noWait {
+		return commands.PrintOrdering(ordering)
+	}
+
 	order, err := commands.WaitForOrder(cmd.Context(), "Creating server", ordering)
 	if err != nil {
 		return fmt.Errorf("wait for order: %w", err)
@@ -242,6 +248,52 @@ func (s *serverCreateCommand) Run(cmd *cobra.Command, args []string) error {
 	return commands.PrintStdout(server)
 }
 
+func (s *serverCreateCommand) buildCloudInit(location string) (string, error) {
+	vars := map[string]string{}
+	for _, variable := range s.cloudInitVars {
+		key, value, ok := strings.Cut(variable, "=")
+		if !ok || key == "" {
+			return "", fmt.Errorf("invalid cloud init variable %q: expected KEY=VALUE", variable)
+		}
+
+		vars[key] = value
+	}
+
+	templates := 0
+	parts := make([]cloudinit.Part, len(s.cloudInitFiles))
+	for idx, file := range s.cloudInitFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("read cloud init file: %w", err)
+		}
+
+		parts[idx] = cloudinit.Part{
+			Name:     strings.TrimSuffix(filepath.Base(file), ".tmpl"),
+			Content:  content,
+			Template: strings.HasSuffix(file, ".tmpl"),
+		}
+
+		if parts[idx].Template {
+			templates++
+		}
+	}
+
+	if len(vars) != 0 && templates == 0 {
+		return "", fmt.Errorf("--cloud-init-var requires a cloud init template ending in .tmpl")
+	}
+
+	if len(parts) == 0 {
+		return "", nil
+	}
+
+	cloudInit, err := cloudinit.Build(parts, cloudinit.TemplateData{Name: s.name, Location: location, Vars: vars}, viper.GetInt(commands.KeyCloudInitMaxSize))
+	if err != nil {
+		return "", fmt.Errorf("cloud init: %w", err)
+	}
+
+	return cloudInit, nil
+}
+
 func (s *serverCreateCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	return nil, cobra.ShellCompDirectiveNoFileComp
 }
@@ -250,13 +302,23 @@ func (s *serverCreateCommand) Build(app commands.Application) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create new server",
-		Long:  "Creates a new compute server.",
+		Long: commands.FormatHelp(`
+			Creates a new compute server.
+
+			The files given by --cloud-init must be cloud config yaml starting with #cloud-config, mime multipart user data
+			or another format supported by cloud-init, like scripts starting with #!. Multiple files are combined into a
+			mime multipart message. Files ending in .tmpl are rendered as Go template with the fields .Name, .Location and
+			.Vars of the server before validation.
+		`),
 		Example: commands.FormatExamples(fmt.Sprintf(`
       # Create a new ubuntu server
       %[1]s compute server create --name my-server --location ALP1 --image linux-ubuntu-20.04-lts --product b1.4x8 --key-pair my-keypair
       
       # Create a new windows server
       %[1]s compute server create --name my-server --location ALP1 --image microsoft-windows-server-2019 --product b1.2x8
+
+      # Create a server using a cloud config template and a script
+      %[1]s compute server create ... --cloud-init base.yaml.tmpl --cloud-init setup.sh --cloud-init-var stage=prod
 		`, app.Name)), // TODO select correct image names
 		ValidArgsFunction: s.CompleteArg,
 		RunE:              s.Run,
@@ -270,8 +332,10 @@ func (s *serverCreateCommand) Build(app commands.Application) *cobra.Command {
 	cmd.Flags().IPVar(&s.privateIP, "private-ip", nil, "ip address of the server in the selected network")
 	cmd.Flags().StringVar(&s.keyPair, "key-pair", "", "ssh key-pair for connecting to the server (required if image is linux)")
 	cmd.Flags().StringVar(&s.password, "windows-password", "", "password for the windows admin user  (required if image is windows)")
-	cmd.Flags().StringVar(&s.cloudInitFile, "cloud-init", "", "cloud init script to customize creation of the server")
+	cmd.Flags().StringArrayVar(&s.cloudInitFiles, "cloud-init", nil, "cloud init file to customize creation of the server, multiple files are combined into a mime multipart message")
+	cmd.Flags().StringArrayVar(&s.cloudInitVars, "cloud-init-var", nil, "variable available as {{ .Vars.KEY }} in cloud init templates ending in .tmpl, given as KEY=VALUE")
 	cmd.Flags().BoolVar(&s.attachExternalIP, "attach-external-ip", true, "whether to attach an elastic ip to the server")
+	cmd.Flags().BoolVar(&s.noWait, "no-wait", false, "print the order reference instead of waiting for the server to be created")
 
 	_ = cmd.MarkFlagRequired("name")
 	_ = cmd.MarkFlagRequired("location")
@@ -329,6 +393,7 @@ func (s *serverUpdateCommand) Build(app commands.Application) *cobra.Command {
 
 type serverUpgradeCommand struct {
 	product string
+	noWait  bool
 }
 
 func (s *serverUpgradeCommand) Run(cmd *cobra.Command, args []string) error {
@@ -358,6 +423,10 @@ func (s *serverUpgradeCommand) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("upgrade server: %w", err)
 	}
 
+	if s.noWait {
+		return commands.PrintOrdering(ordering)
+	}
+
 	order, err := commands.WaitForOrder(cmd.Context(), "Upgrading server", ordering)
 	if err != nil {
 		return fmt.Errorf("wait for order: %w", err)
@@ -390,6 +459,7 @@ func (s *serverUpgradeCommand) Build(app commands.Application) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&s.product, "product", "", "product to use for the new server")
+	cmd.Flags().BoolVar(&s.noWait, "no-wait", false, "print the order reference instead of waiting for the server to be upgraded")
 
 	_ = cmd.MarkFlagRequired("product")
 
@@ -399,48 +469,54 @@ func (s *serverUpgradeCommand) Build(app commands.Application) *cobra.Command {
 type serverDeleteCommand struct {
 	force      bool
 	detachOnly bool
+	bulk       commands.BulkOptions
 }
 
 func (s *serverDeleteCommand) Run(cmd *cobra.Command, args []string) error {
-	server, err := findServer(cmd.Context(), args[0])
+	service := compute.NewServerService(commands.Config.Client)
+
+	servers, err := service.List(cmd.Context())
 	if err != nil {
-		return err
+		return fmt.Errorf("fetch servers: %w", err)
 	}
 
-	if !s.force && !commands.ConfirmDeletion("server", server) {
-		commands.Stderr.Println("aborted.")
-		return nil
+	servers, err = commands.SelectBulk(servers, args, s.bulk)
+	if err != nil {
+		return fmt.Errorf("find server: %w", err)
 	}
 
-	err = compute.NewServerService(commands.Config.Client).Delete(cmd.Context(), server.ID, !s.detachOnly)
-	if err != nil {
-		return fmt.Errorf("delete server: %w", err)
+	if !s.force && !commands.ConfirmBulk("delete", "server", servers) {
+		commands.Stderr.Println("aborted.")
+		return nil
 	}
 
-	return nil
+	return commands.RunBulk(cmd.Context(), "delete", "server", servers, s.bulk.Concurrency, func(ctx context.Context, server compute.Server) error {
+		return service.Delete(ctx, server.ID, !s.detachOnly)
+	})
 }
 
 func (s *serverDeleteCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	if len(args) == 0 {
-		return completeServer(cmd.Context(), toComplete)
-	}
-
-	return nil, cobra.ShellCompDirectiveNoFileComp
+	return completeServer(cmd.Context(), toComplete)
 }
 
 func (s *serverDeleteCommand) Build(app commands.Application) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete SERVER",
-		Short: "Delete server",
-		Long:  "Deletes a compute server.",
+		Use:   "delete SERVER...",
+		Short: "Delete servers",
+		Long:  "Deletes one or more compute servers.",
 		Example: commands.FormatExamples(fmt.Sprintf(`
       # Delete a server and elastic ips attached to it
       %[1]s compute server delete my-server
       
       # Delete a server, but keep elastic ips
       %[1]s compute server delete my-server --detach-only
+
+      # Delete multiple servers with a single confirmation
+      %[1]s compute server delete web-1 web-2 web-3
+
+      # Delete all servers matching a term
+      %[1]s compute server delete --all --filter test-
 		`, app.Name)),
-		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: s.CompleteArg,
 		RunE:              s.Run,
 	}
@@ -448,6 +524,62 @@ func (s *serverDeleteCommand) Build(app commands.Application) *cobra.Command {
 	cmd.Flags().BoolVar(&s.force, "force", false, "forces deletion of the server without asking for confirmation")
 	cmd.Flags().BoolVar(&s.detachOnly, "detach-only", false, "specifies whether elastic ips should only be detached without getting deleted")
 
+	commands.AddBulkFlags(cmd, &s.bulk, "server")
+
+	return cmd
+}
+
+type serverWaitCommand struct {
+	opts commands.WaitOptions
+}
+
+func (s *serverWaitCommand) Run(cmd *cobra.Command, args []string) error {
+	server, err := findServer(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	server, err = commands.WaitUntil(cmd.Context(), "server", server.Name, s.opts, func(ctx context.Context) (compute.Server, error) {
+		return compute.NewServerService(commands.Config.Client).Get(ctx, server.ID)
+	})
+	if err != nil || s.opts.Deletion() {
+		return err
+	}
+
+	return commands.PrintStdout(server)
+}
+
+func (s *serverWaitCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeServer(cmd.Context(), toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (s *serverWaitCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait SERVER",
+		Short: "Wait for a server to reach a state",
+		Long: commands.FormatHelp(`
+			Blocks until the server matches the condition given by --for or the timeout expires. The condition is either
+			COLUMN=VALUE, which is compared with the column of the server as shown by the list command, or delete to wait
+			until the server no longer exists.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Wait until the server is running
+      %[1]s compute server wait my-server --for status=running --timeout 10m
+
+      # Wait until the server has been deleted
+      %[1]s compute server wait my-server --for delete
+		`, app.Name)),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: s.CompleteArg,
+		RunE:              s.Run,
+	}
+
+	commands.AddWaitFlags(cmd, &s.opts)
+
 	return cmd
 }
 
@@ -480,59 +612,3 @@ func findServer(ctx context.Context, term string) (compute.Server, error) {
 
 	return server, nil
 }
-
-const specialChars = "~!@#$%^&*_-+=`|\\(){}[]:;\"'<>,.?/"
-
-func checkWindowsPassword(password string) error {
-	// https://docs.microsoft.com/en-us/windows/security/threat-protection/security-policy-settings/password-must-meet-complexity-requirements
-
-	// 1. Passwords may not contain the user's samAccountName (Account Name) value or entire displayName (Full
-	//	  Name value). Both checks aren't case-sensitive.
-	if strings.Contains(strings.ToLower(password), "administrator") {
-		return fmt.Errorf("windows user password cannot contain the username")
-	}
-
-	// 2. The password contains characters from three of the following categories:
-	//    	- Uppercase letters of European languages (A through Z, with diacritic marks, Greek and Cyrillic
-	//   		characters)
-	//   	- Lowercase letters of European languages (a through z, with diacritic marks, Greek and Cyrillic
-	//   		characters)
-	//   	- Base 10 digits (0 through 9)
-	//		- Non-alphanumeric characters (special characters): (~!@#$%^&*_-+=`|\(){}[]:;"'<>,.?/) Currency symbols such
-	//			as the Euro or British Pound aren't counted as special characters for this policy setting.
-	// 		- Any Unicode character that's categorized as an alphabetic character but isn't uppercase or lowercase. This
-	//			group includes Unicode characters from Asian languages. (NOTE: not implemented)
-	hasUppercase := false
-	hasLowercase := false
-	hasDigit := false
-	hasSpecial := false
-	count := 0
-
-	for _, char := range password {
-		if unicode.IsUpper(char) && !hasUppercase {
-			hasUppercase = true
-			count++
-		}
-
-		if unicode.IsLower(char) && !hasLowercase {
-			hasLowercase = true
-			count++
-		}
-
-		if char >= '0' && char <= '9' && !hasDigit {
-			hasDigit = true
-			count++
-		}
-
-		if strings.ContainsRune(specialChars, char) && !hasSpecial {
-			hasSpecial = true
-			count++
-		}
-	}
-
-	if count < 3 {
-		return fmt.Errorf("windows user password must contain at least 3 of the following categories: uppercase letters, lowercase letters, digits, and non-alphanumeric characters")
-	}
-
-	return nil
-}
@@ -3,6 +3,7 @@ package compute
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/spf13/cobra"
 
@@ -114,33 +115,90 @@ func (s *serverActionRunCommand) Build(app commands.Application) *cobra.Command
 	}
 }
 
-type serverActionRunCommandPreset string
-
-func (s serverActionRunCommandPreset) Run(cmd *cobra.Command, args []string) error {
-	return runAction(cmd.Context(), args[0], string(s))
+type serverActionRunCommandPreset struct {
+	action string
+	force  bool
+	bulk   commands.BulkOptions
 }
 
-func (s *serverActionRunCommandPreset) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	if len(args) == 0 {
-		return completeServer(cmd.Context(), toComplete)
+func (s *serverActionRunCommandPreset) Run(cmd *cobra.Command, args []string) error {
+	servers, err := compute.NewServerService(commands.Config.Client).List(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("fetch servers: %w", err)
 	}
 
-	return nil, cobra.ShellCompDirectiveNoFileComp
+	servers, err = commands.SelectBulk(servers, args, s.bulk)
+	if err != nil {
+		return fmt.Errorf("find server: %w", err)
+	}
+
+	if len(servers) > 1 && !s.force && !commands.ConfirmBulk(s.action, "server", servers) {
+		commands.Stderr.Println("aborted.")
+		return nil
+	}
+
+	var mu sync.Mutex
+	results := make(map[int]compute.Server, len(servers))
+
+	bulkErr := commands.RunBulk(cmd.Context(), s.action, "server", servers, s.bulk.Concurrency, func(ctx context.Context, server compute.Server) error {
+		server, err := runServerAction(ctx, server, s.action)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		results[server.ID] = server
+		return nil
+	})
+
+	updated := make([]compute.Server, 0, len(results))
+	for _, server := range servers {
+		if result, ok := results[server.ID]; ok {
+			updated = append(updated, result)
+		}
+	}
+
+	if len(updated) != 0 {
+		if err = commands.PrintStdout(updated); err != nil {
+			return err
+		}
+	}
+
+	return bulkErr
 }
 
-func (s serverActionRunCommandPreset) Build(app commands.Application) *cobra.Command {
-	return &cobra.Command{
-		Use:   string(s) + " SERVER",
-		Short: "Run " + string(s) + " action on the server",
+func (s *serverActionRunCommandPreset) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeServer(cmd.Context(), toComplete)
+}
+
+func (s *serverActionRunCommandPreset) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   s.action + " SERVER...",
+		Short: "Run " + s.action + " action on servers",
 		Long: commands.FormatHelp(fmt.Sprintf(`
-			Runs the %[2]s action on the specified server.
+			Runs the %[2]s action on the specified servers.
 
-			This is a shortcut for "%[1]s compute server action run SERVER %[2]s".
-		`, app.Name, string(s))),
-		Args:              cobra.ExactArgs(1),
+			This is a shortcut for "%[1]s compute server action run SERVER %[2]s". When running the action on multiple
+			servers, a single confirmation is asked for all of them.
+		`, app.Name, s.action)),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Run the %[2]s action on a single server
+      %[1]s compute server %[2]s my-server
+
+      # Run the %[2]s action on all servers matching a term without confirmation
+      %[1]s compute server %[2]s --all --filter test- --force
+		`, app.Name, s.action)),
 		ValidArgsFunction: s.CompleteArg,
 		RunE:              s.Run,
 	}
+
+	cmd.Flags().BoolVar(&s.force, "force", false, "run the action on multiple servers without asking for confirmation")
+
+	commands.AddBulkFlags(cmd, &s.bulk, "server")
+
+	return cmd
 }
 
 func completeServerAction(ctx context.Context, server compute.Server, term string) ([]string, cobra.ShellCompDirective) {
@@ -165,6 +223,15 @@ func runAction(ctx context.Context, serverTerm, actionTerm string) error {
 		return err
 	}
 
+	server, err = runServerAction(ctx, server, actionTerm)
+	if err != nil {
+		return err
+	}
+
+	return commands.PrintStdout(server)
+}
+
+func runServerAction(ctx context.Context, server compute.Server, actionTerm string) (compute.Server, error) {
 	availableActions := make([]compute.ServerAction, len(server.Status.Actions))
 	for i, action := range server.Status.Actions {
 		availableActions[i] = compute.ServerAction(action)
@@ -172,7 +239,7 @@ func runAction(ctx context.Context, serverTerm, actionTerm string) error {
 
 	action, err := filter.FindOne(availableActions, actionTerm)
 	if err != nil {
-		return fmt.Errorf("the selected action does not exist or is currently not possible")
+		return compute.Server{}, fmt.Errorf("the selected action does not exist or is currently not possible")
 	}
 
 	body := compute.ServerRunAction{
@@ -181,8 +248,8 @@ func runAction(ctx context.Context, serverTerm, actionTerm string) error {
 
 	server, err = compute.NewServerActionService(commands.Config.Client).Run(ctx, server.ID, body)
 	if err != nil {
-		return fmt.Errorf("run action: %w", err)
+		return compute.Server{}, fmt.Errorf("run action: %w", err)
 	}
 
-	return commands.PrintStdout(server)
+	return server, nil
 }
@@ -0,0 +1,150 @@
+package compute
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+)
+
+type serverCopyCommand struct {
+	ssh       sshOptions
+	recursive bool
+}
+
+// remotePath is a path on a server given as [USER@]SERVER:PATH.
+type remotePath struct {
+	user   string
+	server string
+	path   string
+}
+
+// parseRemotePath parses an argument of the form [USER@]SERVER:PATH. Like scp, arguments containing a slash before the
+// first colon are local paths, as are windows paths starting with a drive letter like C:\dir\file.
+func parseRemotePath(arg string) (remotePath, bool) {
+	idx := strings.Index(arg, ":")
+	if idx <= 0 || strings.ContainsAny(arg[:idx], `/\`) || isDriveLetter(arg[:idx]) {
+		return remotePath{}, false
+	}
+
+	remote := remotePath{server: arg[:idx], path: arg[idx+1:]}
+	if at := strings.LastIndex(remote.server, "@"); at != -1 {
+		remote.user, remote.server = remote.server[:at], remote.server[at+1:]
+	}
+
+	if remote.server == "" {
+		return remotePath{}, false
+	}
+
+	return remote, true
+}
+
+func isDriveLetter(s string) bool {
+	return len(s) == 1 && (s[0] >= 'a' && s[0] <= 'z' || s[0] >= 'A' && s[0] <= 'Z')
+}
+
+func (s *serverCopyCommand) Run(cmd *cobra.Command, args []string) error {
+	server := ""
+	for _, arg := range args {
+		remote, ok := parseRemotePath(arg)
+		if !ok {
+			continue
+		}
+
+		if server != "" && remote.server != server {
+			return fmt.Errorf("copying between servers is not supported, got %s and %s", server, remote.server)
+		}
+
+		server = remote.server
+	}
+
+	if server == "" {
+		return fmt.Errorf("either the source or the destination must be on a server, e.g. my-server:/path")
+	}
+
+	target, err := resolveSSHTarget(cmd.Context(), server, s.ssh)
+	if err != nil {
+		return err
+	}
+
+	host := target.host
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	scpArgs := target.options()
+	if s.recursive {
+		scpArgs = append(scpArgs, "-r")
+	}
+
+	scpArgs = append(scpArgs, "--")
+	for _, arg := range args {
+		if remote, ok := parseRemotePath(arg); ok {
+			user := remote.user
+			if user == "" {
+				user = target.user
+			}
+
+			arg = fmt.Sprintf("%s@%s:%s", user, host, remote.path)
+		}
+
+		scpArgs = append(scpArgs, arg)
+	}
+
+	// failures of scp are reported by scp itself
+	cmd.SilenceUsage = true
+	return runExternal(cmd.Context(), "scp", scpArgs...)
+}
+
+func (s *serverCopyCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if strings.ContainsAny(toComplete, "/:~") || strings.HasPrefix(toComplete, ".") {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	servers, directive := completeServer(cmd.Context(), toComplete)
+	if directive == cobra.ShellCompDirectiveError || len(servers) == 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	for i, server := range servers {
+		servers[i] = server + ":"
+	}
+
+	return servers, cobra.ShellCompDirectiveNoSpace
+}
+
+func (s *serverCopyCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cp SOURCE... DESTINATION",
+		Short: "Copy files from and to a server",
+		Long: commands.FormatHelp(`
+			Copies files between the local machine and a server using scp, which must be installed. Remote paths are given
+			as [USER@]SERVER:PATH, where the server is resolved like in the ssh command and the user defaults to --user or
+			the default user of the image. Paths with a slash before the colon or a drive letter like C:\dir are local.
+			Either the sources or the destination must be on the server.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Upload a file to the home directory of the default user
+      %[1]s compute server cp ./app.tar.gz my-server:
+
+      # Download a directory recursively
+      %[1]s compute server cp -r my-server:/var/log/nginx ./logs
+
+      # Upload a file as root
+      %[1]s compute server cp ./nginx.conf root@my-server:/etc/nginx/
+
+      # Upload through a bastion server to a server without public ip
+      %[1]s compute server cp ./config.yaml my-server:/etc/app/ --private-ip --jump bastion
+		`, app.Name)),
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: s.CompleteArg,
+		RunE:              s.Run,
+	}
+
+	addSSHFlags(cmd, &s.ssh)
+	cmd.Flags().BoolVarP(&s.recursive, "recursive", "r", false, "copy directories recursively")
+
+	return cmd
+}
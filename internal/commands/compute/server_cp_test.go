@@ -0,0 +1,32 @@
+package compute
+
+import (
+	"testing"
+)
+
+func TestParseRemotePath(t *testing.T) {
+	tests := []struct {
+		arg    string
+		remote bool
+		parsed remotePath
+	}{
+		{arg: "my-server:/var/log", remote: true, parsed: remotePath{server: "my-server", path: "/var/log"}},
+		{arg: "my-server:", remote: true, parsed: remotePath{server: "my-server"}},
+		{arg: "root@my-server:/etc/nginx/", remote: true, parsed: remotePath{user: "root", server: "my-server", path: "/etc/nginx/"}},
+		{arg: "web-1:C:\\Users", remote: true, parsed: remotePath{server: "web-1", path: "C:\\Users"}},
+		{arg: "./app.tar.gz", remote: false},
+		{arg: "./dir:with:colons", remote: false},
+		{arg: ":file", remote: false},
+		{arg: "C:\\dir\\file", remote: false},
+		{arg: "c:/dir/file", remote: false},
+		{arg: "dir\\sub:file", remote: false},
+		{arg: "root@:file", remote: false},
+	}
+
+	for _, test := range tests {
+		parsed, remote := parseRemotePath(test.arg)
+		if remote != test.remote || parsed != test.parsed {
+			t.Errorf("parseRemotePath(%q): expected %+v, %t, got %+v, %t", test.arg, test.parsed, test.remote, parsed, remote)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package compute
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/compute"
+	"github.com/cloudbit-ch/cli/v2/pkg/filter"
+	"github.com/cloudbit-ch/cli/v2/pkg/inventory"
+)
+
+type serverInventoryCommand struct {
+	opts commands.InventoryOptions
+}
+
+func (s *serverInventoryCommand) Run(cmd *cobra.Command, args []string) error {
+	servers, err := compute.NewServerService(commands.Config.Client).List(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("fetch servers: %w", err)
+	}
+
+	if len(s.opts.Filter) != 0 {
+		servers = filter.Find(servers, s.opts.Filter)
+	}
+
+	var elasticIPs []compute.ElasticIP
+
+	hosts := make([]inventory.Host, len(servers))
+	for idx, server := range servers {
+		identity, err := identityFile(server.KeyPair.Name)
+		if err != nil {
+			return err
+		}
+
+		host := inventory.Host{
+			Name:         server.Name,
+			PublicIP:     server.PublicIP(),
+			PrivateIP:    server.PrivateIP(),
+			User:         compute.Image{Image: server.Image}.DefaultUser(),
+			IdentityFile: identity,
+			Location:     server.Location.Name,
+			Product:      server.Product.Name,
+		}
+
+		if len(server.Networks) != 0 {
+			host.Network = server.Networks[0].Name
+		}
+
+		// elastic ips are only fetched if they are needed, since most servers have a public ip on their interfaces
+		if host.PublicIP == "" && elasticIPs == nil {
+			if elasticIPs, err = compute.NewElasticIPService(commands.Config.Client).List(cmd.Context()); err != nil {
+				return fmt.Errorf("fetch elastic ips: %w", err)
+			}
+		}
+
+		for _, elasticIP := range elasticIPs {
+			if host.PublicIP == "" && elasticIP.Attachment.ID == server.ID {
+				host.PublicIP = elasticIP.PublicIP
+			}
+		}
+
+		hosts[idx] = host
+	}
+
+	return commands.PrintInventory(cmd, hosts, s.opts, "servers")
+}
+
+func (s *serverInventoryCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (s *serverInventoryCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Generate an ssh config or ansible inventory",
+		Long: commands.FormatHelp(`
+			Prints all servers as OpenSSH client configuration or Ansible inventory, selected by --format ssh-config
+			(default), ansible-ini or ansible-yaml. The hosts are grouped by location, product, network and name prefix,
+			e.g. web-1 and web-2 are part of the group prefix_web. The user of each host is the default user of its image
+			and the identity file is taken from the identity_files section of the config file.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Include the servers in the ssh config
+      %[1]s compute server inventory > ~/.ssh/config.d/cloudbit
+
+      # Run an ansible playbook against all web servers
+      %[1]s compute server inventory --format ansible-ini > hosts.ini
+      ansible-playbook -i hosts.ini --limit prefix_web site.yml
+		`, app.Name)),
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: s.CompleteArg,
+		RunE:              s.Run,
+	}
+
+	commands.AddInventoryFlags(cmd, &s.opts, "server")
+
+	return cmd
+}
@@ -0,0 +1,262 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/compute"
+)
+
+type sshOptions struct {
+	user      string
+	identity  string
+	privateIP bool
+	jump      string
+}
+
+func addSSHFlags(cmd *cobra.Command, opts *sshOptions) {
+	cmd.Flags().StringVarP(&opts.user, "user", "l", "", "user to log in as, defaults to the default user of the server image")
+	cmd.Flags().StringVarP(&opts.identity, "identity", "i", "", "private key to authenticate with, defaults to the identity file configured for the key pair of the server")
+	cmd.Flags().BoolVar(&opts.privateIP, "private-ip", false, "connect to the private ip of the server instead of its public ip")
+	cmd.Flags().StringVar(&opts.jump, "jump", "", "server to use as bastion host to reach the target server")
+
+	_ = cmd.RegisterFlagCompletionFunc("jump", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeServer(cmd.Context(), toComplete)
+	})
+}
+
+// sshTarget is a resolved server to connect to using the OpenSSH client.
+type sshTarget struct {
+	server   compute.Server
+	host     string
+	user     string
+	identity string
+	jump     *sshTarget
+}
+
+func (t sshTarget) destination() string {
+	return fmt.Sprintf("%s@%s", t.user, t.host)
+}
+
+// options returns the command line options of ssh and scp to reach the target, excluding the destination itself.
+func (t sshTarget) options() []string {
+	var options []string
+	if t.identity != "" {
+		options = append(options, "-i", t.identity)
+	}
+
+	if t.jump != nil {
+		if t.jump.identity == "" {
+			options = append(options, "-J", t.jump.destination())
+		} else {
+			// -J does not allow to pass a separate identity for the bastion host
+			proxy := fmt.Sprintf("ssh -i %s -W %%h:%%p %s", quoteSSHArg(t.jump.identity), t.jump.destination())
+			options = append(options, "-o", "ProxyCommand="+proxy)
+		}
+	}
+
+	return options
+}
+
+// resolveSSHTarget looks up the server and determines the address, user and identity file to connect with.
+func resolveSSHTarget(ctx context.Context, term string, opts sshOptions) (sshTarget, error) {
+	server, err := findServer(ctx, term)
+	if err != nil {
+		return sshTarget{}, err
+	}
+
+	target := sshTarget{
+		server:   server,
+		user:     opts.user,
+		identity: opts.identity,
+	}
+
+	if opts.privateIP {
+		target.host = server.PrivateIP()
+		if target.host == "" {
+			return sshTarget{}, fmt.Errorf("server %s has no private ip", server.Name)
+		}
+	} else {
+		target.host, err = publicAddress(ctx, server)
+		if err != nil {
+			return sshTarget{}, err
+		}
+	}
+
+	if err = target.fill(); err != nil {
+		return sshTarget{}, err
+	}
+
+	if opts.jump != "" {
+		bastion, err := findServer(ctx, opts.jump)
+		if err != nil {
+			return sshTarget{}, fmt.Errorf("jump host: %w", err)
+		}
+
+		jump := &sshTarget{server: bastion}
+		if jump.host, err = publicAddress(ctx, bastion); err != nil {
+			return sshTarget{}, fmt.Errorf("jump host: %w", err)
+		}
+
+		if err = jump.fill(); err != nil {
+			return sshTarget{}, fmt.Errorf("jump host: %w", err)
+		}
+
+		target.jump = jump
+	}
+
+	return target, nil
+}
+
+// fill sets the user and identity of the target to the defaults of the server, unless they are given explicitly.
+func (t *sshTarget) fill() error {
+	if t.user == "" {
+		t.user = compute.Image{Image: t.server.Image}.DefaultUser()
+	}
+
+	if t.identity == "" {
+		var err error
+		if t.identity, err = identityFile(t.server.KeyPair.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publicAddress returns the public ip of the server, falling back to the elastic ips attached to it.
+func publicAddress(ctx context.Context, server compute.Server) (string, error) {
+	if ip := server.PublicIP(); ip != "" {
+		return ip, nil
+	}
+
+	elasticIPs, err := compute.NewElasticIPService(commands.Config.Client).List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("fetch elastic ips: %w", err)
+	}
+
+	for _, elasticIP := range elasticIPs {
+		if elasticIP.Attachment.ID == server.ID {
+			return elasticIP.PublicIP, nil
+		}
+	}
+
+	return "", fmt.Errorf("server %s has no public ip, use --private-ip together with --jump to connect through a bastion server", server.Name)
+}
+
+// identityFile returns the private key configured for the key pair in the identity_files section of the config file.
+// The ssh client falls back to its own configuration if there is none.
+func identityFile(keyPair string) (string, error) {
+	if keyPair == "" {
+		return "", nil
+	}
+
+	// viper stores all keys in lower case
+	path := viper.GetStringMapString(commands.KeyIdentityFiles)[strings.ToLower(keyPair)]
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("resolve identity file: %w", err)
+		}
+
+		path = filepath.Join(home, path[1:])
+	}
+
+	return path, nil
+}
+
+func quoteSSHArg(arg string) string {
+	if !strings.ContainsAny(arg, " \t'\"\\$") {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// runExternal runs the external program attached to the terminal of the cli.
+func runExternal(ctx context.Context, name string, args ...string) error {
+	command := exec.CommandContext(ctx, name, args...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	return command.Run()
+}
+
+type serverSSHCommand struct {
+	ssh sshOptions
+}
+
+func (s *serverSSHCommand) Run(cmd *cobra.Command, args []string) error {
+	var remote []string
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		args, remote = args[:dash], args[dash:]
+	}
+
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly one server, got %d", len(args))
+	}
+
+	target, err := resolveSSHTarget(cmd.Context(), args[0], s.ssh)
+	if err != nil {
+		return err
+	}
+
+	sshArgs := append(target.options(), target.destination())
+	if len(remote) != 0 {
+		sshArgs = append(sshArgs, "--")
+		sshArgs = append(sshArgs, remote...)
+	}
+
+	// failures of ssh are reported by ssh itself
+	cmd.SilenceUsage = true
+	return runExternal(cmd.Context(), "ssh", sshArgs...)
+}
+
+func (s *serverSSHCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeServer(cmd.Context(), toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (s *serverSSHCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ssh SERVER [-- COMMAND...]",
+		Short: "Connect to a server using ssh",
+		Long: commands.FormatHelp(fmt.Sprintf(`
+			Opens an ssh session to the server or runs the command given after -- on it. The OpenSSH client must be
+			installed. The server is reached at its public or elastic ip as the default user of its image.
+
+			The private key used to authenticate is taken from the identity_files section of the config file, which maps
+			the names of key pairs to private key files, e.g. {"identity_files": {"my-keypair": "~/.ssh/id_ed25519"}} in
+			~/.%s/config.json. Without a matching entry, the configuration of the ssh client applies.
+		`, app.Name)),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Open a shell on the server
+      %[1]s compute server ssh my-server
+
+      # Run a command on the server
+      %[1]s compute server ssh my-server -- uptime
+
+      # Connect to a server without public ip through a bastion server
+      %[1]s compute server ssh my-server --private-ip --jump bastion
+		`, app.Name)),
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: s.CompleteArg,
+		RunE:              s.Run,
+	}
+
+	addSSHFlags(cmd, &s.ssh)
+
+	return cmd
+}
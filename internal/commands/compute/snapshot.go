@@ -23,6 +23,7 @@ func SnapshotCommand(app commands.Application) *cobra.Command {
 		&snapshotCreateCommand{},
 		&snapshotUpdateCommand{},
 		&snapshotDeleteCommand{},
+		&snapshotWaitCommand{},
 	)
 
 	return cmd
@@ -157,28 +158,85 @@ func (s *snapshotUpdateCommand) Build(app commands.Application) *cobra.Command {
 
 type snapshotDeleteCommand struct {
 	force bool
+	bulk  commands.BulkOptions
 }
 
 func (s *snapshotDeleteCommand) Run(cmd *cobra.Command, args []string) error {
-	snapshot, err := findSnapshot(cmd.Context(), args[0])
+	service := compute.NewSnapshotService(commands.Config.Client)
+
+	snapshots, err := service.List(cmd.Context())
 	if err != nil {
-		return err
+		return fmt.Errorf("fetch snapshots: %w", err)
 	}
 
-	if !s.force && !commands.ConfirmDeletion("snapshot", snapshot) {
+	snapshots, err = commands.SelectBulk(snapshots, args, s.bulk)
+	if err != nil {
+		return fmt.Errorf("find snapshot: %w", err)
+	}
+
+	if !s.force && !commands.ConfirmBulk("delete", "snapshot", snapshots) {
 		commands.Stderr.Println("aborted.")
 		return nil
 	}
 
-	err = compute.NewSnapshotService(commands.Config.Client).Delete(cmd.Context(), snapshot.ID)
+	return commands.RunBulk(cmd.Context(), "delete", "snapshot", snapshots, s.bulk.Concurrency, func(ctx context.Context, snapshot compute.Snapshot) error {
+		return service.Delete(ctx, snapshot.ID)
+	})
+}
+
+func (s *snapshotDeleteCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeSnapshot(cmd.Context(), toComplete)
+}
+
+func (s *snapshotDeleteCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete SNAPSHOT...",
+		Short: "Delete snapshots",
+		Long:  "Deletes one or more snapshots.",
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Delete multiple snapshots with a single confirmation
+      %[1]s compute snapshot delete before-upgrade after-upgrade
+
+      # Delete all snapshots matching a term without confirmation
+      %[1]s compute snapshot delete --all --filter nightly --force
+		`, app.Name)),
+		ValidArgsFunction: s.CompleteArg,
+		RunE:              s.Run,
+	}
+
+	cmd.Flags().BoolVar(&s.force, "force", false, "force the deletion of the snapshot without asking for confirmation")
+
+	commands.AddBulkFlags(cmd, &s.bulk, "snapshot")
+
+	return cmd
+}
+
+type snapshotWaitCommand struct {
+	opts commands.WaitOptions
+}
+
+func (s *snapshotWaitCommand) Run(cmd *cobra.Command, args []string) error {
+	snapshot, err := findSnapshot(cmd.Context(), args[0])
 	if err != nil {
-		return fmt.Errorf("delete snapshot: %w", err)
+		return err
+	}
+
+	snapshot, err = commands.WaitUntil(cmd.Context(), "snapshot", snapshot.Name, s.opts, func(ctx context.Context) (compute.Snapshot, error) {
+		snapshots, err := compute.NewSnapshotService(commands.Config.Client).List(ctx)
+		if err != nil {
+			return compute.Snapshot{}, err
+		}
+
+		return commands.FindByID(snapshots, snapshot.ID, func(s compute.Snapshot) int { return s.ID })
+	})
+	if err != nil || s.opts.Deletion() {
+		return err
 	}
 
-	return nil
+	return commands.PrintStdout(snapshot)
 }
 
-func (s *snapshotDeleteCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+func (s *snapshotWaitCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	if len(args) == 0 {
 		return completeSnapshot(cmd.Context(), toComplete)
 	}
@@ -186,17 +244,28 @@ func (s *snapshotDeleteCommand) CompleteArg(cmd *cobra.Command, args []string, t
 	return nil, cobra.ShellCompDirectiveNoFileComp
 }
 
-func (s *snapshotDeleteCommand) Build(app commands.Application) *cobra.Command {
+func (s *snapshotWaitCommand) Build(app commands.Application) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:               "delete SNAPSHOT",
-		Short:             "Delete a snapshot",
-		Long:              "Deletes a snapshot.",
+		Use:   "wait SNAPSHOT",
+		Short: "Wait for a snapshot to reach a state",
+		Long: commands.FormatHelp(`
+			Blocks until the snapshot matches the condition given by --for or the timeout expires. The condition is either
+			COLUMN=VALUE, which is compared with the column of the snapshot as shown by the list command, or delete to wait
+			until the snapshot no longer exists.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Wait until the snapshot is available
+      %[1]s compute snapshot wait my-snapshot --for status=available --timeout 10m
+
+      # Wait until the snapshot has been deleted
+      %[1]s compute snapshot wait my-snapshot --for delete
+		`, app.Name)),
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: s.CompleteArg,
 		RunE:              s.Run,
 	}
 
-	cmd.Flags().BoolVar(&s.force, "force", false, "force the deletion of the snapshot without asking for confirmation")
+	commands.AddWaitFlags(cmd, &s.opts)
 
 	return cmd
 }
@@ -27,6 +27,7 @@ func VolumeCommand(app commands.Application) *cobra.Command {
 		&volumeRevertCommand{},
 		&volumeExpandCommand{},
 		&volumeDeleteCommand{},
+		&volumeWaitCommand{},
 	)
 
 	return cmd
@@ -334,7 +335,8 @@ func (v *volumeRevertCommand) Build(app commands.Application) *cobra.Command {
 }
 
 type volumeExpandCommand struct {
-	size int
+	size   int
+	noWait bool
 }
 
 func (v *volumeExpandCommand) Run(cmd *cobra.Command, args []string) error {
@@ -347,11 +349,35 @@ func (v *volumeExpandCommand) Run(cmd *cobra.Command, args []string) error {
 		Size: v.size,
 	}
 
-	volume, err = compute.NewVolumeService(commands.Config.Client).Expand(cmd.Context(), volume.ID, data)
+	service := compute.NewVolumeService(commands.Config.Client)
+
+	volume, err = service.Expand(cmd.Context(), volume.ID, data)
 	if err != nil {
 		return fmt.Errorf("expand volume: %w", err)
 	}
 
+	if v.noWait {
+		return commands.PrintStdout(volume)
+	}
+
+	err = commands.WaitFor(cmd.Context(), "Expanding volume", func(ctx context.Context) (bool, error) {
+		volume, err = service.Get(ctx, volume.ID)
+		if err != nil {
+			return false, fmt.Errorf("fetch volume: %w", err)
+		}
+
+		if volume.Status.ID == compute.VolumeStatusError {
+			return false, fmt.Errorf("expansion of volume %s failed", volume.Name)
+		}
+
+		// the status may not have changed to working yet, so the volume is only done once it has the new size
+		done := volume.Status.ID == compute.VolumeStatusAvailable || volume.Status.ID == compute.VolumeStatusInUse
+		return done && volume.Size == v.size, nil
+	})
+	if err != nil {
+		return err
+	}
+
 	return commands.PrintStdout(volume)
 }
 
@@ -374,6 +400,7 @@ func (v *volumeExpandCommand) Build(app commands.Application) *cobra.Command {
 	}
 
 	cmd.Flags().IntVar(&v.size, "size", 0, "size of the volume in GiB")
+	cmd.Flags().BoolVar(&v.noWait, "no-wait", false, "print the volume instead of waiting for the volume to be expanded")
 
 	_ = cmd.MarkFlagRequired("size")
 
@@ -382,28 +409,85 @@ func (v *volumeExpandCommand) Build(app commands.Application) *cobra.Command {
 
 type volumeDeleteCommand struct {
 	force bool
+	bulk  commands.BulkOptions
 }
 
 func (v *volumeDeleteCommand) Run(cmd *cobra.Command, args []string) error {
-	volume, err := findVolume(cmd.Context(), args[0])
+	service := compute.NewVolumeService(commands.Config.Client)
+
+	volumes, err := service.List(cmd.Context())
 	if err != nil {
-		return err
+		return fmt.Errorf("fetch volumes: %w", err)
 	}
 
-	if !v.force && !commands.ConfirmDeletion("volume", volume) {
+	volumes, err = commands.SelectBulk(volumes, args, v.bulk)
+	if err != nil {
+		return fmt.Errorf("find volume: %w", err)
+	}
+
+	if !v.force && !commands.ConfirmBulk("delete", "volume", volumes) {
 		commands.Stderr.Println("aborted.")
 		return nil
 	}
 
-	err = compute.NewVolumeService(commands.Config.Client).Delete(cmd.Context(), volume.ID)
+	return commands.RunBulk(cmd.Context(), "delete", "volume", volumes, v.bulk.Concurrency, func(ctx context.Context, volume compute.Volume) error {
+		return service.Delete(ctx, volume.ID)
+	})
+}
+
+func (v *volumeDeleteCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeVolume(cmd.Context(), toComplete, nil)
+}
+
+func (v *volumeDeleteCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete VOLUME...",
+		Short: "Delete volumes",
+		Long:  "Deletes one or more volumes.",
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Delete multiple volumes with a single confirmation
+      %[1]s compute volume delete data-1 data-2
+
+      # Delete all volumes matching a term without confirmation
+      %[1]s compute volume delete --all --filter scratch --force
+		`, app.Name)),
+		ValidArgsFunction: v.CompleteArg,
+		RunE:              v.Run,
+	}
+
+	cmd.Flags().BoolVar(&v.force, "force", false, "force the deletion of the volume without asking for confirmation")
+
+	commands.AddBulkFlags(cmd, &v.bulk, "volume")
+
+	return cmd
+}
+
+type volumeWaitCommand struct {
+	opts commands.WaitOptions
+}
+
+func (v *volumeWaitCommand) Run(cmd *cobra.Command, args []string) error {
+	volume, err := findVolume(cmd.Context(), args[0])
 	if err != nil {
-		return fmt.Errorf("delete volume: %w", err)
+		return err
 	}
 
-	return nil
+	volume, err = commands.WaitUntil(cmd.Context(), "volume", volume.Name, v.opts, func(ctx context.Context) (compute.Volume, error) {
+		volumes, err := compute.NewVolumeService(commands.Config.Client).List(ctx)
+		if err != nil {
+			return compute.Volume{}, err
+		}
+
+		return commands.FindByID(volumes, volume.ID, func(v compute.Volume) int { return v.ID })
+	})
+	if err != nil || v.opts.Deletion() {
+		return err
+	}
+
+	return commands.PrintStdout(volume)
 }
 
-func (v *volumeDeleteCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+func (v *volumeWaitCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	if len(args) == 0 {
 		return completeVolume(cmd.Context(), toComplete, nil)
 	}
@@ -411,17 +495,28 @@ func (v *volumeDeleteCommand) CompleteArg(cmd *cobra.Command, args []string, toC
 	return nil, cobra.ShellCompDirectiveNoFileComp
 }
 
-func (v *volumeDeleteCommand) Build(app commands.Application) *cobra.Command {
+func (v *volumeWaitCommand) Build(app commands.Application) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:               "delete VOLUME",
-		Short:             "Delete a volume",
-		Long:              "Deletes a volume.",
+		Use:   "wait VOLUME",
+		Short: "Wait for a volume to reach a state",
+		Long: commands.FormatHelp(`
+			Blocks until the volume matches the condition given by --for or the timeout expires. The condition is either
+			COLUMN=VALUE, which is compared with the column of the volume as shown by the list command, or delete to wait
+			until the volume no longer exists.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Wait until the volume is available
+      %[1]s compute volume wait my-volume --for status=available --timeout 10m
+
+      # Wait until the volume has been deleted
+      %[1]s compute volume wait my-volume --for delete
+		`, app.Name)),
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: v.CompleteArg,
 		RunE:              v.Run,
 	}
 
-	cmd.Flags().BoolVar(&v.force, "force", false, "force the deletion of the volume without asking for confirmation")
+	commands.AddWaitFlags(cmd, &v.opts)
 
 	return cmd
 }
@@ -1,20 +1,18 @@
 package commands
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/flowswiss/goclient"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"golang.org/x/term"
-
-	"github.com/cloudbit-ch/cli/v2/pkg/console"
 )
 
 const (
@@ -23,17 +21,32 @@ const (
 	FlagDump     = "dump"
 	FlagDryRun   = "dry-run"
 	FlagFormat   = "format"
+	FlagProfile  = "profile"
+	FlagLocation = "location"
+	FlagRetries  = "retries"
+	FlagTimeout  = "timeout"
+
+	FlagTraceFile = "trace-file"
+
+	FlagColumns   = "columns"
+	FlagNoHeaders = "no-headers"
+	FlagWrap      = "wrap"
+	FlagTruncate  = "truncate"
 )
 
 const (
-	FormatJSON  = "json"
-	FormatTable = "table"
-	FormatCSV   = "csv"
+	KeyCurrentProfile   = "current_profile"
+	KeyProfiles         = "profiles"
+	KeyKeyring          = "keyring"
+	KeyPassphrase       = "keyring_passphrase"
+	KeyIdentityFiles    = "identity_files"
+	KeyCloudInitMaxSize = "cloud_init_max_size"
 )
 
 var (
-	configFile string
-	configDir  string
+	configFile    string
+	configDir     string
+	activeProfile string
 
 	baseFlagSet *pflag.FlagSet
 )
@@ -43,66 +56,85 @@ var Config config
 type config struct {
 	Client   goclient.Client
 	Terminal bool
+
+	// HTTPClient sends requests to services besides the api, like the S3 api of the object storage.
+	HTTPClient *http.Client
 }
 
-func Print(out console.Writer, val interface{}) error {
-	format := viper.GetString(FlagFormat)
-	if format == FormatJSON {
-		return json.NewEncoder(out).Encode(val)
+func loadConfig(app Application, cmd *cobra.Command) error {
+	if err := initViper(app); err != nil {
+		return err
 	}
 
-	separator := "   "
-	pretty := true
+	if _, ok := cmd.Annotations[AnnotationFormats]; ok {
+		if _, err := CommandFormat(cmd); err != nil {
+			return err
+		}
+	} else if err := checkFormat(viper.GetString(FlagFormat)); err != nil {
+		return err
+	}
 
-	if format == FormatCSV {
-		separator = ","
-		pretty = false
+	if viper.GetBool(FlagWrap) && viper.GetBool(FlagTruncate) {
+		return fmt.Errorf("the flags --%s and --%s are mutually exclusive", FlagWrap, FlagTruncate)
 	}
 
-	table := console.Table{}
+	authenticated := requiresAuthentication(cmd)
 
-	err := table.Insert(val)
+	if err := selectProfile(authenticated); err != nil {
+		return err
+	}
+
+	cfg, err := buildConfig(app, authenticated)
 	if err != nil {
 		return err
 	}
 
-	table.Format(out, separator, pretty)
+	Config = cfg
+
+	if authenticated {
+		applyProfileDefaults(cmd)
+	}
 
-	Stderr.Printf("Found a total of %d items\n", len(table.Rows))
 	return nil
 }
 
-func PrintStdout(val interface{}) error {
-	return Print(Stdout, val)
-}
+func buildConfig(app Application, requireToken bool) (config, error) {
+	endpoint := viper.GetString(FlagEndpoint)
+	token := viper.GetString(FlagToken)
 
-func loadConfig(app Application) {
-	if err := initViper(app); err != nil {
-		Stderr.Errorf("%v\n", err)
-		os.Exit(1)
+	if len(token) == 0 && requireToken && viper.GetBool(KeyKeyring) {
+		var err error
+		if token, err = keyringToken(); err != nil {
+			return config{}, err
+		}
 	}
 
-	cfg, err := buildConfig(app)
-	if err != nil {
-		Stderr.Errorf("%v\n", err)
-		os.Exit(1)
+	if len(token) == 0 && requireToken {
+		return config{}, fmt.Errorf("missing authentication token, use \"%s login\" to authenticate", app.Name)
 	}
 
-	Config = cfg
+	return config{
+		Client:     NewClient(app, endpoint, token),
+		Terminal:   term.IsTerminal(int(os.Stdin.Fd())),
+		HTTPClient: NewHTTPClient(app),
+	}, nil
 }
 
-func buildConfig(app Application) (config, error) {
-	endpoint := viper.GetString(FlagEndpoint)
-	token := viper.GetString(FlagToken)
-
-	if len(token) == 0 {
-		return config{}, fmt.Errorf("missing authentication token")
-	}
-
+// NewClient creates an api client for the given endpoint and token, which honors the global flags.
+func NewClient(app Application, endpoint string, token string) goclient.Client {
 	opts := []goclient.Option{
 		goclient.WithBase(endpoint),
-		goclient.WithToken(token),
-		goclient.WithUserAgent(fmt.Sprintf("%s-cli/%s", app.Name, app.Version)),
+	}
+
+	// the trace and dump are added before the token, so they see the authorization header and are able to redact it
+	if path := viper.GetString(FlagTraceFile); path != "" {
+		recorder := startTrace(app, path)
+		opts = append(opts, goclient.WithHTTPClientOption(func(client *http.Client) {
+			client.Transport = traceTransport{
+				delegate: client.Transport,
+				recorder: recorder,
+			}
+		}))
 	}
 
 	if viper.GetBool(FlagDump) {
@@ -113,6 +145,19 @@ func buildConfig(app Application) (config, error) {
 		}))
 	}
 
+	opts = append(opts,
+		goclient.WithToken(token),
+		goclient.WithUserAgent(fmt.Sprintf("%s-cli/%s", app.Name, app.Version)),
+	)
+
+	opts = append(opts, goclient.WithHTTPClientOption(func(client *http.Client) {
+		client.Transport = retryTransport{
+			delegate: client.Transport,
+			retries:  viper.GetInt(FlagRetries),
+		}
+	}))
+
+	// the dry run is added last, so aborted requests are neither retried nor dumped
 	if viper.GetBool(FlagDryRun) {
 		opts = append(opts, goclient.WithHTTPClientOption(func(client *http.Client) {
 			client.Transport = dryRunTransport{
@@ -121,19 +166,58 @@ func buildConfig(app Application) (config, error) {
 		}))
 	}
 
-	return config{
-		Client:   goclient.NewClient(opts...),
-		Terminal: term.IsTerminal(int(os.Stdin.Fd())),
-	}, nil
+	return goclient.NewClient(opts...)
+}
+
+// NewHTTPClient creates a plain http client, which honors the global flags the same way as the api client. Requests
+// are expected to carry their own authentication.
+func NewHTTPClient(app Application) *http.Client {
+	var transport http.RoundTripper = http.DefaultTransport
+
+	if path := viper.GetString(FlagTraceFile); path != "" {
+		transport = traceTransport{
+			delegate:  transport,
+			recorder:  startTrace(app, path),
+			bodyLimit: maxRecordedBodySize,
+		}
+	}
+
+	if viper.GetBool(FlagDump) {
+		transport = dumpRequestTransport{
+			delegate:  transport,
+			bodyLimit: maxRecordedBodySize,
+		}
+	}
+
+	transport = retryTransport{
+		delegate: transport,
+		retries:  viper.GetInt(FlagRetries),
+	}
+
+	if viper.GetBool(FlagDryRun) {
+		transport = dryRunTransport{
+			delegate: transport,
+		}
+	}
+
+	return &http.Client{Transport: transport}
 }
 
 func setupFlags(app Application, root *cobra.Command) {
 	baseFlagSet = pflag.NewFlagSet("base", pflag.ContinueOnError)
 	baseFlagSet.String(FlagEndpoint, app.Endpoint, "base endpoint to use for all api requests")
 	baseFlagSet.String(FlagToken, "", "authentication token to use for all api requests")
-	baseFlagSet.Bool(FlagDump, false, "dump all requests and responses to stderr")
-	baseFlagSet.Bool(FlagDryRun, false, "dry run mode, print requests to stdout instead of sending them to the server")
-	baseFlagSet.StringP(FlagFormat, "o", "table", fmt.Sprintf("output format to use. allowed values: %s, %s or %s", FormatTable, FormatCSV, FormatJSON))
+	baseFlagSet.Bool(FlagDump, false, "dump all requests and responses to stderr with credentials and secrets redacted")
+	baseFlagSet.String(FlagTraceFile, "", "write all requests and responses including timings to the file, as http archive if it ends in .har or as json lines otherwise")
+	baseFlagSet.Bool(FlagDryRun, false, "print the resolved payload of requests modifying resources as json to stdout instead of sending them to the server")
+	baseFlagSet.Int(FlagRetries, 3, "number of times a request is retried after a temporary failure or rate limiting")
+	baseFlagSet.Duration(FlagTimeout, 0, fmt.Sprintf("maximum duration of the command including retries and waiting, e.g. 10m (default no limit, but waiting for at most %s)", DefaultWaitTimeout))
+	baseFlagSet.StringP(FlagFormat, "o", FormatTable, fmt.Sprintf("output format to use. allowed values: %s, %s, %s, %s, %s, %s, %s=TEMPLATE, %s=TEMPLATE or %s=SPEC", FormatTable, FormatWide, FormatCSV, FormatJSON, FormatYAML, FormatName, FormatJSONPath, FormatGoTemplate, FormatCustomColumns))
+	baseFlagSet.String(FlagProfile, "", "profile of the config file to use instead of the current profile")
+	baseFlagSet.String(FlagColumns, "", "comma separated columns to show in the table and csv output, e.g. 'id,name,public ip'")
+	baseFlagSet.Bool(FlagNoHeaders, false, "omit the header line of the table and csv output")
+	baseFlagSet.Bool(FlagWrap, false, "wrap table cells which do not fit into the terminal width onto multiple lines")
+	baseFlagSet.Bool(FlagTruncate, false, "truncate table cells which do not fit into the terminal width (default if writing to a terminal)")
 
 	_ = baseFlagSet.MarkHidden(FlagToken)
 
@@ -181,3 +265,60 @@ func initViper(app Application) error {
 
 	return nil
 }
+
+// selectProfile merges the settings of the selected profile into the config. Since flags and environment variables
+// take precedence over the config file, they still override the settings of the profile. A profile which does not exist
+// yet is only accepted if strict is false, e.g. to create it during login.
+func selectProfile(strict bool) error {
+	explicit := true
+
+	activeProfile = strings.ToLower(viper.GetString(FlagProfile))
+	if activeProfile == "" {
+		activeProfile = strings.ToLower(viper.GetString(KeyCurrentProfile))
+		explicit = false
+	}
+
+	if activeProfile == "" {
+		return nil
+	}
+
+	settings := viper.GetStringMap(KeyProfiles + "." + activeProfile)
+	if len(settings) == 0 {
+		if explicit && strict {
+			return fmt.Errorf("profile %q does not exist", activeProfile)
+		}
+
+		return nil
+	}
+
+	// credentials are never inherited from the top level of the config file, since they belong to another organization
+	for key, empty := range map[string]interface{}{FlagToken: "", KeyKeyring: false} {
+		if _, ok := settings[key]; !ok {
+			settings[key] = empty
+		}
+	}
+
+	return viper.MergeConfigMap(settings)
+}
+
+// applyProfileDefaults fills unset flags of the command with the defaults of the active profile.
+func applyProfileDefaults(cmd *cobra.Command) {
+	location := viper.GetString(FlagLocation)
+	if location == "" {
+		return
+	}
+
+	if flag := cmd.Flags().Lookup(FlagLocation); flag != nil && !flag.Changed {
+		_ = cmd.Flags().Set(FlagLocation, location)
+	}
+}
+
+// ActiveProfile returns the name of the profile in use or an empty string if no profile is selected.
+func ActiveProfile() string {
+	return activeProfile
+}
+
+// ConfigDir returns the directory holding the configuration, the keyring and other state of the application.
+func ConfigDir() string {
+	return configDir
+}
@@ -0,0 +1,330 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+)
+
+func Module(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration",
+		Long: commands.FormatHelp(fmt.Sprintf(`
+			Manages the profiles (contexts) stored in the config file.
+
+			Every profile has its own authentication token, endpoint and default location. The current profile is used
+			by all commands unless another profile is selected using the --profile flag or the %s_PROFILE environment
+			variable.
+		`, strings.ToUpper(app.Name))),
+		Annotations: map[string]string{
+			commands.AnnotationNoAuthentication: "true",
+		},
+	}
+
+	commands.Add(app, cmd,
+		&useContextCommand{},
+		&getContextsCommand{},
+		&setContextCommand{},
+		&deleteContextCommand{},
+	)
+
+	return cmd
+}
+
+type context struct {
+	Current  bool   `json:"current"`
+	Name     string `json:"name"`
+	Endpoint string `json:"endpoint"`
+	Location string `json:"location"`
+}
+
+func (c context) String() string {
+	return c.Name
+}
+
+func (c context) Keys() []string {
+	return []string{c.Name}
+}
+
+func (c context) Columns() []string {
+	return []string{"current", "name", "endpoint", "location"}
+}
+
+func (c context) Values() map[string]interface{} {
+	current := ""
+	if c.Current {
+		current = "*"
+	}
+
+	return map[string]interface{}{
+		"current":  current,
+		"name":     c.Name,
+		"endpoint": c.Endpoint,
+		"location": c.Location,
+	}
+}
+
+type useContextCommand struct{}
+
+func (u *useContextCommand) Run(cmd *cobra.Command, args []string) error {
+	file, err := commands.LoadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	_, ok, err := file.Profile(args[0])
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("profile %q does not exist", args[0])
+	}
+
+	file.SetCurrentProfile(args[0])
+	if err = file.Save(); err != nil {
+		return err
+	}
+
+	commands.Stderr.Printf("switched to profile %q.\n", strings.ToLower(args[0]))
+	return nil
+}
+
+func (u *useContextCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeProfile(toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (u *useContextCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "use-context PROFILE",
+		Aliases:           []string{"use-profile", "use"},
+		Short:             "Switch the current profile",
+		Long:              "Sets the profile used by all commands if no other profile is selected explicitly.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: u.CompleteArg,
+		RunE:              u.Run,
+	}
+
+	return cmd
+}
+
+type getContextsCommand struct{}
+
+func (g *getContextsCommand) Run(cmd *cobra.Command, args []string) error {
+	file, err := commands.LoadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	profiles, err := file.Profiles()
+	if err != nil {
+		return err
+	}
+
+	current := file.CurrentProfile()
+
+	items := make([]context, 0, len(profiles))
+	for name, profile := range profiles {
+		items = append(items, context{
+			Current:  name == current,
+			Name:     name,
+			Endpoint: profile.Endpoint,
+			Location: profile.Location,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	return commands.PrintStdout(items)
+}
+
+func (g *getContextsCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (g *getContextsCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "get-contexts",
+		Aliases:           []string{"get-profiles", "contexts", "profiles"},
+		Short:             "List all profiles",
+		Long:              "Lists all profiles of the config file. The current profile is marked with an asterisk.",
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: g.CompleteArg,
+		RunE:              g.Run,
+	}
+
+	return cmd
+}
+
+type setContextCommand struct {
+	token    string
+	endpoint string
+	location string
+	use      bool
+}
+
+func (s *setContextCommand) Run(cmd *cobra.Command, args []string) error {
+	file, err := commands.LoadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	profile, _, err := file.Profile(args[0])
+	if err != nil {
+		return err
+	}
+
+	if cmd.Flags().Changed("token") {
+		profile.Token = s.token
+	}
+
+	if cmd.Flags().Changed("endpoint") {
+		profile.Endpoint = s.endpoint
+	}
+
+	if cmd.Flags().Changed("location") {
+		profile.Location = s.location
+	}
+
+	if err = file.SetProfile(args[0], profile); err != nil {
+		return err
+	}
+
+	if s.use || file.CurrentProfile() == "" {
+		file.SetCurrentProfile(args[0])
+	}
+
+	if err = file.Save(); err != nil {
+		return err
+	}
+
+	commands.Stderr.Printf("saved profile %q.\n", strings.ToLower(args[0]))
+	return nil
+}
+
+func (s *setContextCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeProfile(toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (s *setContextCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "set-context PROFILE",
+		Aliases: []string{"set-profile"},
+		Short:   "Create or update a profile",
+		Long: commands.FormatHelp(`
+			Creates a new profile or updates an existing one. Only the settings passed as flags are changed.
+
+			The first profile created automatically becomes the current profile.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Create a profile for the staging organization
+      %[1]s config set-context staging --token "$STAGING_TOKEN" --location ALP1
+
+      # Change the default location of the production profile and switch to it
+      %[1]s config set-context production --location ZRH1 --use
+		`, app.Name)),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: s.CompleteArg,
+		RunE:              s.Run,
+	}
+
+	// these flags shadow the global flags of the same name, so they only change the stored profile
+	cmd.Flags().StringVar(&s.token, "token", "", "authentication token of the profile")
+	cmd.Flags().StringVar(&s.endpoint, "endpoint", "", "base endpoint of the profile")
+	cmd.Flags().StringVar(&s.location, "location", "", "default location of the profile, used by all commands with a location flag")
+	cmd.Flags().BoolVar(&s.use, "use", false, "switch to the profile after saving it")
+
+	return cmd
+}
+
+type deleteContextCommand struct {
+	force bool
+}
+
+func (d *deleteContextCommand) Run(cmd *cobra.Command, args []string) error {
+	file, err := commands.LoadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	_, ok, err := file.Profile(args[0])
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("profile %q does not exist", args[0])
+	}
+
+	if !d.force && !commands.Confirm(fmt.Sprintf("Are you sure you want to delete the profile %q?", strings.ToLower(args[0]))) {
+		commands.Stderr.Println("aborted.")
+		return nil
+	}
+
+	if err = file.DeleteProfile(args[0]); err != nil {
+		return err
+	}
+
+	return file.Save()
+}
+
+func (d *deleteContextCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeProfile(toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (d *deleteContextCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "delete-context PROFILE",
+		Aliases:           []string{"delete-profile"},
+		Short:             "Delete a profile",
+		Long:              "Deletes a profile from the config file.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: d.CompleteArg,
+		RunE:              d.Run,
+	}
+
+	cmd.Flags().BoolVar(&d.force, "force", false, "force the deletion of the profile without asking for confirmation")
+
+	return cmd
+}
+
+func completeProfile(term string) ([]string, cobra.ShellCompDirective) {
+	file, err := commands.LoadConfigFile()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	profiles, err := file.Profiles()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		if strings.HasPrefix(name, strings.ToLower(term)) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,116 @@
+package dev
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/apitest"
+)
+
+func Module(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Tools for developing against the API",
+		Long:  "Tools for developing scripts and automation against the API without touching a real organization.",
+		Annotations: map[string]string{
+			commands.AnnotationNoAuthentication: "true",
+		},
+	}
+
+	commands.Add(app, cmd,
+		&mockServerCommand{},
+	)
+
+	return cmd
+}
+
+type mockServerCommand struct {
+	listen      string
+	token       string
+	orderDelay  time.Duration
+	actionDelay time.Duration
+}
+
+func (m *mockServerCommand) Run(cmd *cobra.Command, args []string) error {
+	listener, err := net.Listen("tcp", m.listen)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", m.listen, err)
+	}
+
+	server := &http.Server{
+		Handler: apitest.NewServer(apitest.Options{
+			Token:       m.token,
+			OrderDelay:  m.orderDelay,
+			ActionDelay: m.actionDelay,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	commands.Stderr.Printf("Serving the mock api on http://%s/, stop it using ctrl+c.\n", listener.Addr())
+	commands.Stderr.Printf("Use it by passing --endpoint http://%s/ to the commands.\n", listener.Addr())
+
+	err = server.Serve(listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+func (m *mockServerCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (m *mockServerCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mock-server",
+		Short: "Run a local mock of the api",
+		Long: commands.FormatHelp(`
+			Runs an in-memory implementation of the api for servers, networks, volumes, key pairs, elastic ips, orders,
+			kubernetes clusters and object storage instances. Orders and actions complete after a delay like they do on
+			the real api, which makes the mock server a sandbox for testing scripts without touching a real organization.
+			The credentials of object storage instances point to an S3 compatible stand-in served by the mock server
+			itself. All resources are lost once the server is stopped.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Run the mock server and create a server against it
+      %[1]s dev mock-server --listen 127.0.0.1:8080 &
+      %[1]s compute key-pair create --name default --public-key ~/.ssh/id_ed25519.pub --endpoint http://127.0.0.1:8080/ --token sandbox
+      %[1]s compute server create --name web --location ALP1 --image ubuntu-22.04 --product b1.1x1 --key-pair default --endpoint http://127.0.0.1:8080/ --token sandbox
+
+      # Run the mock server with fast orders for automated tests
+      %[1]s dev mock-server --order-delay 100ms --action-delay 100ms
+		`, app.Name)),
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: m.CompleteArg,
+		RunE:              m.Run,
+	}
+
+	cmd.Flags().StringVar(&m.listen, "listen", "127.0.0.1:8080", "address to listen on")
+	cmd.Flags().StringVar(&m.token, "accept-token", "", "only accept this authentication token (default accept any token)")
+	cmd.Flags().DurationVar(&m.orderDelay, "order-delay", apitest.DefaultOrderDelay, "time orders spend processing")
+	cmd.Flags().DurationVar(&m.actionDelay, "action-delay", apitest.DefaultActionDelay, "time actions like starting a server take")
+
+	return cmd
+}
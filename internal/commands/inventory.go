@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/pkg/inventory"
+)
+
+// InventoryOptions holds the flags of the inventory commands.
+type InventoryOptions struct {
+	Filter    string
+	PrivateIP bool
+}
+
+// AddInventoryFlags registers the flags of an inventory command. The format of the inventory is selected using the
+// global --format flag, which accepts the inventory formats instead of the common output formats.
+func AddInventoryFlags(cmd *cobra.Command, opts *InventoryOptions, kind string) {
+	cmd.Flags().StringVar(&opts.Filter, "filter", "", "custom term to filter the "+kind+"s")
+	cmd.Flags().BoolVar(&opts.PrivateIP, "private-ip", false, "use the private ip as address of all hosts, e.g. when connecting through a vpn or bastion host")
+
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+
+	cmd.Annotations[AnnotationFormats] = strings.Join(inventory.Formats, ",")
+}
+
+// PrintInventory writes the hosts to stdout in the format selected by --format. All hosts are part of the group named
+// after the kind, e.g. servers.
+func PrintInventory(cmd *cobra.Command, hosts []inventory.Host, opts InventoryOptions, kind string) error {
+	format, err := CommandFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	return inventory.Write(Stdout, hosts, inventory.Options{
+		Format:    format,
+		Kind:      kind,
+		PrivateIP: opts.PrivateIP,
+	})
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+
+	"github.com/cloudbit-ch/cli/v2/pkg/console"
+	"github.com/cloudbit-ch/cli/v2/pkg/keyring"
+)
+
+// DefaultKeyringEntry is the name of the keyring entry used if no profile is selected.
+const DefaultKeyringEntry = "default"
+
+func KeyringPath() string {
+	return filepath.Join(configDir, "keyring")
+}
+
+// KeyringEntry returns the name of the keyring entry holding the token of the active profile.
+func KeyringEntry() string {
+	if activeProfile == "" {
+		return DefaultKeyringEntry
+	}
+
+	return activeProfile
+}
+
+// OpenKeyring asks for the passphrase and decrypts the keyring. The passphrase can also be passed using the
+// keyring_passphrase environment variable. If the keyring does not exist yet and create is true, the passphrase has to
+// be confirmed instead.
+func OpenKeyring(create bool) (*keyring.Keyring, string, error) {
+	path := KeyringPath()
+	exists := keyring.Exists(path)
+
+	if !exists && !create {
+		return nil, "", fmt.Errorf("keyring %s does not exist", path)
+	}
+
+	passphrase := viper.GetString(KeyPassphrase)
+	if passphrase == "" {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return nil, "", errors.New("keyring passphrase required, but not running in a terminal")
+		}
+
+		var err error
+		if exists {
+			passphrase, err = console.Password(Stderr, "Keyring Passphrase", checkPassphrase)
+		} else {
+			passphrase, err = newPassphrase()
+		}
+
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	ring, err := keyring.Open(path, passphrase)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return ring, passphrase, nil
+}
+
+func keyringToken() (string, error) {
+	ring, _, err := OpenKeyring(false)
+	if err != nil {
+		return "", err
+	}
+
+	token, ok := ring.Get(KeyringEntry())
+	if !ok {
+		return "", fmt.Errorf("keyring contains no token for %q", KeyringEntry())
+	}
+
+	return token, nil
+}
+
+func newPassphrase() (string, error) {
+	passphrase, err := console.Password(Stderr, "New Keyring Passphrase", checkPassphrase)
+	if err != nil {
+		return "", err
+	}
+
+	repeated, err := console.Password(Stderr, "Repeat Keyring Passphrase", func(string) error { return nil })
+	if err != nil {
+		return "", err
+	}
+
+	if repeated != passphrase {
+		return "", errors.New("passphrases do not match")
+	}
+
+	return passphrase, nil
+}
+
+func checkPassphrase(passphrase string) error {
+	if len(passphrase) < 8 {
+		return errors.New("the passphrase must be at least 8 characters long")
+	}
+
+	return nil
+}
@@ -28,6 +28,7 @@ func ClusterCommand(app commands.Application) *cobra.Command {
 		&clusterDeleteCommand{},
 		&clusterUpgradeCommand{},
 		&clusterKubeConfigCommand{},
+		&clusterWaitCommand{},
 	)
 
 	cmd.AddCommand(
@@ -83,6 +84,7 @@ type clusterCreateCommand struct {
 	workerProduct    string
 	workerCount      int
 	attachExternalIP bool
+	noWait           bool
 }
 
 func (c *clusterCreateCommand) Run(cmd *cobra.Command, args []string) error {
@@ -138,6 +140,10 @@ func (c *clusterCreateCommand) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("create cluster: %w", err)
 	}
 
+	if c.noWait {
+		return commands.PrintOrdering(ordering)
+	}
+
 	order, err := commands.WaitForOrder(cmd.Context(), "Creating cluster", ordering)
 	if err != nil {
 		return fmt.Errorf("wait for order: %w", err)
@@ -174,6 +180,7 @@ func (c *clusterCreateCommand) Build(app commands.Application) *cobra.Command {
 	cmd.Flags().StringVar(&c.workerProduct, "worker-product", "", "product for the worker nodes (required)")
 	cmd.Flags().IntVar(&c.workerCount, "worker-count", 3, "number of worker nodes")
 	cmd.Flags().BoolVar(&c.attachExternalIP, "attach-external-ip", true, "whether to attach an elastic ip to the cluster")
+	cmd.Flags().BoolVar(&c.noWait, "no-wait", false, "print the order reference instead of waiting for the cluster to be created")
 
 	_ = cmd.MarkFlagRequired("name")
 	_ = cmd.MarkFlagRequired("location")
@@ -276,6 +283,7 @@ func (c *clusterDeleteCommand) Build(app commands.Application) *cobra.Command {
 type clusterUpgradeCommand struct {
 	workerProduct string
 	workerCount   int
+	noWait        bool
 }
 
 func (c *clusterUpgradeCommand) Run(cmd *cobra.Command, args []string) error {
@@ -301,11 +309,45 @@ func (c *clusterUpgradeCommand) Run(cmd *cobra.Command, args []string) error {
 		},
 	}
 
-	cluster, err = kubernetes.NewClusterService(commands.Config.Client).UpdateFlavor(cmd.Context(), cluster.ID, data)
+	service := kubernetes.NewClusterService(commands.Config.Client)
+
+	cluster, err = service.UpdateFlavor(cmd.Context(), cluster.ID, data)
 	if err != nil {
 		return fmt.Errorf("upgrade cluster: %w", err)
 	}
 
+	if c.noWait {
+		return commands.PrintStdout(cluster)
+	}
+
+	err = commands.WaitFor(cmd.Context(), "Upgrading cluster", func(ctx context.Context) (bool, error) {
+		cluster, err = service.Get(ctx, cluster.ID)
+		if err != nil {
+			return false, fmt.Errorf("fetch cluster: %w", err)
+		}
+
+		// the cluster may not have been locked for the upgrade yet, so it is only done once it has the new flavor
+		if cluster.Locked || cluster.NodeCount.Current.Worker != c.workerCount || cluster.ExpectedPreset.Worker.ID != workerProduct.ID {
+			return false, nil
+		}
+
+		nodes, err := kubernetes.NewNodeService(commands.Config.Client, cluster.ID).List(ctx)
+		if err != nil {
+			return false, fmt.Errorf("fetch nodes: %w", err)
+		}
+
+		for _, node := range nodes {
+			if isWorkerNode(node) && node.Product.ID != workerProduct.ID {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
 	return commands.PrintStdout(cluster)
 }
 
@@ -329,6 +371,7 @@ func (c *clusterUpgradeCommand) Build(app commands.Application) *cobra.Command {
 
 	cmd.Flags().StringVar(&c.workerProduct, "worker-product", "", "product for the worker nodes (required)")
 	cmd.Flags().IntVar(&c.workerCount, "worker-count", 0, "number of worker nodes (required)")
+	cmd.Flags().BoolVar(&c.noWait, "no-wait", false, "print the cluster instead of waiting for the cluster to be upgraded")
 
 	_ = cmd.MarkFlagRequired("worker-product")
 	_ = cmd.MarkFlagRequired("worker-count")
@@ -380,6 +423,60 @@ func (c *clusterKubeConfigCommand) Build(app commands.Application) *cobra.Comman
 	return cmd
 }
 
+type clusterWaitCommand struct {
+	opts commands.WaitOptions
+}
+
+func (c *clusterWaitCommand) Run(cmd *cobra.Command, args []string) error {
+	cluster, err := findCluster(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	cluster, err = commands.WaitUntil(cmd.Context(), "cluster", cluster.Name, c.opts, func(ctx context.Context) (kubernetes.Cluster, error) {
+		return kubernetes.NewClusterService(commands.Config.Client).Get(ctx, cluster.ID)
+	})
+	if err != nil || c.opts.Deletion() {
+		return err
+	}
+
+	return commands.PrintStdout(cluster)
+}
+
+func (c *clusterWaitCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeCluster(cmd.Context(), toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (c *clusterWaitCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait CLUSTER",
+		Short: "Wait for a cluster to reach a state",
+		Long: commands.FormatHelp(`
+			Blocks until the cluster matches the condition given by --for or the timeout expires. The condition is either
+			COLUMN=VALUE, which is compared with the column of the cluster as shown by the list command, or delete to wait
+			until the cluster no longer exists.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Wait until the cluster is running
+      %[1]s kubernetes cluster wait my-cluster --for status=running --timeout 10m
+
+      # Wait until the cluster has been deleted
+      %[1]s kubernetes cluster wait my-cluster --for delete
+		`, app.Name)),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: c.CompleteArg,
+		RunE:              c.Run,
+	}
+
+	commands.AddWaitFlags(cmd, &c.opts)
+
+	return cmd
+}
+
 func completeCluster(ctx context.Context, term string) ([]string, cobra.ShellCompDirective) {
 	clusters, err := kubernetes.NewClusterService(commands.Config.Client).List(ctx)
 	if err != nil {
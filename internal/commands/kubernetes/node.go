@@ -21,6 +21,8 @@ func NodeCommand(app commands.Application) *cobra.Command {
 	commands.Add(app, cmd,
 		&nodeListCommand{},
 		&nodeDeleteCommand{},
+		&nodeWaitCommand{},
+		&nodeInventoryCommand{},
 	)
 
 	cmd.AddCommand(
@@ -136,6 +138,76 @@ func (n *nodeDeleteCommand) Build(app commands.Application) *cobra.Command {
 	return cmd
 }
 
+type nodeWaitCommand struct {
+	opts commands.WaitOptions
+}
+
+func (n *nodeWaitCommand) Run(cmd *cobra.Command, args []string) error {
+	cluster, err := findCluster(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	node, err := findNode(cmd.Context(), cluster.ID, args[1])
+	if err != nil {
+		return err
+	}
+
+	node, err = commands.WaitUntil(cmd.Context(), "node", node.Name, n.opts, func(ctx context.Context) (kubernetes.Node, error) {
+		nodes, err := kubernetes.NewNodeService(commands.Config.Client, cluster.ID).List(ctx)
+		if err != nil {
+			return kubernetes.Node{}, err
+		}
+
+		return commands.FindByID(nodes, node.ID, func(n kubernetes.Node) int { return n.ID })
+	})
+	if err != nil || n.opts.Deletion() {
+		return err
+	}
+
+	return commands.PrintStdout(node)
+}
+
+func (n *nodeWaitCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeCluster(cmd.Context(), toComplete)
+	}
+
+	if len(args) == 1 {
+		cluster, err := findCluster(cmd.Context(), args[0])
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		return completeNode(cmd.Context(), cluster, toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (n *nodeWaitCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait CLUSTER NODE",
+		Short: "Wait for a node to reach a state",
+		Long: commands.FormatHelp(`
+			Blocks until the node matches the condition given by --for or the timeout expires. The condition is either
+			COLUMN=VALUE, which is compared with the column of the node as shown by the list command, or delete to wait
+			until the node no longer exists.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Wait until the node is running
+      %[1]s kubernetes cluster node wait my-cluster my-node --for status=running --timeout 10m
+		`, app.Name)),
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: n.CompleteArg,
+		RunE:              n.Run,
+	}
+
+	commands.AddWaitFlags(cmd, &n.opts)
+
+	return cmd
+}
+
 func completeNode(ctx context.Context, cluster kubernetes.Cluster, term string) ([]string, cobra.ShellCompDirective) {
 	nodes, err := kubernetes.NewNodeService(commands.Config.Client, cluster.ID).List(ctx)
 	if err != nil {
@@ -165,3 +237,14 @@ func findNode(ctx context.Context, clusterID int, term string) (kubernetes.Node,
 
 	return node, nil
 }
+
+// isWorkerNode reports whether the node runs the workloads of the cluster instead of its control plane.
+func isWorkerNode(node kubernetes.Node) bool {
+	for _, role := range node.Roles {
+		if role.Key == "worker" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,102 @@
+package kubernetes
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/kubernetes"
+	"github.com/cloudbit-ch/cli/v2/pkg/filter"
+	"github.com/cloudbit-ch/cli/v2/pkg/inventory"
+)
+
+type nodeInventoryCommand struct {
+	opts commands.InventoryOptions
+}
+
+func (n *nodeInventoryCommand) Run(cmd *cobra.Command, args []string) error {
+	var clusters []kubernetes.Cluster
+	if len(args) == 0 {
+		var err error
+		if clusters, err = kubernetes.NewClusterService(commands.Config.Client).List(cmd.Context()); err != nil {
+			return fmt.Errorf("fetch clusters: %w", err)
+		}
+	}
+
+	for _, term := range args {
+		cluster, err := findCluster(cmd.Context(), term)
+		if err != nil {
+			return err
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	var hosts []inventory.Host
+	for _, cluster := range clusters {
+		nodes, err := kubernetes.NewNodeService(commands.Config.Client, cluster.ID).List(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("fetch nodes of cluster %s: %w", cluster.Name, err)
+		}
+
+		if len(n.opts.Filter) != 0 {
+			nodes = filter.Find(nodes, n.opts.Filter)
+		}
+
+		for _, node := range nodes {
+			host := inventory.Host{
+				Name:     node.Name,
+				Location: cluster.Location.Name,
+				Product:  node.Product.Name,
+				Network:  node.Network.Name,
+				Groups:   []string{inventory.GroupName("cluster", cluster.Name)},
+			}
+
+			for _, role := range node.Roles {
+				host.Groups = append(host.Groups, inventory.GroupName("role", role.Key))
+			}
+
+			for _, iface := range node.Network.Interfaces {
+				if host.PrivateIP == "" {
+					host.PrivateIP = iface.PrivateIP
+				}
+
+				if host.PublicIP == "" {
+					host.PublicIP = iface.PublicIP
+				}
+			}
+
+			hosts = append(hosts, host)
+		}
+	}
+
+	return commands.PrintInventory(cmd, hosts, n.opts, "kubernetes_nodes")
+}
+
+func (n *nodeInventoryCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeCluster(cmd.Context(), toComplete)
+}
+
+func (n *nodeInventoryCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inventory [CLUSTER...]",
+		Short: "Generate an ssh config or ansible inventory",
+		Long: commands.FormatHelp(`
+			Prints the nodes of the clusters, or of all clusters if none are given, as OpenSSH client configuration or
+			Ansible inventory, selected by --format ssh-config (default), ansible-ini or ansible-yaml. Besides the
+			location, product, network and name prefix, the nodes are grouped by their cluster and role, e.g.
+			cluster_production and role_worker.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Generate an ansible inventory of all nodes in the cluster production
+      %[1]s kubernetes cluster node inventory production --format ansible-yaml --private-ip
+		`, app.Name)),
+		ValidArgsFunction: n.CompleteArg,
+		RunE:              n.Run,
+	}
+
+	commands.AddInventoryFlags(cmd, &n.opts, "node")
+
+	return cmd
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/cloudbit-ch/cli/v2/internal/commands"
 	"github.com/cloudbit-ch/cli/v2/pkg/api/common"
 	"github.com/cloudbit-ch/cli/v2/pkg/api/macbaremetal"
+	"github.com/cloudbit-ch/cli/v2/pkg/console"
 	"github.com/cloudbit-ch/cli/v2/pkg/filter"
 )
 
@@ -20,7 +21,10 @@ func DeviceCommand(app commands.Application) *cobra.Command {
 		Short:   "Manage mac bare metal devices",
 		Example: commands.FormatExamples(fmt.Sprintf(`
       # Create a new device
-      %[1]s mac-bare-metal device create --name "my-device" --product "macmini.2018.6-16-256" --network default --password "some-secure-user-password"
+      %[1]s mac-bare-metal device create --name "my-device" --product "macmini.2018.6-16-256" --network default
+      
+      # Create a new device and read the user password from stdin
+      echo "$PASSWORD" | %[1]s mac-bare-metal device create --name "my-device" --product "macmini.2018.6-16-256" --network default --password-stdin
 		`, app.Name)),
 	}
 
@@ -30,6 +34,8 @@ func DeviceCommand(app commands.Application) *cobra.Command {
 		&deviceUpdateCommand{},
 		&deviceDeleteCommand{},
 		&deviceVNCCommand{},
+		&deviceWaitCommand{},
+		&deviceInventoryCommand{},
 	)
 
 	cmd.AddCommand(
@@ -155,7 +161,8 @@ type deviceCreateCommand struct {
 	product         string
 	network         string
 	attachElasticIP bool
-	password        string
+	passwordStdin   bool
+	noWait          bool
 }
 
 func (d *deviceCreateCommand) Run(cmd *cobra.Command, args []string) error {
@@ -179,13 +186,18 @@ func (d *deviceCreateCommand) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("find network: %w", err)
 	}
 
+	password, err := d.readPassword()
+	if err != nil {
+		return fmt.Errorf("read user password: %w", err)
+	}
+
 	data := macbaremetal.DeviceCreate{
 		Name:            d.name,
 		LocationID:      network.Location.ID,
 		ProductID:       product.ID,
 		NetworkID:       network.ID,
 		AttachElasticIP: d.attachElasticIP,
-		Password:        d.password,
+		Password:        password,
 	}
 
 	service := macbaremetal.NewDeviceService(commands.Config.Client)
@@ -195,6 +207,10 @@ func (d *deviceCreateCommand) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("create device: %w", err)
 	}
 
+	if d.noWait {
+		return commands.PrintOrdering(ordering)
+	}
+
 	order, err := commands.WaitForOrder(cmd.Context(), "Creating device", ordering)
 	if err != nil {
 		return fmt.Errorf("wait for order: %w", err)
@@ -202,23 +218,51 @@ func (d *deviceCreateCommand) Run(cmd *cobra.Command, args []string) error {
 
 	device, err := service.Get(cmd.Context(), order.Product.ID)
 	if err != nil {
-		return fmt.Errorf("fetch order: %w", err)
+		return fmt.Errorf("fetch device: %w", err)
 	}
 
 	return commands.PrintStdout(device)
 }
 
+func (d *deviceCreateCommand) readPassword() (string, error) {
+	if d.passwordStdin {
+		password, err := console.ReadLine()
+		if err != nil {
+			return "", err
+		}
+
+		return password, checkDevicePassword(password)
+	}
+
+	if !commands.Config.Terminal {
+		return "", fmt.Errorf("unable to prompt for the password without a terminal, use --password-stdin instead")
+	}
+
+	return console.Password(commands.Stderr, "Device User Password", checkDevicePassword)
+}
+
 func (d *deviceCreateCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	return nil, cobra.ShellCompDirectiveNoFileComp
 }
 
 func (d *deviceCreateCommand) Build(app commands.Application) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:               "create",
-		Aliases:           []string{"add", "new"},
-		Short:             "Create new device",
-		Long:              "Creates a new mac bare metal device.",
-		Example:           "", // TODO
+		Use:     "create",
+		Aliases: []string{"add", "new"},
+		Short:   "Create new device",
+		Long: commands.FormatHelp(`
+			Creates a new mac bare metal device.
+
+			The password of the device user is prompted interactively. When no terminal is available, the password
+			can be passed on stdin using the --password-stdin flag.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Create a new device and prompt for the user password
+      %[1]s mac-bare-metal device create --name "my-device" --product "macmini.2018.6-16-256" --network default
+      
+      # Create a new device with the user password read from a file
+      %[1]s mac-bare-metal device create --name "my-device" --product "macmini.2018.6-16-256" --network default --password-stdin < password.txt
+		`, app.Name)),
 		ValidArgsFunction: d.CompleteArg,
 		RunE:              d.Run,
 	}
@@ -227,12 +271,16 @@ func (d *deviceCreateCommand) Build(app commands.Application) *cobra.Command {
 	cmd.Flags().StringVar(&d.product, "product", "", "product for the device")
 	cmd.Flags().StringVar(&d.network, "network", "", "network to be attached to the device")
 	cmd.Flags().BoolVar(&d.attachElasticIP, "attach-elastic-ip", false, "whether to attach an elastic ip to the device")
-	cmd.Flags().StringVar(&d.password, "password", "", "password to be applied to the device") // TODO this is insecure and should be removed
+	cmd.Flags().BoolVar(&d.passwordStdin, "password-stdin", false, "read the password of the device user from stdin")
+	cmd.Flags().BoolVar(&d.noWait, "no-wait", false, "print the order reference instead of waiting for the device to be created")
 
 	_ = cmd.MarkFlagRequired("name")
 	_ = cmd.MarkFlagRequired("product")
 	_ = cmd.MarkFlagRequired("network")
-	_ = cmd.MarkFlagRequired("password")
+
+	_ = cmd.RegisterFlagCompletionFunc("network", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeNetwork(cmd.Context(), toComplete)
+	})
 
 	return cmd
 }
@@ -242,23 +290,16 @@ type deviceUpdateCommand struct {
 }
 
 func (d *deviceUpdateCommand) Run(cmd *cobra.Command, args []string) error {
-	service := macbaremetal.NewDeviceService(commands.Config.Client)
-
-	devices, err := service.List(cmd.Context())
-	if err != nil {
-		return fmt.Errorf("fetch devices: %w", err)
-	}
-
-	device, err := filter.FindOne(devices, args[0])
+	device, err := findDevice(cmd.Context(), args[0])
 	if err != nil {
-		return fmt.Errorf("find device: %w", err)
+		return err
 	}
 
 	update := macbaremetal.DeviceUpdate{
 		Name: d.name,
 	}
 
-	device, err = service.Update(cmd.Context(), device.ID, update)
+	device, err = macbaremetal.NewDeviceService(commands.Config.Client).Update(cmd.Context(), device.ID, update)
 	if err != nil {
 		return fmt.Errorf("update device: %w", err)
 	}
@@ -297,16 +338,9 @@ type deviceDeleteCommand struct {
 }
 
 func (d *deviceDeleteCommand) Run(cmd *cobra.Command, args []string) error {
-	service := macbaremetal.NewDeviceService(commands.Config.Client)
-
-	devices, err := service.List(cmd.Context())
-	if err != nil {
-		return fmt.Errorf("fetch devices: %w", err)
-	}
-
-	device, err := filter.FindOne(devices, args[0])
+	device, err := findDevice(cmd.Context(), args[0])
 	if err != nil {
-		return fmt.Errorf("find device: %w", err)
+		return err
 	}
 
 	if !d.force && !commands.ConfirmDeletion("device", device) {
@@ -314,7 +348,7 @@ func (d *deviceDeleteCommand) Run(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	err = service.Delete(cmd.Context(), device.ID)
+	err = macbaremetal.NewDeviceService(commands.Config.Client).Delete(cmd.Context(), device.ID)
 	if err != nil {
 		return fmt.Errorf("delete device: %w", err)
 	}
@@ -353,6 +387,60 @@ func (d *deviceDeleteCommand) Build(app commands.Application) *cobra.Command {
 	return cmd
 }
 
+type deviceWaitCommand struct {
+	opts commands.WaitOptions
+}
+
+func (d *deviceWaitCommand) Run(cmd *cobra.Command, args []string) error {
+	device, err := findDevice(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	device, err = commands.WaitUntil(cmd.Context(), "device", device.Name, d.opts, func(ctx context.Context) (macbaremetal.Device, error) {
+		return macbaremetal.NewDeviceService(commands.Config.Client).Get(ctx, device.ID)
+	})
+	if err != nil || d.opts.Deletion() {
+		return err
+	}
+
+	return commands.PrintStdout(device)
+}
+
+func (d *deviceWaitCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeDevice(cmd.Context(), toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (d *deviceWaitCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait DEVICE",
+		Short: "Wait for a device to reach a state",
+		Long: commands.FormatHelp(`
+			Blocks until the device matches the condition given by --for or the timeout expires. The condition is either
+			COLUMN=VALUE, which is compared with the column of the device as shown by the list command, or delete to wait
+			until the device no longer exists.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Wait until the device is running
+      %[1]s mac-bare-metal device wait my-device --for status=running --timeout 10m
+
+      # Wait until the device has been deleted
+      %[1]s mac-bare-metal device wait my-device --for delete
+		`, app.Name)),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: d.CompleteArg,
+		RunE:              d.Run,
+	}
+
+	commands.AddWaitFlags(cmd, &d.opts)
+
+	return cmd
+}
+
 func completeDevice(ctx context.Context, term string) ([]string, cobra.ShellCompDirective) {
 	devices, err := macbaremetal.NewDeviceService(commands.Config.Client).List(ctx)
 	if err != nil {
@@ -370,15 +458,23 @@ func completeDevice(ctx context.Context, term string) ([]string, cobra.ShellComp
 }
 
 func findDevice(ctx context.Context, term string) (macbaremetal.Device, error) {
-	elasticIPs, err := macbaremetal.NewDeviceService(commands.Config.Client).List(ctx)
+	devices, err := macbaremetal.NewDeviceService(commands.Config.Client).List(ctx)
 	if err != nil {
 		return macbaremetal.Device{}, fmt.Errorf("fetch devices: %w", err)
 	}
 
-	elasticIP, err := filter.FindOne(elasticIPs, term)
+	device, err := filter.FindOne(devices, term)
 	if err != nil {
 		return macbaremetal.Device{}, fmt.Errorf("find device: %w", err)
 	}
 
-	return elasticIP, nil
+	return device, nil
+}
+
+func checkDevicePassword(password string) error {
+	if len(password) == 0 {
+		return fmt.Errorf("device user password must not be empty")
+	}
+
+	return nil
 }
@@ -0,0 +1,78 @@
+package macbaremetal
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/macbaremetal"
+	"github.com/cloudbit-ch/cli/v2/pkg/filter"
+	"github.com/cloudbit-ch/cli/v2/pkg/inventory"
+)
+
+type deviceInventoryCommand struct {
+	opts commands.InventoryOptions
+}
+
+func (d *deviceInventoryCommand) Run(cmd *cobra.Command, args []string) error {
+	devices, err := macbaremetal.NewDeviceService(commands.Config.Client).List(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("fetch devices: %w", err)
+	}
+
+	if len(d.opts.Filter) != 0 {
+		devices = filter.Find(devices, d.opts.Filter)
+	}
+
+	hosts := make([]inventory.Host, len(devices))
+	for idx, device := range devices {
+		host := inventory.Host{
+			Name:     device.Name,
+			Location: device.Location.Name,
+			Product:  device.Product.Name,
+			Network:  device.Network.Name,
+		}
+
+		for _, iface := range device.NetworkInterfaces {
+			if host.PrivateIP == "" {
+				host.PrivateIP = iface.PrivateIP
+			}
+
+			if host.PublicIP == "" {
+				host.PublicIP = iface.PublicIP
+			}
+		}
+
+		hosts[idx] = host
+	}
+
+	return commands.PrintInventory(cmd, hosts, d.opts, "mac_devices")
+}
+
+func (d *deviceInventoryCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (d *deviceInventoryCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Generate an ssh config or ansible inventory",
+		Long: commands.FormatHelp(`
+			Prints all mac bare metal devices as OpenSSH client configuration or Ansible inventory, selected by --format
+			ssh-config (default), ansible-ini or ansible-yaml. The hosts are grouped by location, product, network and
+			name prefix.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Generate an ansible inventory of all devices
+      %[1]s mac-bare-metal device inventory --format ansible-ini
+		`, app.Name)),
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: d.CompleteArg,
+		RunE:              d.Run,
+	}
+
+	commands.AddInventoryFlags(cmd, &d.opts, "device")
+
+	return cmd
+}
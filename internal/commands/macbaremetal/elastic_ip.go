@@ -123,16 +123,9 @@ type elasticIPDeleteCommand struct {
 }
 
 func (e *elasticIPDeleteCommand) Run(cmd *cobra.Command, args []string) error {
-	service := macbaremetal.NewElasticIPService(commands.Config.Client)
-
-	elasticIPs, err := service.List(cmd.Context())
-	if err != nil {
-		return fmt.Errorf("fetch elastic ips: %w", err)
-	}
-
-	elasticIP, err := filter.FindOne(elasticIPs, args[0])
+	elasticIP, err := findElasticIP(cmd.Context(), args[0])
 	if err != nil {
-		return fmt.Errorf("find elastic ip: %w", err)
+		return err
 	}
 
 	if elasticIP.Attachment.ID != 0 {
@@ -144,6 +137,8 @@ func (e *elasticIPDeleteCommand) Run(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	service := macbaremetal.NewElasticIPService(commands.Config.Client)
+
 	if elasticIP.Attachment.ID != 0 {
 		err = service.Detach(cmd.Context(), elasticIP.Attachment.ID, elasticIP.ID)
 		if err != nil {
@@ -68,16 +68,9 @@ type routerUpdateCommand struct {
 }
 
 func (r *routerUpdateCommand) Run(cmd *cobra.Command, args []string) error {
-	service := macbaremetal.NewRouterService(commands.Config.Client)
-
-	routers, err := service.List(cmd.Context())
+	router, err := findRouter(cmd.Context(), args[0])
 	if err != nil {
-		return fmt.Errorf("fetch routers: %w", err)
-	}
-
-	router, err := filter.FindOne(routers, args[0])
-	if err != nil {
-		return fmt.Errorf("find router: %w", err)
+		return err
 	}
 
 	update := macbaremetal.RouterUpdate{
@@ -85,7 +78,7 @@ func (r *routerUpdateCommand) Run(cmd *cobra.Command, args []string) error {
 		Description: r.description,
 	}
 
-	router, err = service.Update(cmd.Context(), router.ID, update)
+	router, err = macbaremetal.NewRouterService(commands.Config.Client).Update(cmd.Context(), router.ID, update)
 	if err != nil {
 		return fmt.Errorf("update router: %w", err)
 	}
@@ -132,3 +125,17 @@ func completeRouter(ctx context.Context, term string) ([]string, cobra.ShellComp
 
 	return names, cobra.ShellCompDirectiveNoFileComp
 }
+
+func findRouter(ctx context.Context, term string) (macbaremetal.Router, error) {
+	routers, err := macbaremetal.NewRouterService(commands.Config.Client).List(ctx)
+	if err != nil {
+		return macbaremetal.Router{}, fmt.Errorf("fetch routers: %w", err)
+	}
+
+	router, err := filter.FindOne(routers, term)
+	if err != nil {
+		return macbaremetal.Router{}, fmt.Errorf("find router: %w", err)
+	}
+
+	return router, nil
+}
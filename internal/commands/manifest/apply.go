@@ -0,0 +1,146 @@
+package manifest
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+)
+
+func ApplyCommand(app commands.Application) *cobra.Command {
+	return (&applyCommand{}).Build(app)
+}
+
+func DiffCommand(app commands.Application) *cobra.Command {
+	return (&diffCommand{}).Build(app)
+}
+
+type applyCommand struct {
+	file  string
+	prune bool
+	force bool
+}
+
+func (a *applyCommand) Run(cmd *cobra.Command, args []string) error {
+	manifest, err := Load(a.file)
+	if err != nil {
+		return err
+	}
+
+	changes, err := Plan(cmd.Context(), manifest, a.prune)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		commands.Stderr.Println("no changes, the infrastructure is up-to-date.")
+		return nil
+	}
+
+	if err = commands.Print(commands.Stderr, changes); err != nil {
+		return err
+	}
+
+	if !a.force && !commands.Confirm(fmt.Sprintf("Are you sure you want to apply %d changes?", len(changes))) {
+		commands.Stderr.Println("aborted.")
+		return nil
+	}
+
+	if err = Apply(cmd.Context(), changes); err != nil {
+		return err
+	}
+
+	commands.Stderr.Printf("applied %d changes.\n", len(changes))
+	return nil
+}
+
+func (a *applyCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (a *applyCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply a manifest",
+		Long: commands.FormatHelp(`
+			Creates or updates the resources described in the manifest, so they match the manifest.
+
+			The manifest can be written in YAML or JSON. Resources are identified by their name and references between
+			resources are resolved by name as well. Only the attributes present in the manifest are compared with the
+			live resources, all other attributes are left untouched.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Apply the manifest in stack.yaml
+      %[1]s apply -f stack.yaml
+
+      # Apply the manifest without confirmation and remove unlisted security group rules and router interfaces
+      %[1]s apply -f stack.yaml --prune --force
+		`, app.Name)),
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: a.CompleteArg,
+		RunE:              a.Run,
+	}
+
+	cmd.Flags().StringVarP(&a.file, "file", "f", "", "manifest file to apply, use - to read from stdin (required)")
+	cmd.Flags().BoolVar(&a.prune, "prune", false, "delete security group rules and router interfaces which are not present in the manifest")
+	cmd.Flags().BoolVar(&a.force, "force", false, "apply the changes without asking for confirmation")
+
+	_ = cmd.MarkFlagRequired("file")
+	_ = cmd.MarkFlagFilename("file", "yaml", "yml", "json")
+
+	return cmd
+}
+
+type diffCommand struct {
+	file  string
+	prune bool
+}
+
+func (d *diffCommand) Run(cmd *cobra.Command, args []string) error {
+	manifest, err := Load(d.file)
+	if err != nil {
+		return err
+	}
+
+	changes, err := Plan(cmd.Context(), manifest, d.prune)
+	if err != nil {
+		return err
+	}
+
+	return commands.PrintStdout(changes)
+}
+
+func (d *diffCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (d *diffCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show changes of a manifest",
+		Long: commands.FormatHelp(fmt.Sprintf(`
+			Prints the changes "%[1]s apply" would make to reconcile the live resources with the manifest.
+
+			No write requests are sent to the api.
+		`, app.Name)),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Show the changes required by stack.yaml
+      %[1]s diff -f stack.yaml
+
+      # Print the changes in JSON format, e.g. to verify them in a pipeline
+      %[1]s diff -f stack.yaml --format json
+		`, app.Name)),
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: d.CompleteArg,
+		RunE:              d.Run,
+	}
+
+	cmd.Flags().StringVarP(&d.file, "file", "f", "", "manifest file to compare, use - to read from stdin (required)")
+	cmd.Flags().BoolVar(&d.prune, "prune", false, "include the deletion of security group rules and router interfaces which are not present in the manifest")
+
+	_ = cmd.MarkFlagRequired("file")
+	_ = cmd.MarkFlagFilename("file", "yaml", "yml", "json")
+
+	return cmd
+}
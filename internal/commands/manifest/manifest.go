@@ -0,0 +1,285 @@
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Manifest struct {
+	KeyPairs       []KeyPair       `yaml:"key_pairs"`
+	Networks       []Network       `yaml:"networks"`
+	SecurityGroups []SecurityGroup `yaml:"security_groups"`
+	Routers        []Router        `yaml:"routers"`
+	Servers        []Server        `yaml:"servers"`
+	Volumes        []Volume        `yaml:"volumes"`
+	ElasticIPs     []ElasticIP     `yaml:"elastic_ips"`
+	LoadBalancers  []LoadBalancer  `yaml:"load_balancers"`
+}
+
+type KeyPair struct {
+	Name          string `yaml:"name"`
+	PublicKey     string `yaml:"public_key"`
+	PublicKeyFile string `yaml:"public_key_file"`
+}
+
+type Network struct {
+	Name                string   `yaml:"name"`
+	Description         string   `yaml:"description"`
+	Location            string   `yaml:"location"`
+	CIDR                string   `yaml:"cidr"`
+	DomainNameServers   []string `yaml:"domain_name_servers"`
+	AllocationPoolStart string   `yaml:"allocation_pool_start"`
+	AllocationPoolEnd   string   `yaml:"allocation_pool_end"`
+	GatewayIP           string   `yaml:"gateway_ip"`
+}
+
+type SecurityGroup struct {
+	Name        string              `yaml:"name"`
+	Description string              `yaml:"description"`
+	Location    string              `yaml:"location"`
+	Rules       []SecurityGroupRule `yaml:"rules"`
+}
+
+type SecurityGroupRule struct {
+	Direction           string `yaml:"direction"`
+	Protocol            string `yaml:"protocol"`
+	FromPort            int    `yaml:"from_port"`
+	ToPort              int    `yaml:"to_port"`
+	ICMPType            int    `yaml:"icmp_type"`
+	ICMPCode            int    `yaml:"icmp_code"`
+	IPRange             string `yaml:"ip_range"`
+	RemoteSecurityGroup string `yaml:"remote_security_group"`
+}
+
+type Router struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Location    string            `yaml:"location"`
+	Public      bool              `yaml:"public"`
+	Interfaces  []RouterInterface `yaml:"interfaces"`
+}
+
+type RouterInterface struct {
+	Network   string `yaml:"network"`
+	PrivateIP string `yaml:"private_ip"`
+}
+
+type Server struct {
+	Name             string `yaml:"name"`
+	Location         string `yaml:"location"`
+	Image            string `yaml:"image"`
+	Product          string `yaml:"product"`
+	Network          string `yaml:"network"`
+	PrivateIP        string `yaml:"private_ip"`
+	KeyPair          string `yaml:"key_pair"`
+	CloudInitFile    string `yaml:"cloud_init_file"`
+	AttachExternalIP *bool  `yaml:"attach_external_ip"`
+}
+
+type Volume struct {
+	Name     string `yaml:"name"`
+	Location string `yaml:"location"`
+	Size     int    `yaml:"size"`
+	AttachTo string `yaml:"attach_to"`
+}
+
+type ElasticIP struct {
+	Location string `yaml:"location"`
+	PublicIP string `yaml:"public_ip"`
+	AttachTo string `yaml:"attach_to"`
+}
+
+type LoadBalancer struct {
+	Name      string `yaml:"name"`
+	Network   string `yaml:"network"`
+	PrivateIP string `yaml:"private_ip"`
+	Internal  bool   `yaml:"internal"`
+}
+
+// Load reads the manifest at the specified path. Since every JSON document is also a valid YAML document, both formats
+// are accepted. A path of "-" reads the manifest from stdin.
+func Load(path string) (Manifest, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		return Manifest{}, fmt.Errorf("read manifest: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	manifest := Manifest{}
+	if err = decoder.Decode(&manifest); err != nil && !errors.Is(err, io.EOF) {
+		return Manifest{}, fmt.Errorf("parse manifest: %w", err)
+	}
+
+	if err = manifest.resolvePaths(filepath.Dir(path)); err != nil {
+		return Manifest{}, err
+	}
+
+	if err = manifest.validate(); err != nil {
+		return Manifest{}, fmt.Errorf("validate manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// resolvePaths makes all file references within the manifest relative to the directory of the manifest itself.
+func (m *Manifest) resolvePaths(dir string) error {
+	resolve := func(path string) (string, error) {
+		if path == "" || filepath.IsAbs(path) {
+			return path, nil
+		}
+
+		if path == "~" || strings.HasPrefix(path, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+
+			return filepath.Join(home, path[1:]), nil
+		}
+
+		return filepath.Join(dir, path), nil
+	}
+
+	var err error
+	for i := range m.KeyPairs {
+		if m.KeyPairs[i].PublicKeyFile, err = resolve(m.KeyPairs[i].PublicKeyFile); err != nil {
+			return err
+		}
+	}
+
+	for i := range m.Servers {
+		if m.Servers[i].CloudInitFile, err = resolve(m.Servers[i].CloudInitFile); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Manifest) validate() error {
+	names := map[string]bool{}
+	unique := func(kind, name string) error {
+		if name == "" {
+			return fmt.Errorf("%s without name", kind)
+		}
+
+		key := kind + "/" + strings.ToLower(name)
+		if names[key] {
+			return fmt.Errorf("duplicate %s %q", kind, name)
+		}
+
+		names[key] = true
+		return nil
+	}
+
+	for _, keyPair := range m.KeyPairs {
+		if err := unique("key pair", keyPair.Name); err != nil {
+			return err
+		}
+
+		if (keyPair.PublicKey == "") == (keyPair.PublicKeyFile == "") {
+			return fmt.Errorf("key pair %q: exactly one of public_key or public_key_file is required", keyPair.Name)
+		}
+	}
+
+	for _, network := range m.Networks {
+		if err := unique("network", network.Name); err != nil {
+			return err
+		}
+
+		if network.Location == "" || network.CIDR == "" {
+			return fmt.Errorf("network %q: location and cidr are required", network.Name)
+		}
+	}
+
+	for _, securityGroup := range m.SecurityGroups {
+		if err := unique("security group", securityGroup.Name); err != nil {
+			return err
+		}
+
+		if securityGroup.Location == "" {
+			return fmt.Errorf("security group %q: location is required", securityGroup.Name)
+		}
+
+		for _, rule := range securityGroup.Rules {
+			if rule.Direction == "" || rule.Protocol == "" {
+				return fmt.Errorf("security group %q: direction and protocol are required for every rule", securityGroup.Name)
+			}
+		}
+	}
+
+	for _, router := range m.Routers {
+		if err := unique("router", router.Name); err != nil {
+			return err
+		}
+
+		if router.Location == "" {
+			return fmt.Errorf("router %q: location is required", router.Name)
+		}
+	}
+
+	for _, server := range m.Servers {
+		if err := unique("server", server.Name); err != nil {
+			return err
+		}
+
+		if server.Location == "" || server.Image == "" || server.Product == "" {
+			return fmt.Errorf("server %q: location, image and product are required", server.Name)
+		}
+	}
+
+	for _, volume := range m.Volumes {
+		if err := unique("volume", volume.Name); err != nil {
+			return err
+		}
+
+		if volume.Size <= 0 {
+			return fmt.Errorf("volume %q: size is required", volume.Name)
+		}
+
+		if volume.Location == "" && volume.AttachTo == "" {
+			return fmt.Errorf("volume %q: either location or attach_to is required", volume.Name)
+		}
+	}
+
+	for _, elasticIP := range m.ElasticIPs {
+		if elasticIP.PublicIP == "" && elasticIP.AttachTo == "" {
+			return fmt.Errorf("elastic ip in %s: either public_ip or attach_to is required to identify it", elasticIP.Location)
+		}
+
+		if elasticIP.Location == "" && elasticIP.AttachTo == "" {
+			return fmt.Errorf("elastic ip %s: either location or attach_to is required", elasticIP.PublicIP)
+		}
+	}
+
+	for _, loadBalancer := range m.LoadBalancers {
+		if err := unique("load balancer", loadBalancer.Name); err != nil {
+			return err
+		}
+
+		if loadBalancer.Network == "" {
+			return fmt.Errorf("load balancer %q: network is required", loadBalancer.Name)
+		}
+	}
+
+	return nil
+}
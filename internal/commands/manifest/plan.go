@@ -0,0 +1,1195 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/compute"
+	"github.com/cloudbit-ch/cli/v2/pkg/cloudinit"
+	"github.com/cloudbit-ch/cli/v2/pkg/console"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+type Change struct {
+	Action  string   `json:"action"`
+	Kind    string   `json:"kind"`
+	Name    string   `json:"name"`
+	Details []string `json:"details,omitempty"`
+
+	apply func(ctx context.Context) error
+}
+
+func (c Change) Columns() []string {
+	return []string{"action", "kind", "name", "details"}
+}
+
+func (c Change) Values() map[string]interface{} {
+	return map[string]interface{}{
+		"action":  c.Action,
+		"kind":    c.Kind,
+		"name":    c.Name,
+		"details": strings.Join(c.Details, ", "),
+	}
+}
+
+type planner struct {
+	state *state
+	prune bool
+
+	// externalIPs contains the names of all servers which will receive an elastic ip, either during their creation or
+	// from an elastic ip of the manifest.
+	externalIPs map[string]bool
+
+	changes []Change
+}
+
+// Plan compares the manifest with the live state and returns the changes required to reconcile them. The changes
+// are ordered, so that every resource is created before it gets referenced by another one.
+func Plan(ctx context.Context, manifest Manifest, prune bool) ([]Change, error) {
+	s, err := fetchState(ctx, commands.Config.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &planner{
+		state:       s,
+		prune:       prune,
+		externalIPs: map[string]bool{},
+	}
+
+	steps := []func(ctx context.Context, m Manifest) error{
+		p.planKeyPairs,
+		p.planNetworks,
+		p.planSecurityGroups,
+		p.planSecurityGroupRules,
+		p.planRouters,
+		p.planServers,
+		p.planVolumes,
+		p.planElasticIPs,
+		p.planLoadBalancers,
+	}
+
+	for _, step := range steps {
+		if err := step(ctx, manifest); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.changes, nil
+}
+
+// Apply executes the changes in order and stops at the first failure.
+func Apply(ctx context.Context, changes []Change) error {
+	for _, change := range changes {
+		commands.Stderr.Printf("%s %s %s\n", change.Action, change.Kind, change.Name)
+
+		if err := change.apply(ctx); err != nil {
+			return fmt.Errorf("%s %s %s: %w", change.Action, change.Kind, change.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *planner) add(change Change) {
+	p.changes = append(p.changes, change)
+}
+
+func (p *planner) planKeyPairs(ctx context.Context, m Manifest) error {
+	for _, item := range m.KeyPairs {
+		if _, ok := lookup(p.state.keyPairs, item.Name, keyPairName); ok {
+			// key pairs are immutable, there is nothing to update
+			continue
+		}
+
+		publicKey := item.PublicKey
+		if item.PublicKeyFile != "" {
+			data, err := os.ReadFile(item.PublicKeyFile)
+			if err != nil {
+				return fmt.Errorf("key pair %q: read public key: %w", item.Name, err)
+			}
+
+			publicKey = string(data)
+		}
+
+		data := compute.KeyPairCreate{
+			Name:      item.Name,
+			PublicKey: publicKey,
+		}
+
+		p.state.keyPairs = append(p.state.keyPairs, compute.KeyPair{Name: item.Name})
+		p.add(Change{
+			Action: ActionCreate,
+			Kind:   "key pair",
+			Name:   item.Name,
+			apply: func(ctx context.Context) error {
+				keyPair, err := compute.NewKeyPairService(p.state.client).Create(ctx, data)
+				if err != nil {
+					return fmt.Errorf("create key pair: %w", err)
+				}
+
+				p.state.keyPairs = upsert(p.state.keyPairs, keyPair, keyPairName)
+				return nil
+			},
+		})
+	}
+
+	return nil
+}
+
+func (p *planner) planNetworks(ctx context.Context, m Manifest) error {
+	for _, item := range m.Networks {
+		location, err := p.state.location(item.Location)
+		if err != nil {
+			return fmt.Errorf("network %q: %w", item.Name, err)
+		}
+
+		live, ok := lookup(p.state.networks, item.Name, networkName)
+		if !ok {
+			data := compute.NetworkCreate{
+				Name:                item.Name,
+				Description:         item.Description,
+				LocationID:          location.ID,
+				DomainNameServers:   item.DomainNameServers,
+				CIDR:                item.CIDR,
+				AllocationPoolStart: item.AllocationPoolStart,
+				AllocationPoolEnd:   item.AllocationPoolEnd,
+				GatewayIP:           item.GatewayIP,
+			}
+
+			placeholder := compute.Network{Name: item.Name, CIDR: item.CIDR}
+			placeholder.Location.ID = location.ID
+			placeholder.Location.Name = location.Name
+
+			p.state.networks = append(p.state.networks, placeholder)
+			p.add(Change{
+				Action:  ActionCreate,
+				Kind:    "network",
+				Name:    item.Name,
+				Details: []string{"location: " + location.Name, "cidr: " + item.CIDR},
+				apply: func(ctx context.Context) error {
+					network, err := compute.NewNetworkService(p.state.client).Create(ctx, data)
+					if err != nil {
+						return fmt.Errorf("create network: %w", err)
+					}
+
+					p.state.networks = upsert(p.state.networks, network, networkName)
+					return nil
+				},
+			})
+			continue
+		}
+
+		if live.Location.ID != location.ID {
+			return fmt.Errorf("network %q: location cannot be changed from %s to %s", item.Name, live.Location.Name, location.Name)
+		}
+
+		if live.CIDR != item.CIDR {
+			return fmt.Errorf("network %q: cidr cannot be changed from %s to %s", item.Name, live.CIDR, item.CIDR)
+		}
+
+		var details []string
+		data := compute.NetworkUpdate{}
+
+		if item.Description != "" && item.Description != live.Description {
+			data.Description = item.Description
+			details = append(details, detail("description", live.Description, item.Description))
+		}
+
+		if len(item.DomainNameServers) != 0 && !equalStrings(item.DomainNameServers, live.DomainNameServers) {
+			data.DomainNameServers = item.DomainNameServers
+			details = append(details, detail("domain name servers", live.DomainNameServers, item.DomainNameServers))
+		}
+
+		if item.AllocationPoolStart != "" && item.AllocationPoolStart != live.AllocationPoolStart {
+			data.AllocationPoolStart = item.AllocationPoolStart
+			details = append(details, detail("allocation pool start", live.AllocationPoolStart, item.AllocationPoolStart))
+		}
+
+		if item.AllocationPoolEnd != "" && item.AllocationPoolEnd != live.AllocationPoolEnd {
+			data.AllocationPoolEnd = item.AllocationPoolEnd
+			details = append(details, detail("allocation pool end", live.AllocationPoolEnd, item.AllocationPoolEnd))
+		}
+
+		if item.GatewayIP != "" && item.GatewayIP != live.GatewayIP {
+			data.GatewayIP = item.GatewayIP
+			details = append(details, detail("gateway ip", live.GatewayIP, item.GatewayIP))
+		}
+
+		if len(details) == 0 {
+			continue
+		}
+
+		id := live.ID
+		p.add(Change{
+			Action:  ActionUpdate,
+			Kind:    "network",
+			Name:    item.Name,
+			Details: details,
+			apply: func(ctx context.Context) error {
+				network, err := compute.NewNetworkService(p.state.client).Update(ctx, id, data)
+				if err != nil {
+					return fmt.Errorf("update network: %w", err)
+				}
+
+				p.state.networks = upsert(p.state.networks, network, networkName)
+				return nil
+			},
+		})
+	}
+
+	return nil
+}
+
+func (p *planner) planSecurityGroups(ctx context.Context, m Manifest) error {
+	for _, item := range m.SecurityGroups {
+		location, err := p.state.location(item.Location)
+		if err != nil {
+			return fmt.Errorf("security group %q: %w", item.Name, err)
+		}
+
+		live, ok := lookup(p.state.securityGroups, item.Name, securityGroupName)
+		if !ok {
+			data := compute.SecurityGroupCreate{
+				Name:        item.Name,
+				Description: item.Description,
+				LocationID:  location.ID,
+			}
+
+			placeholder := compute.SecurityGroup{Name: item.Name}
+			placeholder.Location.ID = location.ID
+			placeholder.Location.Name = location.Name
+
+			p.state.securityGroups = append(p.state.securityGroups, placeholder)
+			p.add(Change{
+				Action:  ActionCreate,
+				Kind:    "security group",
+				Name:    item.Name,
+				Details: []string{"location: " + location.Name},
+				apply: func(ctx context.Context) error {
+					securityGroup, err := compute.NewSecurityGroupService(p.state.client).Create(ctx, data)
+					if err != nil {
+						return fmt.Errorf("create security group: %w", err)
+					}
+
+					p.state.securityGroups = upsert(p.state.securityGroups, securityGroup, securityGroupName)
+					return nil
+				},
+			})
+			continue
+		}
+
+		if live.Location.ID != location.ID {
+			return fmt.Errorf("security group %q: location cannot be changed from %s to %s", item.Name, live.Location.Name, location.Name)
+		}
+
+		if item.Description == "" || item.Description == live.Description {
+			continue
+		}
+
+		id := live.ID
+		data := compute.SecurityGroupUpdate{
+			Name:        live.Name,
+			Description: item.Description,
+		}
+
+		p.add(Change{
+			Action:  ActionUpdate,
+			Kind:    "security group",
+			Name:    item.Name,
+			Details: []string{detail("description", live.Description, item.Description)},
+			apply: func(ctx context.Context) error {
+				securityGroup, err := compute.NewSecurityGroupService(p.state.client).Update(ctx, id, data)
+				if err != nil {
+					return fmt.Errorf("update security group: %w", err)
+				}
+
+				p.state.securityGroups = upsert(p.state.securityGroups, securityGroup, securityGroupName)
+				return nil
+			},
+		})
+	}
+
+	return nil
+}
+
+// ruleSpec is the normalized, comparable form of a security group rule.
+type ruleSpec struct {
+	direction           string
+	protocol            int
+	fromPort            int
+	toPort              int
+	icmpType            int
+	icmpCode            int
+	ipRange             string
+	remoteSecurityGroup string
+}
+
+func newRuleSpec(direction string, protocol, fromPort, toPort, icmpType, icmpCode int, ipRange, remote string) ruleSpec {
+	spec := ruleSpec{
+		direction:           strings.ToLower(direction),
+		protocol:            protocol,
+		remoteSecurityGroup: strings.ToLower(remote),
+	}
+
+	switch protocol {
+	case compute.ProtocolICMP:
+		spec.icmpType, spec.icmpCode = icmpType, icmpCode
+	case compute.ProtocolTCP, compute.ProtocolUDP:
+		spec.fromPort, spec.toPort = fromPort, toPort
+	}
+
+	if _, cidr, err := net.ParseCIDR(ipRange); err == nil {
+		ipRange = cidr.String()
+	}
+
+	if ipRange != compute.IPRangeAny.String() {
+		spec.ipRange = ipRange
+	}
+
+	return spec
+}
+
+func (r ruleSpec) String() string {
+	rule := compute.SecurityGroupRule{
+		Direction: r.direction,
+		Protocol:  r.protocol,
+		FromPort:  r.fromPort,
+		ToPort:    r.toPort,
+		ICMPType:  r.icmpType,
+		ICMPCode:  r.icmpCode,
+		IPRange:   r.ipRange,
+	}
+	rule.RemoteSecurityGroup.Name = r.remoteSecurityGroup
+
+	return rule.String()
+}
+
+func (p *planner) planSecurityGroupRules(ctx context.Context, m Manifest) error {
+	for _, item := range m.SecurityGroups {
+		securityGroup, _ := lookup(p.state.securityGroups, item.Name, securityGroupName)
+
+		liveRules, err := p.state.fetchSecurityGroupRules(ctx, securityGroup)
+		if err != nil {
+			return fmt.Errorf("security group %q: %w", item.Name, err)
+		}
+
+		matched := make([]bool, len(liveRules))
+
+	nextRule:
+		for _, rule := range item.Rules {
+			protocol, found := compute.ProtocolIDs[strings.ToLower(rule.Protocol)]
+			if !found {
+				return fmt.Errorf("security group %q: invalid protocol: %s", item.Name, rule.Protocol)
+			}
+
+			remote := ""
+			if rule.RemoteSecurityGroup != "" {
+				remoteSecurityGroup, err := p.state.securityGroup(rule.RemoteSecurityGroup)
+				if err != nil {
+					return fmt.Errorf("security group %q: %w", item.Name, err)
+				}
+
+				remote = remoteSecurityGroup.Name
+			}
+
+			spec := newRuleSpec(rule.Direction, protocol, rule.FromPort, rule.ToPort, rule.ICMPType, rule.ICMPCode, rule.IPRange, remote)
+
+			for idx, live := range liveRules {
+				liveSpec := newRuleSpec(live.Direction, live.Protocol, live.FromPort, live.ToPort, live.ICMPType, live.ICMPCode, live.IPRange, live.RemoteSecurityGroup.Name)
+				if !matched[idx] && liveSpec == spec {
+					matched[idx] = true
+					continue nextRule
+				}
+			}
+
+			groupName := item.Name
+			p.add(Change{
+				Action:  ActionCreate,
+				Kind:    "security group rule",
+				Name:    groupName,
+				Details: []string{spec.String()},
+				apply: func(ctx context.Context) error {
+					securityGroup, _ := lookup(p.state.securityGroups, groupName, securityGroupName)
+
+					data := compute.SecurityGroupRuleCreate{
+						Direction: spec.direction,
+						Protocol:  spec.protocol,
+						FromPort:  spec.fromPort,
+						ToPort:    spec.toPort,
+						ICMPType:  spec.icmpType,
+						ICMPCode:  spec.icmpCode,
+						IPRange:   spec.ipRange,
+					}
+
+					if spec.remoteSecurityGroup != "" {
+						remoteSecurityGroup, err := p.state.securityGroup(spec.remoteSecurityGroup)
+						if err != nil {
+							return err
+						}
+
+						data.RemoteSecurityGroupID = remoteSecurityGroup.ID
+					}
+
+					_, err := compute.NewSecurityGroupRuleService(p.state.client, securityGroup.ID).Create(ctx, data)
+					if err != nil {
+						return fmt.Errorf("create security group rule: %w", err)
+					}
+
+					return nil
+				},
+			})
+		}
+
+		if !p.prune {
+			continue
+		}
+
+		for idx, live := range liveRules {
+			if matched[idx] {
+				continue
+			}
+
+			securityGroupID, ruleID := securityGroup.ID, live.ID
+			p.add(Change{
+				Action:  ActionDelete,
+				Kind:    "security group rule",
+				Name:    item.Name,
+				Details: []string{live.String()},
+				apply: func(ctx context.Context) error {
+					err := compute.NewSecurityGroupRuleService(p.state.client, securityGroupID).Delete(ctx, ruleID)
+					if err != nil {
+						return fmt.Errorf("delete security group rule: %w", err)
+					}
+
+					return nil
+				},
+			})
+		}
+	}
+
+	return nil
+}
+
+func (p *planner) planRouters(ctx context.Context, m Manifest) error {
+	for _, item := range m.Routers {
+		location, err := p.state.location(item.Location)
+		if err != nil {
+			return fmt.Errorf("router %q: %w", item.Name, err)
+		}
+
+		live, ok := lookup(p.state.routers, item.Name, routerName)
+		if !ok {
+			data := compute.RouterCreate{
+				Name:        item.Name,
+				Description: item.Description,
+				LocationID:  location.ID,
+				Public:      item.Public,
+			}
+
+			placeholder := compute.Router{Name: item.Name}
+			placeholder.Location.ID = location.ID
+			placeholder.Location.Name = location.Name
+
+			p.state.routers = append(p.state.routers, placeholder)
+			p.add(Change{
+				Action:  ActionCreate,
+				Kind:    "router",
+				Name:    item.Name,
+				Details: []string{"location: " + location.Name},
+				apply: func(ctx context.Context) error {
+					router, err := compute.NewRouterService(p.state.client).Create(ctx, data)
+					if err != nil {
+						return fmt.Errorf("create router: %w", err)
+					}
+
+					p.state.routers = upsert(p.state.routers, router, routerName)
+					return nil
+				},
+			})
+		} else {
+			if live.Location.ID != location.ID {
+				return fmt.Errorf("router %q: location cannot be changed from %s to %s", item.Name, live.Location.Name, location.Name)
+			}
+
+			var details []string
+			data := compute.RouterUpdate{}
+
+			if item.Description != "" && item.Description != live.Description {
+				data.Description = item.Description
+				details = append(details, detail("description", live.Description, item.Description))
+			}
+
+			// the api ignores public = false on updates, so routers can only be made public
+			if item.Public && !live.Public {
+				data.Public = true
+				details = append(details, detail("public", live.Public, item.Public))
+			}
+
+			if len(details) != 0 {
+				id := live.ID
+				p.add(Change{
+					Action:  ActionUpdate,
+					Kind:    "router",
+					Name:    item.Name,
+					Details: details,
+					apply: func(ctx context.Context) error {
+						router, err := compute.NewRouterService(p.state.client).Update(ctx, id, data)
+						if err != nil {
+							return fmt.Errorf("update router: %w", err)
+						}
+
+						p.state.routers = upsert(p.state.routers, router, routerName)
+						return nil
+					},
+				})
+			}
+		}
+
+		if err = p.planRouterInterfaces(ctx, item); err != nil {
+			return fmt.Errorf("router %q: %w", item.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *planner) planRouterInterfaces(ctx context.Context, item Router) error {
+	router, _ := lookup(p.state.routers, item.Name, routerName)
+
+	liveInterfaces, err := p.state.fetchRouterInterfaces(ctx, router)
+	if err != nil {
+		return err
+	}
+
+	matched := make([]bool, len(liveInterfaces))
+
+nextInterface:
+	for _, iface := range item.Interfaces {
+		network, err := p.state.network(iface.Network)
+		if err != nil {
+			return err
+		}
+
+		for idx, live := range liveInterfaces {
+			if matched[idx] || !strings.EqualFold(live.Network.Name, network.Name) {
+				continue
+			}
+
+			if iface.PrivateIP == "" || iface.PrivateIP == live.PrivateIP {
+				matched[idx] = true
+				continue nextInterface
+			}
+		}
+
+		details := []string{"network: " + network.Name}
+		if iface.PrivateIP != "" {
+			details = append(details, "private ip: "+iface.PrivateIP)
+		}
+
+		routerName, networkName, privateIP := item.Name, network.Name, iface.PrivateIP
+		p.add(Change{
+			Action:  ActionCreate,
+			Kind:    "router interface",
+			Name:    routerName,
+			Details: details,
+			apply: func(ctx context.Context) error {
+				router, err := p.state.router(routerName)
+				if err != nil {
+					return err
+				}
+
+				network, err := p.state.network(networkName)
+				if err != nil {
+					return err
+				}
+
+				data := compute.RouterInterfaceCreate{
+					NetworkID: network.ID,
+					PrivateIP: privateIP,
+				}
+
+				_, err = compute.NewRouterInterfaceService(p.state.client, router.ID).Create(ctx, data)
+				if err != nil {
+					return fmt.Errorf("create router interface: %w", err)
+				}
+
+				return nil
+			},
+		})
+	}
+
+	if !p.prune {
+		return nil
+	}
+
+	for idx, live := range liveInterfaces {
+		if matched[idx] {
+			continue
+		}
+
+		routerID, interfaceID := router.ID, live.ID
+		p.add(Change{
+			Action:  ActionDelete,
+			Kind:    "router interface",
+			Name:    item.Name,
+			Details: []string{"network: " + live.Network.Name, "private ip: " + live.PrivateIP},
+			apply: func(ctx context.Context) error {
+				err := compute.NewRouterInterfaceService(p.state.client, routerID).Delete(ctx, interfaceID)
+				if err != nil {
+					return fmt.Errorf("delete router interface: %w", err)
+				}
+
+				return nil
+			},
+		})
+	}
+
+	return nil
+}
+
+func (p *planner) planServers(ctx context.Context, m Manifest) error {
+	for _, item := range m.Servers {
+		location, err := p.state.location(item.Location)
+		if err != nil {
+			return fmt.Errorf("server %q: %w", item.Name, err)
+		}
+
+		image, err := p.state.image(item.Image)
+		if err != nil {
+			return fmt.Errorf("server %q: %w", item.Name, err)
+		}
+
+		if !image.AvailableAt(location) {
+			return fmt.Errorf("server %q: image %s is not available in location %s", item.Name, image, location.Name)
+		}
+
+		product, err := p.state.product(item.Product)
+		if err != nil {
+			return fmt.Errorf("server %q: %w", item.Name, err)
+		}
+
+		if item.Network != "" {
+			network, err := p.state.network(item.Network)
+			if err != nil {
+				return fmt.Errorf("server %q: %w", item.Name, err)
+			}
+
+			if network.Location.ID != location.ID {
+				return fmt.Errorf("server %q: network %s is not available in location %s", item.Name, network.Name, location.Name)
+			}
+		}
+
+		if item.KeyPair != "" {
+			if _, err := p.state.keyPair(item.KeyPair); err != nil {
+				return fmt.Errorf("server %q: %w", item.Name, err)
+			}
+		} else if !image.IsWindows() {
+			return fmt.Errorf("server %q: key pair is required for non-windows images", item.Name)
+		}
+
+		live, ok := lookup(p.state.servers, item.Name, serverName)
+		if ok {
+			if live.Location.ID != location.ID {
+				return fmt.Errorf("server %q: location cannot be changed from %s to %s", item.Name, live.Location.Name, location.Name)
+			}
+
+			if live.Image.ID != image.ID {
+				return fmt.Errorf("server %q: image cannot be changed from %s to %s", item.Name, compute.Image{Image: live.Image}, image)
+			}
+
+			if live.Product.ID == product.ID {
+				continue
+			}
+
+			id := live.ID
+			data := compute.ServerUpgrade{
+				ProductID: product.ID,
+			}
+
+			p.add(Change{
+				Action:  ActionUpdate,
+				Kind:    "server",
+				Name:    item.Name,
+				Details: []string{detail("product", live.Product.Name, product.Name)},
+				apply: func(ctx context.Context) error {
+					service := compute.NewServerService(p.state.client)
+
+					ordering, err := service.Upgrade(ctx, id, data)
+					if err != nil {
+						return fmt.Errorf("upgrade server: %w", err)
+					}
+
+					if _, err = commands.WaitForOrder(ctx, "Upgrading server", ordering); err != nil {
+						return fmt.Errorf("wait for order: %w", err)
+					}
+
+					server, err := service.Get(ctx, id)
+					if err != nil {
+						return fmt.Errorf("fetch server: %w", err)
+					}
+
+					p.state.servers = upsert(p.state.servers, server, serverName)
+					return nil
+				},
+			})
+			continue
+		}
+
+		cloudInit := ""
+		if item.CloudInitFile != "" {
+			data, err := os.ReadFile(item.CloudInitFile)
+			if err != nil {
+				return fmt.Errorf("server %q: read cloud init file: %w", item.Name, err)
+			}
+
+			part := cloudinit.Part{
+				Name:     strings.TrimSuffix(filepath.Base(item.CloudInitFile), ".tmpl"),
+				Content:  data,
+				Template: strings.HasSuffix(item.CloudInitFile, ".tmpl"),
+			}
+
+			cloudInit, err = cloudinit.Build([]cloudinit.Part{part}, cloudinit.TemplateData{Name: item.Name, Location: location.Name}, viper.GetInt(commands.KeyCloudInitMaxSize))
+			if err != nil {
+				return fmt.Errorf("server %q: cloud init: %w", item.Name, err)
+			}
+		}
+
+		attachExternalIP := item.AttachExternalIP == nil || *item.AttachExternalIP
+		p.externalIPs[strings.ToLower(item.Name)] = attachExternalIP
+
+		placeholder := compute.Server{Name: item.Name}
+		placeholder.Location.ID = location.ID
+		placeholder.Location.Name = location.Name
+
+		p.state.servers = append(p.state.servers, placeholder)
+
+		item, isWindows := item, image.IsWindows()
+		data := compute.ServerCreate{
+			Name:             item.Name,
+			LocationID:       location.ID,
+			ImageID:          image.ID,
+			ProductID:        product.ID,
+			AttachExternalIP: attachExternalIP,
+			PrivateIP:        item.PrivateIP,
+			CloudInit:        cloudInit,
+		}
+
+		p.add(Change{
+			Action:  ActionCreate,
+			Kind:    "server",
+			Name:    item.Name,
+			Details: []string{"location: " + location.Name, "image: " + image.String(), "product: " + product.Name},
+			apply: func(ctx context.Context) error {
+				if item.Network != "" {
+					network, err := p.state.network(item.Network)
+					if err != nil {
+						return err
+					}
+
+					data.NetworkID = network.ID
+				}
+
+				if item.KeyPair != "" {
+					keyPair, err := p.state.keyPair(item.KeyPair)
+					if err != nil {
+						return err
+					}
+
+					data.KeyPairID = keyPair.ID
+				}
+
+				if isWindows {
+					password, err := console.Password(commands.Stderr, fmt.Sprintf("Windows User Password for %s", item.Name), compute.CheckWindowsPassword)
+					if err != nil {
+						return fmt.Errorf("read user password: %w", err)
+					}
+
+					data.Password = password
+				}
+
+				service := compute.NewServerService(p.state.client)
+
+				ordering, err := service.Create(ctx, data)
+				if err != nil {
+					return fmt.Errorf("create server: %w", err)
+				}
+
+				order, err := commands.WaitForOrder(ctx, "Creating server", ordering)
+				if err != nil {
+					return fmt.Errorf("wait for order: %w", err)
+				}
+
+				server, err := service.Get(ctx, order.Product.ID)
+				if err != nil {
+					return fmt.Errorf("fetch server: %w", err)
+				}
+
+				p.state.servers = upsert(p.state.servers, server, serverName)
+				return nil
+			},
+		})
+	}
+
+	return nil
+}
+
+func (p *planner) planVolumes(ctx context.Context, m Manifest) error {
+	for _, item := range m.Volumes {
+		var server compute.Server
+		if item.AttachTo != "" {
+			var err error
+			if server, err = p.state.server(item.AttachTo); err != nil {
+				return fmt.Errorf("volume %q: %w", item.Name, err)
+			}
+		}
+
+		locationID, locationName := server.Location.ID, server.Location.Name
+		if item.Location != "" {
+			location, err := p.state.location(item.Location)
+			if err != nil {
+				return fmt.Errorf("volume %q: %w", item.Name, err)
+			}
+
+			locationID, locationName = location.ID, location.Name
+		}
+
+		serverTerm := item.AttachTo
+
+		live, ok := lookup(p.state.volumes, item.Name, volumeName)
+		if !ok {
+			data := compute.VolumeCreate{
+				Name:       item.Name,
+				Size:       item.Size,
+				LocationID: locationID,
+			}
+
+			details := []string{"location: " + locationName, fmt.Sprintf("size: %d GiB", item.Size)}
+			if serverTerm != "" {
+				details = append(details, "attached to: "+server.Name)
+			}
+
+			p.state.volumes = append(p.state.volumes, compute.Volume{Name: item.Name})
+			p.add(Change{
+				Action:  ActionCreate,
+				Kind:    "volume",
+				Name:    item.Name,
+				Details: details,
+				apply: func(ctx context.Context) error {
+					if serverTerm != "" {
+						server, err := p.state.server(serverTerm)
+						if err != nil {
+							return err
+						}
+
+						data.InstanceID = server.ID
+					}
+
+					volume, err := compute.NewVolumeService(p.state.client).Create(ctx, data)
+					if err != nil {
+						return fmt.Errorf("create volume: %w", err)
+					}
+
+					p.state.volumes = upsert(p.state.volumes, volume, volumeName)
+					return nil
+				},
+			})
+			continue
+		}
+
+		if live.Location.ID != locationID {
+			return fmt.Errorf("volume %q: location cannot be changed from %s to %s", item.Name, live.Location.Name, locationName)
+		}
+
+		if item.Size < live.Size {
+			return fmt.Errorf("volume %q: size cannot be reduced from %d GiB to %d GiB", item.Name, live.Size, item.Size)
+		}
+
+		var details []string
+
+		expand := item.Size > live.Size
+		if expand {
+			details = append(details, detail("size", fmt.Sprint(live.Size, " GiB"), fmt.Sprint(item.Size, " GiB")))
+		}
+
+		attach := serverTerm != "" && !strings.EqualFold(live.AttachedTo.Name, server.Name)
+		if attach {
+			details = append(details, detail("attached to", live.AttachedTo.Name, server.Name))
+		}
+
+		if len(details) == 0 {
+			continue
+		}
+
+		volumeID, size, attachedTo := live.ID, item.Size, live.AttachedTo.ID
+		p.add(Change{
+			Action:  ActionUpdate,
+			Kind:    "volume",
+			Name:    item.Name,
+			Details: details,
+			apply: func(ctx context.Context) error {
+				service := compute.NewVolumeService(p.state.client)
+
+				if expand {
+					volume, err := service.Expand(ctx, volumeID, compute.VolumeExpand{Size: size})
+					if err != nil {
+						return fmt.Errorf("expand volume: %w", err)
+					}
+
+					p.state.volumes = upsert(p.state.volumes, volume, volumeName)
+				}
+
+				if !attach {
+					return nil
+				}
+
+				if attachedTo != 0 {
+					if err := service.Detach(ctx, volumeID, attachedTo); err != nil {
+						return fmt.Errorf("detach volume: %w", err)
+					}
+				}
+
+				server, err := p.state.server(serverTerm)
+				if err != nil {
+					return err
+				}
+
+				volume, err := service.Attach(ctx, volumeID, compute.VolumeAttach{InstanceID: server.ID})
+				if err != nil {
+					return fmt.Errorf("attach volume: %w", err)
+				}
+
+				p.state.volumes = upsert(p.state.volumes, volume, volumeName)
+				return nil
+			},
+		})
+	}
+
+	return nil
+}
+
+func (p *planner) planElasticIPs(ctx context.Context, m Manifest) error {
+	for _, item := range m.ElasticIPs {
+		var server compute.Server
+		if item.AttachTo != "" {
+			var err error
+			if server, err = p.state.server(item.AttachTo); err != nil {
+				return fmt.Errorf("elastic ip: %w", err)
+			}
+		}
+
+		serverTerm := item.AttachTo
+
+		if item.PublicIP != "" {
+			live, ok := lookup(p.state.elasticIPs, item.PublicIP, elasticIPName)
+			if !ok {
+				return fmt.Errorf("elastic ip %s does not exist, specific addresses cannot be allocated", item.PublicIP)
+			}
+
+			if serverTerm == "" || strings.EqualFold(live.Attachment.Name, server.Name) {
+				continue
+			}
+
+			elasticIPID, attachedTo := live.ID, live.Attachment.ID
+			p.add(Change{
+				Action:  ActionUpdate,
+				Kind:    "elastic ip",
+				Name:    live.PublicIP,
+				Details: []string{detail("attached to", live.Attachment.Name, server.Name)},
+				apply: func(ctx context.Context) error {
+					service := compute.NewElasticIPService(p.state.client)
+
+					if attachedTo != 0 {
+						if err := service.Detach(ctx, attachedTo, elasticIPID); err != nil {
+							return fmt.Errorf("detach elastic ip: %w", err)
+						}
+					}
+
+					return p.attachElasticIP(ctx, elasticIPID, serverTerm)
+				},
+			})
+
+			live.Attachment.Name = server.Name
+			p.state.elasticIPs = upsert(p.state.elasticIPs, live, elasticIPName)
+			p.externalIPs[strings.ToLower(server.Name)] = true
+			continue
+		}
+
+		if serverHasPublicIP(server) || p.externalIPs[strings.ToLower(server.Name)] {
+			continue
+		}
+
+		if _, ok := lookup(p.state.elasticIPs, server.Name, func(e compute.ElasticIP) string { return e.Attachment.Name }); ok {
+			continue
+		}
+
+		locationID, locationName := server.Location.ID, server.Location.Name
+		if item.Location != "" {
+			location, err := p.state.location(item.Location)
+			if err != nil {
+				return fmt.Errorf("elastic ip: %w", err)
+			}
+
+			locationID, locationName = location.ID, location.Name
+		}
+
+		data := compute.ElasticIPCreate{
+			LocationID: locationID,
+		}
+
+		p.add(Change{
+			Action:  ActionCreate,
+			Kind:    "elastic ip",
+			Name:    server.Name,
+			Details: []string{"location: " + locationName, "attached to: " + server.Name},
+			apply: func(ctx context.Context) error {
+				elasticIP, err := compute.NewElasticIPService(p.state.client).Create(ctx, data)
+				if err != nil {
+					return fmt.Errorf("create elastic ip: %w", err)
+				}
+
+				p.state.elasticIPs = upsert(p.state.elasticIPs, elasticIP, elasticIPName)
+				return p.attachElasticIP(ctx, elasticIP.ID, serverTerm)
+			},
+		})
+
+		p.externalIPs[strings.ToLower(server.Name)] = true
+	}
+
+	return nil
+}
+
+func (p *planner) attachElasticIP(ctx context.Context, elasticIPID int, serverTerm string) error {
+	server, err := p.state.server(serverTerm)
+	if err != nil {
+		return err
+	}
+
+	data := compute.ElasticIPAttach{
+		ElasticIPID: elasticIPID,
+	}
+
+searchFreeNetworkInterface:
+	for _, network := range server.Networks {
+		for _, iface := range network.Interfaces {
+			if iface.PublicIP == "" {
+				data.NetworkInterfaceID = iface.ID
+				break searchFreeNetworkInterface
+			}
+		}
+	}
+
+	if data.NetworkInterfaceID == 0 {
+		return fmt.Errorf("server has no free network interface to attach the elastic ip to")
+	}
+
+	elasticIP, err := compute.NewElasticIPService(p.state.client).Attach(ctx, server.ID, data)
+	if err != nil {
+		return fmt.Errorf("attach elastic ip: %w", err)
+	}
+
+	p.state.elasticIPs = upsert(p.state.elasticIPs, elasticIP, elasticIPName)
+	return nil
+}
+
+func (p *planner) planLoadBalancers(ctx context.Context, m Manifest) error {
+	for _, item := range m.LoadBalancers {
+		network, err := p.state.network(item.Network)
+		if err != nil {
+			return fmt.Errorf("load balancer %q: %w", item.Name, err)
+		}
+
+		if _, ok := lookup(p.state.loadBalancers, item.Name, loadBalancerName); ok {
+			// the name is the only attribute of a load balancer which can be changed
+			continue
+		}
+
+		networkTerm := item.Network
+		data := compute.LoadBalancerCreate{
+			Name:             item.Name,
+			LocationID:       network.Location.ID,
+			AttachExternalIP: !item.Internal,
+			PrivateIP:        item.PrivateIP,
+		}
+
+		p.state.loadBalancers = append(p.state.loadBalancers, compute.LoadBalancer{Name: item.Name})
+		p.add(Change{
+			Action:  ActionCreate,
+			Kind:    "load balancer",
+			Name:    item.Name,
+			Details: []string{"network: " + network.Name},
+			apply: func(ctx context.Context) error {
+				network, err := p.state.network(networkTerm)
+				if err != nil {
+					return err
+				}
+
+				data.NetworkID = network.ID
+
+				service := compute.NewLoadBalancerService(p.state.client)
+
+				ordering, err := service.Create(ctx, data)
+				if err != nil {
+					return fmt.Errorf("create load balancer: %w", err)
+				}
+
+				order, err := commands.WaitForOrder(ctx, "Creating load balancer", ordering)
+				if err != nil {
+					return fmt.Errorf("wait for order: %w", err)
+				}
+
+				loadBalancer, err := service.Get(ctx, order.Product.ID)
+				if err != nil {
+					return fmt.Errorf("fetch load balancer: %w", err)
+				}
+
+				p.state.loadBalancers = upsert(p.state.loadBalancers, loadBalancer, loadBalancerName)
+				return nil
+			},
+		})
+	}
+
+	return nil
+}
+
+func serverHasPublicIP(server compute.Server) bool {
+	for _, network := range server.Networks {
+		for _, iface := range network.Interfaces {
+			if iface.PublicIP != "" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func detail(field string, from, to interface{}) string {
+	return fmt.Sprintf("%s: %v -> %v", field, from, to)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
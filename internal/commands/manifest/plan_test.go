@@ -0,0 +1,195 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flowswiss/goclient"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/apitest"
+)
+
+const testPublicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl deploy@example"
+
+// testManifest returns a manifest in which every resource references one created before it.
+func testManifest() Manifest {
+	attachExternalIP := false
+
+	return Manifest{
+		KeyPairs: []KeyPair{{Name: "deploy", PublicKey: testPublicKey}},
+		Networks: []Network{{Name: "backend", Location: "ALP1", CIDR: "172.31.0.0/24"}},
+		Servers: []Server{{
+			Name:             "web",
+			Location:         "ALP1",
+			Image:            "linux-ubuntu-22.04-lts",
+			Product:          "b1.1x1",
+			Network:          "backend",
+			KeyPair:          "deploy",
+			AttachExternalIP: &attachExternalIP,
+		}},
+		Volumes:    []Volume{{Name: "data", Size: 20, AttachTo: "web"}},
+		ElasticIPs: []ElasticIP{{AttachTo: "web"}},
+	}
+}
+
+// useTestAPI makes the planner talk to a new in-memory api.
+func useTestAPI(t *testing.T) {
+	server := apitest.NewTestServer(apitest.Options{
+		Token:       "test",
+		OrderDelay:  10 * time.Millisecond,
+		ActionDelay: 10 * time.Millisecond,
+	})
+	t.Cleanup(server.Close)
+
+	client := commands.Config.Client
+	t.Cleanup(func() { commands.Config.Client = client })
+
+	commands.Config.Client = goclient.NewClient(goclient.WithBase(server.URL+"/"), goclient.WithToken("test"))
+}
+
+func planChanges(t *testing.T, m Manifest) []Change {
+	t.Helper()
+
+	changes, err := Plan(context.Background(), m, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return changes
+}
+
+func checkChanges(t *testing.T, changes []Change, expected ...string) {
+	t.Helper()
+
+	actual := make([]string, len(changes))
+	for idx, change := range changes {
+		actual[idx] = fmt.Sprintf("%s %s %s", change.Action, change.Kind, change.Name)
+		if len(change.Details) != 0 {
+			actual[idx] += " (" + strings.Join(change.Details, ", ") + ")"
+		}
+	}
+
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected changes:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	}
+}
+
+func TestPlanCreate(t *testing.T) {
+	useTestAPI(t)
+
+	checkChanges(t, planChanges(t, testManifest()),
+		"create key pair deploy",
+		"create network backend (location: ALP1, cidr: 172.31.0.0/24)",
+		"create server web (location: ALP1, image: Ubuntu 22.04 LTS, product: b1.1x1)",
+		"create volume data (location: ALP1, size: 20 GiB, attached to: web)",
+		"create elastic ip web (location: ALP1, attached to: web)",
+	)
+}
+
+func TestPlanNoop(t *testing.T) {
+	useTestAPI(t)
+
+	m := testManifest()
+	if err := Apply(context.Background(), planChanges(t, m)); err != nil {
+		t.Fatal(err)
+	}
+
+	checkChanges(t, planChanges(t, m))
+}
+
+func TestPlanUpdate(t *testing.T) {
+	useTestAPI(t)
+
+	m := testManifest()
+	if err := Apply(context.Background(), planChanges(t, m)); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Networks[0].Description = "application servers"
+	m.Servers[0].Product = "b1.2x4"
+
+	changes := planChanges(t, m)
+	checkChanges(t, changes,
+		"update network backend (description:  -> application servers)",
+		"update server web (product: b1.1x1 -> b1.2x4)",
+	)
+
+	if err := Apply(context.Background(), changes); err != nil {
+		t.Fatal(err)
+	}
+
+	checkChanges(t, planChanges(t, m))
+}
+
+func TestPlanReferences(t *testing.T) {
+	useTestAPI(t)
+
+	// references to resources of the manifest are resolved before they exist, ignoring the case of the names, and a
+	// second elastic ip for the same server is not planned again
+	m := testManifest()
+	m.Volumes = append(m.Volumes, Volume{Name: "logs", Size: 10, AttachTo: "WEB"})
+	m.ElasticIPs = append(m.ElasticIPs, ElasticIP{AttachTo: "Web"})
+
+	changes := planChanges(t, m)
+	checkChanges(t, changes,
+		"create key pair deploy",
+		"create network backend (location: ALP1, cidr: 172.31.0.0/24)",
+		"create server web (location: ALP1, image: Ubuntu 22.04 LTS, product: b1.1x1)",
+		"create volume data (location: ALP1, size: 20 GiB, attached to: web)",
+		"create volume logs (location: ALP1, size: 10 GiB, attached to: web)",
+		"create elastic ip web (location: ALP1, attached to: web)",
+	)
+
+	if err := Apply(context.Background(), changes); err != nil {
+		t.Fatal(err)
+	}
+
+	checkChanges(t, planChanges(t, m))
+
+	tests := []struct {
+		name   string
+		modify func(m *Manifest)
+		err    string
+	}{
+		{
+			name:   "unknown network",
+			modify: func(m *Manifest) { m.Servers[0].Network = "frontend" },
+			err:    "find network",
+		},
+		{
+			name: "unknown key pair",
+			modify: func(m *Manifest) {
+				m.Servers = append(m.Servers, Server{Name: "db", Location: "ALP1", Image: "linux-debian-11", Product: "b1.1x1", KeyPair: "ops"})
+			},
+			err: "find key pair",
+		},
+		{
+			name:   "unknown server",
+			modify: func(m *Manifest) { m.Volumes[0].AttachTo = "db" },
+			err:    "find server",
+		},
+		{
+			name: "unknown elastic ip",
+			modify: func(m *Manifest) {
+				m.ElasticIPs = append(m.ElasticIPs, ElasticIP{PublicIP: "203.0.113.10", Location: "ALP1"})
+			},
+			err: "elastic ip 203.0.113.10 does not exist",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := testManifest()
+			test.modify(&m)
+
+			_, err := Plan(context.Background(), m, false)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/flowswiss/goclient"
+
+	"github.com/cloudbit-ch/cli/v2/pkg/api/common"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/compute"
+	"github.com/cloudbit-ch/cli/v2/pkg/filter"
+)
+
+// state holds the live resources of the organization. While planning, resources which are going to be created are
+// added as placeholders without an id, so later resources are able to reference them. While applying, the placeholders
+// get replaced with the actually created resources.
+type state struct {
+	client goclient.Client
+
+	locations []common.Location
+	images    []compute.Image
+	products  []common.Product
+
+	keyPairs           []compute.KeyPair
+	networks           []compute.Network
+	securityGroups     []compute.SecurityGroup
+	securityGroupRules map[string][]compute.SecurityGroupRule
+	routers            []compute.Router
+	routerInterfaces   map[string][]compute.RouterInterface
+	servers            []compute.Server
+	volumes            []compute.Volume
+	elasticIPs         []compute.ElasticIP
+	loadBalancers      []compute.LoadBalancer
+}
+
+func fetchState(ctx context.Context, client goclient.Client) (*state, error) {
+	s := &state{
+		client:             client,
+		securityGroupRules: map[string][]compute.SecurityGroupRule{},
+		routerInterfaces:   map[string][]compute.RouterInterface{},
+	}
+
+	var err error
+
+	if s.locations, err = common.Locations(ctx, client); err != nil {
+		return nil, fmt.Errorf("fetch locations: %w", err)
+	}
+
+	if s.images, err = compute.Images(ctx, client); err != nil {
+		return nil, fmt.Errorf("fetch images: %w", err)
+	}
+
+	if s.products, err = common.ProductsByType(ctx, client, common.ProductTypeComputeServer); err != nil {
+		return nil, fmt.Errorf("fetch products: %w", err)
+	}
+
+	if s.keyPairs, err = compute.NewKeyPairService(client).List(ctx); err != nil {
+		return nil, fmt.Errorf("fetch key pairs: %w", err)
+	}
+
+	if s.networks, err = compute.NewNetworkService(client).List(ctx); err != nil {
+		return nil, fmt.Errorf("fetch networks: %w", err)
+	}
+
+	if s.securityGroups, err = compute.NewSecurityGroupService(client).List(ctx); err != nil {
+		return nil, fmt.Errorf("fetch security groups: %w", err)
+	}
+
+	if s.routers, err = compute.NewRouterService(client).List(ctx); err != nil {
+		return nil, fmt.Errorf("fetch routers: %w", err)
+	}
+
+	if s.servers, err = compute.NewServerService(client).List(ctx); err != nil {
+		return nil, fmt.Errorf("fetch servers: %w", err)
+	}
+
+	if s.volumes, err = compute.NewVolumeService(client).List(ctx); err != nil {
+		return nil, fmt.Errorf("fetch volumes: %w", err)
+	}
+
+	if s.elasticIPs, err = compute.NewElasticIPService(client).List(ctx); err != nil {
+		return nil, fmt.Errorf("fetch elastic ips: %w", err)
+	}
+
+	if s.loadBalancers, err = compute.NewLoadBalancerService(client).List(ctx); err != nil {
+		return nil, fmt.Errorf("fetch load balancers: %w", err)
+	}
+
+	return s, nil
+}
+
+func (s *state) location(term string) (common.Location, error) {
+	location, err := filter.FindOne(s.locations, term)
+	if err != nil {
+		return common.Location{}, fmt.Errorf("find location: %w", err)
+	}
+
+	return location, nil
+}
+
+func (s *state) image(term string) (compute.Image, error) {
+	image, err := filter.FindOne(s.images, term)
+	if err != nil {
+		return compute.Image{}, fmt.Errorf("find image: %w", err)
+	}
+
+	return image, nil
+}
+
+func (s *state) product(term string) (common.Product, error) {
+	product, err := filter.FindOne(s.products, term)
+	if err != nil {
+		return common.Product{}, fmt.Errorf("find product: %w", err)
+	}
+
+	return product, nil
+}
+
+func (s *state) keyPair(term string) (compute.KeyPair, error) {
+	keyPair, err := filter.FindOne(s.keyPairs, term)
+	if err != nil {
+		return compute.KeyPair{}, fmt.Errorf("find key pair: %w", err)
+	}
+
+	return keyPair, nil
+}
+
+func (s *state) network(term string) (compute.Network, error) {
+	network, err := filter.FindOne(s.networks, term)
+	if err != nil {
+		return compute.Network{}, fmt.Errorf("find network: %w", err)
+	}
+
+	return network, nil
+}
+
+func (s *state) securityGroup(term string) (compute.SecurityGroup, error) {
+	securityGroup, err := filter.FindOne(s.securityGroups, term)
+	if err != nil {
+		return compute.SecurityGroup{}, fmt.Errorf("find security group: %w", err)
+	}
+
+	return securityGroup, nil
+}
+
+func (s *state) router(term string) (compute.Router, error) {
+	router, err := filter.FindOne(s.routers, term)
+	if err != nil {
+		return compute.Router{}, fmt.Errorf("find router: %w", err)
+	}
+
+	return router, nil
+}
+
+func (s *state) server(term string) (compute.Server, error) {
+	server, err := filter.FindOne(s.servers, term)
+	if err != nil {
+		return compute.Server{}, fmt.Errorf("find server: %w", err)
+	}
+
+	return server, nil
+}
+
+// fetchSecurityGroupRules loads the rules of the security group unless they are already known.
+func (s *state) fetchSecurityGroupRules(ctx context.Context, securityGroup compute.SecurityGroup) ([]compute.SecurityGroupRule, error) {
+	if rules, ok := s.securityGroupRules[securityGroup.Name]; ok || securityGroup.ID == 0 {
+		return rules, nil
+	}
+
+	rules, err := compute.NewSecurityGroupRuleService(s.client, securityGroup.ID).List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch security group rules: %w", err)
+	}
+
+	s.securityGroupRules[securityGroup.Name] = rules
+	return rules, nil
+}
+
+// fetchRouterInterfaces loads the interfaces of the router unless they are already known.
+func (s *state) fetchRouterInterfaces(ctx context.Context, router compute.Router) ([]compute.RouterInterface, error) {
+	if interfaces, ok := s.routerInterfaces[router.Name]; ok || router.ID == 0 {
+		return interfaces, nil
+	}
+
+	interfaces, err := compute.NewRouterInterfaceService(s.client, router.ID).List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch router interfaces: %w", err)
+	}
+
+	s.routerInterfaces[router.Name] = interfaces
+	return interfaces, nil
+}
+
+// lookup searches for the item with exactly the given name. In contrast to filter.FindOne, which is used to resolve
+// references, partial matches are never considered, since they would identify the wrong resource.
+func lookup[T any](items []T, name string, nameOf func(T) string) (T, bool) {
+	for _, item := range items {
+		if strings.EqualFold(nameOf(item), name) {
+			return item, true
+		}
+	}
+
+	var empty T
+	return empty, false
+}
+
+// upsert replaces the item with the same name or appends it if no such item exists yet.
+func upsert[T any](items []T, item T, nameOf func(T) string) []T {
+	for idx := range items {
+		if strings.EqualFold(nameOf(items[idx]), nameOf(item)) {
+			items[idx] = item
+			return items
+		}
+	}
+
+	return append(items, item)
+}
+
+func keyPairName(k compute.KeyPair) string             { return k.Name }
+func networkName(n compute.Network) string             { return n.Name }
+func securityGroupName(s compute.SecurityGroup) string { return s.Name }
+func routerName(r compute.Router) string               { return r.Name }
+func serverName(s compute.Server) string               { return s.Name }
+func volumeName(v compute.Volume) string               { return v.Name }
+func elasticIPName(e compute.ElasticIP) string         { return e.PublicIP }
+func loadBalancerName(l compute.LoadBalancer) string   { return l.Name }
@@ -0,0 +1,219 @@
+package objectstorage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/objectstorage"
+	"github.com/cloudbit-ch/cli/v2/pkg/filter"
+)
+
+func BucketCommand(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "bucket",
+		Aliases: []string{"buckets"},
+		Short:   "Manage object storage buckets",
+	}
+
+	commands.Add(app, cmd,
+		&bucketListCommand{},
+		&bucketCreateCommand{},
+		&bucketDeleteCommand{},
+	)
+
+	for _, setting := range bucketSettings {
+		cmd.AddCommand(bucketSettingCommand(app, setting))
+	}
+
+	return cmd
+}
+
+// bucketName accepts the name of a bucket with or without the s3:// scheme.
+func bucketName(arg string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(arg, uriScheme), "/")
+}
+
+func completeBucket(ctx context.Context, opts storageOptions, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client, err := opts.client(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	buckets, err := objectstorage.NewBucketService(client).List(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	filtered := make([]string, 0, len(buckets))
+	for _, bucket := range buckets {
+		if strings.HasPrefix(bucket.Name, toComplete) {
+			filtered = append(filtered, bucket.Name)
+		}
+	}
+
+	return filtered, cobra.ShellCompDirectiveNoFileComp
+}
+
+type bucketListCommand struct {
+	storage storageOptions
+	filter  string
+}
+
+func (b *bucketListCommand) Run(cmd *cobra.Command, args []string) error {
+	client, err := b.storage.client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	buckets, err := objectstorage.NewBucketService(client).List(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("fetch buckets: %w", err)
+	}
+
+	if len(b.filter) != 0 {
+		buckets = filter.Find(buckets, b.filter)
+	}
+
+	return commands.PrintStdout(buckets)
+}
+
+func (b *bucketListCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"show", "ls", "get"},
+		Short:   "List buckets",
+		Long:    "Prints a table of all buckets of the object storage instance.",
+		Args:    cobra.NoArgs,
+		RunE:    b.Run,
+	}
+
+	addStorageFlags(cmd, &b.storage)
+	cmd.Flags().StringVar(&b.filter, "filter", "", "custom term to filter the results")
+
+	return cmd
+}
+
+type bucketCreateCommand struct {
+	storage storageOptions
+}
+
+func (b *bucketCreateCommand) Run(cmd *cobra.Command, args []string) error {
+	client, err := b.storage.client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	service := objectstorage.NewBucketService(client)
+	for _, arg := range args {
+		if err = service.Create(cmd.Context(), bucketName(arg)); err != nil {
+			return fmt.Errorf("create bucket %s: %w", bucketName(arg), err)
+		}
+	}
+
+	buckets, err := service.List(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("fetch buckets: %w", err)
+	}
+
+	created := make([]objectstorage.Bucket, 0, len(args))
+	for _, bucket := range buckets {
+		for _, arg := range args {
+			if bucket.Name == bucketName(arg) {
+				created = append(created, bucket)
+				break
+			}
+		}
+	}
+
+	return commands.PrintStdout(created)
+}
+
+func (b *bucketCreateCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "create BUCKET...",
+		Aliases: []string{"add", "new"},
+		Short:   "Create buckets",
+		Long: commands.FormatHelp(`
+			Creates one or more buckets in the object storage instance. Bucket names consist of 3 to 63 lower case
+			letters, numbers, dots and hyphens.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Create a bucket in the object storage instance of ALP1
+      %[1]s object-storage bucket create backups --location ALP1
+		`, app.Name)),
+		Args: cobra.MinimumNArgs(1),
+		RunE: b.Run,
+	}
+
+	addStorageFlags(cmd, &b.storage)
+
+	return cmd
+}
+
+type bucketDeleteCommand struct {
+	storage storageOptions
+	force   bool
+	bulk    commands.BulkOptions
+}
+
+func (b *bucketDeleteCommand) Run(cmd *cobra.Command, args []string) error {
+	client, err := b.storage.client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	service := objectstorage.NewBucketService(client)
+
+	buckets, err := service.List(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("fetch buckets: %w", err)
+	}
+
+	names := make([]string, len(args))
+	for idx, arg := range args {
+		names[idx] = bucketName(arg)
+	}
+
+	buckets, err = commands.SelectBulk(buckets, names, b.bulk)
+	if err != nil {
+		return fmt.Errorf("find bucket: %w", err)
+	}
+
+	if !b.force && !commands.ConfirmBulk("delete", "bucket", buckets) {
+		commands.Stderr.Println("aborted.")
+		return nil
+	}
+
+	return commands.RunBulk(cmd.Context(), "delete", "bucket", buckets, b.bulk.Concurrency, func(ctx context.Context, bucket objectstorage.Bucket) error {
+		return service.Delete(ctx, bucket.Name)
+	})
+}
+
+func (b *bucketDeleteCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeBucket(cmd.Context(), b.storage, toComplete)
+}
+
+func (b *bucketDeleteCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete BUCKET...",
+		Aliases: []string{"del", "remove", "rm"},
+		Short:   "Delete buckets",
+		Long: commands.FormatHelp(`
+			Deletes one or more buckets. Only empty buckets can be deleted, use "object rm --recursive s3://BUCKET/" to
+			delete all objects of a bucket first.
+		`),
+		ValidArgsFunction: b.CompleteArg,
+		RunE:              b.Run,
+	}
+
+	addStorageFlags(cmd, &b.storage)
+	cmd.Flags().BoolVar(&b.force, "force", false, "force the deletion of the buckets without asking for confirmation")
+
+	commands.AddBulkFlags(cmd, &b.bulk, "bucket")
+
+	return cmd
+}
@@ -0,0 +1,286 @@
+package objectstorage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/objectstorage"
+	"github.com/cloudbit-ch/cli/v2/pkg/s3"
+)
+
+// bucketSetting is a configuration of a bucket, which is managed as a whole using a get and a set command.
+type bucketSetting struct {
+	name    string
+	short   string
+	long    string
+	example string
+
+	// get returns the configuration of the bucket or nil if it has none
+	get func(ctx context.Context, service objectstorage.BucketService, bucket string) (interface{}, error)
+
+	// parse decodes and validates the document, which is applied to the bucket using set
+	parse func(data []byte) (interface{}, error)
+	set   func(ctx context.Context, service objectstorage.BucketService, bucket string, config interface{}) error
+}
+
+var bucketSettings = []bucketSetting{
+	{
+		name:  "lifecycle",
+		short: "Manage the lifecycle rules of buckets",
+		long: `
+			Lifecycle rules expire the objects of a bucket, e.g. to enforce retention periods. Each rule selects the
+			objects by their prefix, tags or size and deletes them, or moves them to another storage class, a number
+			of days after their creation or at a date. In versioned buckets, replaced versions can be expired using
+			NoncurrentVersionExpiration. The configuration is given as JSON in the format of the AWS CLI or as XML in
+			the format of the S3 API. Unknown fields and elements are rejected instead of being dropped.
+		`,
+		example: `
+      # Delete the logs after 90 days
+      echo '{"Rules": [{"ID": "logs", "Filter": {"Prefix": "logs/"}, "Status": "Enabled", "Expiration": {"Days": 90}}]}' > lifecycle.json
+      %[1]s object-storage bucket lifecycle set backups -f lifecycle.json`,
+		get: func(ctx context.Context, service objectstorage.BucketService, bucket string) (interface{}, error) {
+			config, err := service.GetLifecycle(ctx, bucket)
+			if err != nil || config == nil {
+				return nil, err
+			}
+
+			return config, nil
+		},
+		parse: func(data []byte) (interface{}, error) {
+			return s3.ParseLifecycleConfiguration(data)
+		},
+		set: func(ctx context.Context, service objectstorage.BucketService, bucket string, config interface{}) error {
+			return service.SetLifecycle(ctx, bucket, config.(s3.LifecycleConfiguration))
+		},
+	},
+	{
+		name:  "versioning",
+		short: "Manage the versioning of buckets",
+		long: `
+			Versioned buckets keep the previous versions of replaced and deleted objects. The status is either
+			Enabled or Suspended, as versioning cannot be disabled once it has been enabled. The configuration is
+			given as JSON in the format of the AWS CLI or as XML in the format of the S3 API.
+		`,
+		example: `
+      # Enable the versioning
+      echo '{"Status": "Enabled"}' | %[1]s object-storage bucket versioning set backups -f -`,
+		get: func(ctx context.Context, service objectstorage.BucketService, bucket string) (interface{}, error) {
+			config, err := service.GetVersioning(ctx, bucket)
+			if err != nil || config.Status == "" {
+				return nil, err
+			}
+
+			return config, nil
+		},
+		parse: func(data []byte) (interface{}, error) {
+			return s3.ParseVersioningConfiguration(data)
+		},
+		set: func(ctx context.Context, service objectstorage.BucketService, bucket string, config interface{}) error {
+			return service.SetVersioning(ctx, bucket, config.(s3.VersioningConfiguration))
+		},
+	},
+	{
+		name:  "policy",
+		short: "Manage the policies of buckets",
+		long: `
+			Bucket policies grant or deny access to a bucket and its objects, e.g. public read access to a website.
+			The policy is a JSON document in the IAM policy language, whose version and statements are checked
+			before the upload.
+		`,
+		example: `
+      # Allow anyone to read the objects of the bucket
+      %[1]s object-storage bucket policy set www -f public-read.json`,
+		get: func(ctx context.Context, service objectstorage.BucketService, bucket string) (interface{}, error) {
+			policy, err := service.GetPolicy(ctx, bucket)
+			if err != nil || policy == nil {
+				return nil, err
+			}
+
+			return json.RawMessage(policy), nil
+		},
+		parse: func(data []byte) (interface{}, error) {
+			return data, s3.ValidatePolicy(data)
+		},
+		set: func(ctx context.Context, service objectstorage.BucketService, bucket string, config interface{}) error {
+			return service.SetPolicy(ctx, bucket, config.([]byte))
+		},
+	},
+	{
+		name:  "cors",
+		short: "Manage the cors rules of buckets",
+		long: `
+			CORS rules allow web pages of other origins to access the objects of a bucket using the allowed methods
+			and headers. The configuration is given as JSON in the format of the AWS CLI or as XML in the format of
+			the S3 API.
+		`,
+		example: `
+      # Allow downloads from a website
+      echo '{"CORSRules": [{"AllowedMethods": ["GET"], "AllowedOrigins": ["https://example.com"]}]}' > cors.json
+      %[1]s object-storage bucket cors set www -f cors.json`,
+		get: func(ctx context.Context, service objectstorage.BucketService, bucket string) (interface{}, error) {
+			config, err := service.GetCORS(ctx, bucket)
+			if err != nil || config == nil {
+				return nil, err
+			}
+
+			return config, nil
+		},
+		parse: func(data []byte) (interface{}, error) {
+			return s3.ParseCORSConfiguration(data)
+		},
+		set: func(ctx context.Context, service objectstorage.BucketService, bucket string, config interface{}) error {
+			return service.SetCORS(ctx, bucket, config.(s3.CORSConfiguration))
+		},
+	},
+}
+
+func bucketSettingCommand(app commands.Application, setting bucketSetting) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     setting.name,
+		Short:   setting.short,
+		Long:    commands.FormatHelp(setting.long),
+		Example: commands.FormatExamples(fmt.Sprintf(setting.example, app.Name)),
+	}
+
+	commands.Add(app, cmd,
+		&bucketSettingGetCommand{setting: setting},
+		&bucketSettingSetCommand{setting: setting},
+	)
+
+	return cmd
+}
+
+type bucketSettingGetCommand struct {
+	setting bucketSetting
+	storage storageOptions
+}
+
+func (b *bucketSettingGetCommand) Run(cmd *cobra.Command, args []string) error {
+	bucket := bucketName(args[0])
+
+	credential, err := findBucketCredential(cmd.Context(), b.storage.location, bucket)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(credential)
+	if err != nil {
+		return err
+	}
+
+	config, err := b.setting.get(cmd.Context(), objectstorage.NewBucketService(client), bucket)
+	if err != nil {
+		return fmt.Errorf("fetch %s of bucket %s: %w", b.setting.name, bucket, err)
+	}
+
+	if config == nil {
+		commands.Stderr.Printf("bucket %s has no %s configuration.\n", bucket, b.setting.name)
+		return nil
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", b.setting.name, err)
+	}
+
+	commands.Stdout.Println(string(data))
+	return nil
+}
+
+func (b *bucketSettingGetCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeBucket(cmd.Context(), b.storage, toComplete)
+}
+
+func (b *bucketSettingGetCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get BUCKET",
+		Short: fmt.Sprintf("Print the %s configuration", b.setting.name),
+		Long: commands.FormatHelp(fmt.Sprintf(`
+			Prints the %s configuration of the bucket as JSON, which can be changed and applied again using "set".
+		`, b.setting.name)),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: b.CompleteArg,
+		RunE:              b.Run,
+	}
+
+	addStorageFlags(cmd, &b.storage)
+
+	return cmd
+}
+
+type bucketSettingSetCommand struct {
+	setting bucketSetting
+	storage storageOptions
+	file    string
+}
+
+func (b *bucketSettingSetCommand) Run(cmd *cobra.Command, args []string) error {
+	bucket := bucketName(args[0])
+
+	var (
+		data []byte
+		err  error
+	)
+
+	if b.file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(b.file)
+	}
+
+	if err != nil {
+		return fmt.Errorf("read %s configuration: %w", b.setting.name, err)
+	}
+
+	config, err := b.setting.parse(data)
+	if err != nil {
+		return fmt.Errorf("invalid %s configuration: %w", b.setting.name, err)
+	}
+
+	credential, err := findBucketCredential(cmd.Context(), b.storage.location, bucket)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(credential)
+	if err != nil {
+		return err
+	}
+
+	err = b.setting.set(cmd.Context(), objectstorage.NewBucketService(client), bucket, config)
+	if err != nil {
+		return fmt.Errorf("set %s of bucket %s: %w", b.setting.name, bucket, err)
+	}
+
+	return nil
+}
+
+func (b *bucketSettingSetCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeBucket(cmd.Context(), b.storage, toComplete)
+}
+
+func (b *bucketSettingSetCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set BUCKET -f FILE",
+		Short: fmt.Sprintf("Replace the %s configuration", b.setting.name),
+		Long: commands.FormatHelp(fmt.Sprintf(`
+			Replaces the %s configuration of the bucket by the contents of the file. The file is validated before it
+			is uploaded. A file of "-" reads the configuration from stdin.
+		`, b.setting.name)),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: b.CompleteArg,
+		RunE:              b.Run,
+	}
+
+	addStorageFlags(cmd, &b.storage)
+	cmd.Flags().StringVarP(&b.file, "file", "f", "", fmt.Sprintf("file containing the %s configuration", b.setting.name))
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
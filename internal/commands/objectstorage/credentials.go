@@ -0,0 +1,200 @@
+package objectstorage
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/objectstorage"
+	"github.com/cloudbit-ch/cli/v2/pkg/filter"
+	"github.com/cloudbit-ch/cli/v2/pkg/s3"
+)
+
+const (
+	credentialFormatEnv        = "env"
+	credentialFormatAWSProfile = "aws-profile"
+	credentialFormatRclone     = "rclone"
+	credentialFormatS3cmd      = "s3cmd"
+)
+
+var credentialFormats = []string{credentialFormatEnv, credentialFormatAWSProfile, credentialFormatRclone, credentialFormatS3cmd}
+
+func CredentialsCommand(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "credentials",
+		Aliases: []string{"credential"},
+		Short:   "Manage object storage credentials",
+	}
+
+	commands.Add(app, cmd,
+		&credentialListCommand{},
+		&credentialExportCommand{},
+	)
+
+	return cmd
+}
+
+type credentialListCommand struct {
+	filter      string
+	showSecrets bool
+}
+
+func (c *credentialListCommand) Run(cmd *cobra.Command, args []string) error {
+	credentials, err := objectstorage.NewCredentialService(commands.Config.Client).List(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("fetch object storage credentials: %w", err)
+	}
+
+	if len(c.filter) != 0 {
+		credentials = filter.Find(credentials, c.filter)
+	}
+
+	if !c.showSecrets {
+		for idx, credential := range credentials {
+			credentials[idx] = credential.Masked()
+		}
+	}
+
+	return commands.PrintStdout(credentials)
+}
+
+func (c *credentialListCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"show", "ls", "get"},
+		Short:   "List credentials",
+		Long: commands.FormatHelp(`
+			Prints a table of all object storage credentials belonging to the current organization. The secret keys
+			are masked unless --show-secrets is given.
+		`),
+		Args: cobra.NoArgs,
+		RunE: c.Run,
+	}
+
+	cmd.Flags().StringVar(&c.filter, "filter", "", "custom term to filter the results")
+	cmd.Flags().BoolVar(&c.showSecrets, "show-secrets", false, "show the secret keys instead of masking them")
+
+	return cmd
+}
+
+type credentialExportCommand struct {
+	storage storageOptions
+	name    string
+}
+
+func (c *credentialExportCommand) Run(cmd *cobra.Command, args []string) error {
+	format, err := commands.CommandFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	credential, err := findCredential(cmd.Context(), c.storage.location)
+	if err != nil {
+		return err
+	}
+
+	name := c.name
+	if name == "" {
+		name = fmt.Sprintf("%s-%s", cmd.Root().Name(), strings.ToLower(credential.Location.Key))
+	}
+
+	return writeCredential(commands.Stdout, format, credential, name)
+}
+
+func (c *credentialExportCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export credentials as configuration",
+		Long: commands.FormatHelp(fmt.Sprintf(`
+			Prints the credentials of the object storage instance as configuration of other tools. The format is
+			selected using --format and is one of:
+
+			%s: shell exports of the AWS environment variables, e.g. for eval
+			%s: a profile of the AWS credentials and config files
+			%s: a remote of the rclone config file
+			%s: the s3cmd config file
+		`, credentialFormatEnv, credentialFormatAWSProfile, credentialFormatRclone, credentialFormatS3cmd)),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Use the credentials in the current shell
+      eval "$(%[1]s object-storage credentials export --location ALP1)"
+
+      # Add a remote to the rclone config
+      %[1]s object-storage credentials export --format rclone >> ~/.config/rclone/rclone.conf
+
+      # Write the s3cmd config
+      %[1]s object-storage credentials export --format s3cmd > ~/.s3cfg
+		`, app.Name)),
+		Args:        cobra.NoArgs,
+		RunE:        c.Run,
+		Annotations: map[string]string{commands.AnnotationFormats: strings.Join(credentialFormats, ",")},
+	}
+
+	addStorageFlags(cmd, &c.storage)
+	cmd.Flags().StringVar(&c.name, "name", "", fmt.Sprintf("name of the aws profile or rclone remote (default %s-LOCATION)", app.Name))
+
+	return cmd
+}
+
+// writeCredential writes the credential in the format, using the name for the aws profile and rclone remote.
+func writeCredential(w io.Writer, format string, credential objectstorage.Credential, name string) error {
+	endpoint, err := s3.ParseEndpoint(credential.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	buf := &strings.Builder{}
+
+	switch format {
+	case credentialFormatEnv:
+		fmt.Fprintf(buf, "export AWS_ACCESS_KEY_ID=%s\n", quoteShell(credential.AccessKey))
+		fmt.Fprintf(buf, "export AWS_SECRET_ACCESS_KEY=%s\n", quoteShell(credential.SecretKey))
+		fmt.Fprintf(buf, "export AWS_ENDPOINT_URL=%s\n", quoteShell(endpoint.String()))
+		fmt.Fprintf(buf, "export AWS_DEFAULT_REGION=%s\n", s3.DefaultRegion)
+	case credentialFormatAWSProfile:
+		fmt.Fprintf(buf, "# ~/.aws/credentials\n[%s]\n", name)
+		fmt.Fprintf(buf, "aws_access_key_id = %s\n", credential.AccessKey)
+		fmt.Fprintf(buf, "aws_secret_access_key = %s\n", credential.SecretKey)
+		fmt.Fprintf(buf, "\n# ~/.aws/config\n[profile %s]\n", name)
+		fmt.Fprintf(buf, "endpoint_url = %s\n", endpoint)
+		fmt.Fprintf(buf, "region = %s\n", s3.DefaultRegion)
+	case credentialFormatRclone:
+		fmt.Fprintf(buf, "[%s]\n", name)
+		fmt.Fprintf(buf, "type = s3\n")
+		fmt.Fprintf(buf, "provider = Other\n")
+		fmt.Fprintf(buf, "access_key_id = %s\n", credential.AccessKey)
+		fmt.Fprintf(buf, "secret_access_key = %s\n", credential.SecretKey)
+		fmt.Fprintf(buf, "endpoint = %s\n", endpoint)
+		fmt.Fprintf(buf, "force_path_style = true\n")
+	case credentialFormatS3cmd:
+		// s3cmd expects the host without scheme and addresses buckets path style if the bucket host equals the base
+		host := endpoint.Host + endpoint.Path
+
+		fmt.Fprintf(buf, "[default]\n")
+		fmt.Fprintf(buf, "access_key = %s\n", credential.AccessKey)
+		fmt.Fprintf(buf, "secret_key = %s\n", credential.SecretKey)
+		fmt.Fprintf(buf, "host_base = %s\n", host)
+		fmt.Fprintf(buf, "host_bucket = %s\n", host)
+		fmt.Fprintf(buf, "use_https = %s\n", pythonBool(endpoint.Scheme == "https"))
+		fmt.Fprintf(buf, "signature_v2 = False\n")
+	default:
+		return fmt.Errorf("unknown credential format %q, allowed values: %s", format, strings.Join(credentialFormats, ", "))
+	}
+
+	_, err = io.WriteString(w, buf.String())
+	return err
+}
+
+func quoteShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func pythonBool(value bool) string {
+	if value {
+		return "True"
+	}
+
+	return "False"
+}
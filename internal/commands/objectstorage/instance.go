@@ -22,9 +22,15 @@ func InstanceCommand(app commands.Application) *cobra.Command {
 		&instanceListCommand{},
 		&instanceCreateCommand{},
 		&instanceDeleteCommand{},
-		&credentialsCommand{},
 	)
 
+	// the credentials used to be listed by "instance credentials"
+	credentials := (&credentialListCommand{}).Build(app)
+	credentials.Use = "credentials"
+	credentials.Aliases = nil
+	credentials.Deprecated = "use \"object-storage credentials list\" instead"
+	cmd.AddCommand(credentials)
+
 	return cmd
 }
 
@@ -141,33 +147,3 @@ func (i *instanceDeleteCommand) Build(app commands.Application) *cobra.Command {
 
 	return cmd
 }
-
-type credentialsCommand struct {
-	filter string
-}
-
-func (c *credentialsCommand) Run(cmd *cobra.Command, args []string) error {
-	credentials, err := objectstorage.NewCredentialService(commands.Config.Client).List(cmd.Context())
-	if err != nil {
-		return err
-	}
-
-	if len(c.filter) != 0 {
-		credentials = filter.Find(credentials, c.filter)
-	}
-
-	return commands.PrintStdout(credentials)
-}
-
-func (c *credentialsCommand) Build(app commands.Application) *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "credentials",
-		Short: "Show object storage credentials",
-		Long:  "Prints a table of all object storage credentials belonging to the current organization.",
-		RunE:  c.Run,
-	}
-
-	cmd.Flags().StringVar(&c.filter, "filter", "", "custom term to filter the results")
-
-	return cmd
-}
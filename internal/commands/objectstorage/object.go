@@ -0,0 +1,430 @@
+package objectstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/objectstorage"
+	"github.com/cloudbit-ch/cli/v2/pkg/filter"
+)
+
+func ObjectCommand(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "object",
+		Aliases: []string{"objects"},
+		Short:   "Manage objects in buckets",
+		Long: commands.FormatHelp(`
+			Lists, copies and deletes the objects in the buckets of an object storage instance. Objects are addressed as
+			s3://BUCKET/KEY, all other arguments are local paths. The credentials of the instance are looked up
+			automatically, select the instance using --location if the organization has instances in multiple
+			locations.
+		`),
+	}
+
+	commands.Add(app, cmd,
+		&objectListCommand{},
+		&objectCopyCommand{},
+		&objectRemoveCommand{},
+		&objectSyncCommand{},
+	)
+
+	return cmd
+}
+
+type objectListCommand struct {
+	storage   storageOptions
+	recursive bool
+	filter    string
+}
+
+func (o *objectListCommand) Run(cmd *cobra.Command, args []string) error {
+	target, err := parseRemotePath(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := o.storage.client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	objects, err := objectstorage.NewObjectService(client, target.bucket).List(cmd.Context(), target.key, o.recursive)
+	if err != nil {
+		return fmt.Errorf("fetch objects: %w", err)
+	}
+
+	if len(o.filter) != 0 {
+		objects = filter.Find(objects, o.filter)
+	}
+
+	return commands.PrintStdout(objects)
+}
+
+func (o *objectListCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeObjectPath(cmd.Context(), o.storage, toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (o *objectListCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "ls s3://BUCKET[/PREFIX]",
+		Aliases: []string{"list", "show", "get"},
+		Short:   "List objects",
+		Long: commands.FormatHelp(`
+			Prints a table of the objects in the bucket starting with the prefix. Objects in sub directories are
+			summarized by the directory, e.g. "photos/", unless --recursive is given.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # List the top level of a bucket
+      %[1]s object-storage object ls s3://backups
+
+      # List all objects below a prefix
+      %[1]s object-storage object ls s3://backups/2023/ --recursive
+		`, app.Name)),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: o.CompleteArg,
+		RunE:              o.Run,
+	}
+
+	addStorageFlags(cmd, &o.storage)
+	cmd.Flags().BoolVarP(&o.recursive, "recursive", "r", false, "list all objects below the prefix instead of a single level")
+	cmd.Flags().StringVar(&o.filter, "filter", "", "custom term to filter the results")
+
+	return cmd
+}
+
+type objectCopyCommand struct {
+	storage     storageOptions
+	transfer    transferOptions
+	recursive   bool
+	concurrency int
+}
+
+func (o *objectCopyCommand) Run(cmd *cobra.Command, args []string) error {
+	if err := o.transfer.validate(); err != nil {
+		return err
+	}
+
+	source, err := parseObjectPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	dest, err := parseObjectPath(args[1])
+	if err != nil {
+		return err
+	}
+
+	if !source.remote() && !dest.remote() {
+		return fmt.Errorf("either the source or the destination must be a bucket, e.g. %sbucket/key", uriScheme)
+	}
+
+	client, err := o.storage.client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	entries, err := listEntries(cmd.Context(), client, source, o.recursive)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		return fmt.Errorf("no objects found below %s", source)
+	}
+
+	intoDir := o.recursive || isDirectory(dest)
+
+	transfers := make([]transfer, len(entries))
+	for idx, entry := range entries {
+		target := dest
+		if intoDir {
+			if target, err = join(dest, entry.rel); err != nil {
+				return err
+			}
+		}
+
+		transfers[idx] = transfer{source: entry.path, dest: target, size: entry.size}
+	}
+
+	return runTransfers(cmd.Context(), client, transfers, o.concurrency, o.transfer)
+}
+
+func (o *objectCopyCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) < 2 {
+		return completeObjectPath(cmd.Context(), o.storage, toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (o *objectCopyCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cp SOURCE DEST",
+		Short: "Copy files and objects",
+		Long: commands.FormatHelp(`
+			Uploads local files to a bucket, downloads objects to local files or copies objects between buckets. If the
+			destination ends with a slash or is an existing directory, the source is copied into it. With --recursive,
+			all files of a directory or all objects below a prefix are copied.
+
+			Files larger than --part-size are uploaded in multiple parts sent in parallel. If such an upload is
+			interrupted, running the same command again continues with the missing parts. Uploads and downloads are
+			verified using the md5 hashes of the content.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Upload a file
+      %[1]s object-storage object cp backup.tar.gz s3://backups/2023/
+
+      # Download all objects below a prefix into a local directory
+      %[1]s object-storage object cp --recursive s3://backups/2023 ./restore
+
+      # Upload a large backup in parts of 64 MiB, sending 8 parts in parallel
+      %[1]s object-storage object cp --part-size 64 --part-concurrency 8 backup.tar.gz s3://backups/
+
+      # Copy an object to another bucket
+      %[1]s object-storage object cp s3://backups/latest.tar.gz s3://archive/latest.tar.gz
+		`, app.Name)),
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: o.CompleteArg,
+		RunE:              o.Run,
+	}
+
+	addStorageFlags(cmd, &o.storage)
+	addTransferFlags(cmd, &o.transfer)
+	cmd.Flags().BoolVarP(&o.recursive, "recursive", "r", false, "copy all files of a directory or objects below a prefix")
+	cmd.Flags().IntVar(&o.concurrency, "concurrency", defaultConcurrency, "maximum number of files copied in parallel")
+
+	return cmd
+}
+
+type objectRemoveCommand struct {
+	storage     storageOptions
+	recursive   bool
+	force       bool
+	concurrency int
+}
+
+func (o *objectRemoveCommand) Run(cmd *cobra.Command, args []string) error {
+	targets := make([]objectPath, len(args))
+	for idx, arg := range args {
+		target, err := parseRemotePath(arg)
+		if err != nil {
+			return err
+		}
+
+		targets[idx] = target
+	}
+
+	client, err := o.storage.client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	var objects []objectPath
+	for _, target := range targets {
+		entries, err := listEntries(cmd.Context(), client, target, o.recursive)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			objects = append(objects, entry.path)
+		}
+	}
+
+	if len(objects) == 0 {
+		commands.Stderr.Println("no objects found.")
+		return nil
+	}
+
+	if !o.force && !commands.ConfirmBulk("delete", "object", objects) {
+		commands.Stderr.Println("aborted.")
+		return nil
+	}
+
+	return commands.RunBulk(cmd.Context(), "delete", "object", objects, o.concurrency, func(ctx context.Context, object objectPath) error {
+		return remove(ctx, client, object)
+	})
+}
+
+func (o *objectRemoveCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeObjectPath(cmd.Context(), o.storage, toComplete)
+}
+
+func (o *objectRemoveCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rm s3://BUCKET/KEY...",
+		Aliases: []string{"remove", "delete", "del"},
+		Short:   "Delete objects",
+		Long:    "Deletes one or more objects or, with --recursive, all objects below the prefixes.",
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Delete a single object
+      %[1]s object-storage object rm s3://backups/2023/backup.tar.gz
+
+      # Delete all objects of a bucket without confirmation
+      %[1]s object-storage object rm --recursive --force s3://scratch/
+		`, app.Name)),
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: o.CompleteArg,
+		RunE:              o.Run,
+	}
+
+	addStorageFlags(cmd, &o.storage)
+	cmd.Flags().BoolVarP(&o.recursive, "recursive", "r", false, "delete all objects below the prefixes")
+	cmd.Flags().BoolVar(&o.force, "force", false, "force the deletion of the objects without asking for confirmation")
+	cmd.Flags().IntVar(&o.concurrency, "concurrency", defaultConcurrency, "maximum number of requests sent in parallel")
+
+	return cmd
+}
+
+type objectSyncCommand struct {
+	storage     storageOptions
+	transfer    transferOptions
+	delete      bool
+	force       bool
+	concurrency int
+}
+
+func (o *objectSyncCommand) Run(cmd *cobra.Command, args []string) error {
+	if err := o.transfer.validate(); err != nil {
+		return err
+	}
+
+	source, err := parseObjectPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	dest, err := parseObjectPath(args[1])
+	if err != nil {
+		return err
+	}
+
+	if !source.remote() && !dest.remote() {
+		return fmt.Errorf("either the source or the destination must be a bucket, e.g. %sbucket/prefix", uriScheme)
+	}
+
+	client, err := o.storage.client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	sourceEntries, err := listEntries(cmd.Context(), client, source, true)
+	if err != nil {
+		return err
+	}
+
+	destEntries, err := listEntries(cmd.Context(), client, dest, true)
+	if err != nil && !notExist(err) {
+		return err
+	}
+
+	existing := make(map[string]entry, len(destEntries))
+	for _, entry := range destEntries {
+		existing[entry.rel] = entry
+	}
+
+	var transfers []transfer
+	for _, entry := range sourceEntries {
+		current, ok := existing[entry.rel]
+		delete(existing, entry.rel)
+
+		if ok {
+			changed, err := modified(entry, current)
+			if err != nil {
+				return err
+			}
+
+			if !changed {
+				continue
+			}
+		}
+
+		target, err := join(dest, entry.rel)
+		if err != nil {
+			return err
+		}
+
+		transfers = append(transfers, transfer{source: entry.path, dest: target, size: entry.size})
+	}
+
+	var removals []objectPath
+	if o.delete {
+		for _, entry := range destEntries {
+			if _, ok := existing[entry.rel]; ok {
+				removals = append(removals, entry.path)
+			}
+		}
+	}
+
+	if len(transfers) == 0 && len(removals) == 0 {
+		commands.Stderr.Printf("%s is up to date.\n", dest)
+		return nil
+	}
+
+	if len(removals) != 0 && !o.force && !commands.ConfirmBulk("delete", "object", removals) {
+		commands.Stderr.Println("aborted.")
+		return nil
+	}
+
+	if len(transfers) != 0 {
+		err = runTransfers(cmd.Context(), client, transfers, o.concurrency, o.transfer)
+		if err != nil && (len(removals) == 0 || !errors.Is(err, commands.ErrDryRun)) {
+			return err
+		}
+	}
+
+	if len(removals) != 0 {
+		return commands.RunBulk(cmd.Context(), "delete", "object", removals, o.concurrency, func(ctx context.Context, target objectPath) error {
+			return remove(ctx, client, target)
+		})
+	}
+
+	return nil
+}
+
+func (o *objectSyncCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) < 2 {
+		return completeObjectPath(cmd.Context(), o.storage, toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (o *objectSyncCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync SOURCE DEST",
+		Short: "Synchronize directories and prefixes",
+		Long: commands.FormatHelp(`
+			Copies all files or objects of the source, which are missing or differ in the destination. Files are
+			compared by their size and md5 hash, or their modification time if the hash of an object is not known, e.g.
+			for objects uploaded in multiple parts. With --delete, files and objects of the destination missing in the
+			source are deleted.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Upload the changes of a local directory
+      %[1]s object-storage object sync ./site s3://www/
+
+      # Mirror a prefix into a local directory, deleting files removed from the bucket
+      %[1]s object-storage object sync s3://backups/2023 ./backups --delete
+		`, app.Name)),
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: o.CompleteArg,
+		RunE:              o.Run,
+	}
+
+	addStorageFlags(cmd, &o.storage)
+	addTransferFlags(cmd, &o.transfer)
+	cmd.Flags().BoolVar(&o.delete, "delete", false, "delete files and objects of the destination, which do not exist in the source")
+	cmd.Flags().BoolVar(&o.force, "force", false, "force the deletion of files and objects without asking for confirmation")
+	cmd.Flags().IntVar(&o.concurrency, "concurrency", defaultConcurrency, "maximum number of files copied in parallel")
+
+	return cmd
+}
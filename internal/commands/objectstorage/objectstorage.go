@@ -15,7 +15,12 @@ func Module(app commands.Application) *cobra.Command {
 
 	cmd.AddCommand(
 		InstanceCommand(app),
+		CredentialsCommand(app),
+		BucketCommand(app),
+		ObjectCommand(app),
 	)
 
+	commands.Add(app, cmd, &presignCommand{})
+
 	return cmd
 }
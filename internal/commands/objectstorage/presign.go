@@ -0,0 +1,98 @@
+package objectstorage
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+)
+
+var presignMethods = []string{http.MethodGet, http.MethodPut, http.MethodHead, http.MethodDelete}
+
+type presignCommand struct {
+	storage storageOptions
+	expires time.Duration
+	method  string
+}
+
+func (p *presignCommand) Run(cmd *cobra.Command, args []string) error {
+	target, err := parseRemotePath(args[0])
+	if err != nil {
+		return err
+	}
+
+	if target.key == "" || strings.HasSuffix(target.key, "/") {
+		return fmt.Errorf("expected %sbucket/key of an object, got %q", uriScheme, args[0])
+	}
+
+	method := strings.ToUpper(p.method)
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodHead, http.MethodDelete:
+	default:
+		return fmt.Errorf("unsupported method %q, expected one of %s", p.method, strings.Join(presignMethods, ", "))
+	}
+
+	credential, err := findBucketCredential(cmd.Context(), p.storage.location, target.bucket)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(credential)
+	if err != nil {
+		return err
+	}
+
+	url, err := client.PresignObject(method, target.bucket, target.key, p.expires)
+	if err != nil {
+		return err
+	}
+
+	commands.Stdout.Println(url)
+	return nil
+}
+
+func (p *presignCommand) CompleteArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeObjectPath(cmd.Context(), p.storage, toComplete)
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (p *presignCommand) Build(app commands.Application) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "presign s3://BUCKET/KEY",
+		Short: "Create a temporary url of an object",
+		Long: commands.FormatHelp(`
+			Prints a url, which allows anyone with the url to download the object or, with --method PUT, to upload it
+			without credentials until the url expires. The url is signed locally using the credentials of the object
+			storage instance holding the bucket, which is looked up automatically if the organization has instances in
+			multiple locations and no --location is given.
+		`),
+		Example: commands.FormatExamples(fmt.Sprintf(`
+      # Create a download link valid for one hour
+      %[1]s object-storage presign s3://backups/2023/backup.tar.gz
+
+      # Let a customer upload a file within the next two days
+      URL=$(%[1]s object-storage presign s3://uploads/customer.zip --expires 48h --method PUT)
+      curl --upload-file customer.zip "$URL"
+		`, app.Name)),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: p.CompleteArg,
+		RunE:              p.Run,
+	}
+
+	addStorageFlags(cmd, &p.storage)
+	cmd.Flags().DurationVar(&p.expires, "expires", time.Hour, "time until the url expires, at most 168h")
+	cmd.Flags().StringVar(&p.method, "method", http.MethodGet, fmt.Sprintf("http method allowed by the url, one of %s", strings.Join(presignMethods, ", ")))
+
+	_ = cmd.RegisterFlagCompletionFunc("method", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return presignMethods, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
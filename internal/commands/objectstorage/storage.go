@@ -0,0 +1,203 @@
+package objectstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/common"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/objectstorage"
+	"github.com/cloudbit-ch/cli/v2/pkg/s3"
+)
+
+const uriScheme = "s3://"
+
+var errMultipleInstances = errors.New("found object storage instances in multiple locations, select one using --location")
+
+// storageOptions select the object storage instance, whose credentials are used to access the buckets.
+type storageOptions struct {
+	location string
+}
+
+func addStorageFlags(cmd *cobra.Command, opts *storageOptions) {
+	cmd.Flags().StringVar(&opts.location, "location", "", "location of the object storage instance (default the only instance)")
+}
+
+// client returns an S3 client using the credentials of the object storage instance in the selected location.
+func (s storageOptions) client(ctx context.Context) (*s3.Client, error) {
+	credential, err := findCredential(ctx, s.location)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(credential)
+}
+
+func newClient(credential objectstorage.Credential) (*s3.Client, error) {
+	client, err := s3.NewClient(credential.Endpoint, s3.Credentials{
+		AccessKey: credential.AccessKey,
+		SecretKey: credential.SecretKey,
+	}, commands.Config.HTTPClient)
+	if err != nil {
+		return nil, fmt.Errorf("create s3 client for %s: %w", credential.Endpoint, err)
+	}
+
+	return client, nil
+}
+
+// findCredential returns the credential of the object storage instance in the location. Without a location, the
+// credential of the only instance of the organization is returned.
+func findCredential(ctx context.Context, location string) (objectstorage.Credential, error) {
+	credentials, err := objectstorage.NewCredentialService(commands.Config.Client).List(ctx)
+	if err != nil {
+		return objectstorage.Credential{}, fmt.Errorf("fetch object storage credentials: %w", err)
+	}
+
+	if location == "" {
+		switch len(credentials) {
+		case 0:
+			return objectstorage.Credential{}, fmt.Errorf("no object storage instance found, create one using \"object-storage instance create\"")
+		case 1:
+			return credentials[0], nil
+		}
+
+		return objectstorage.Credential{}, errMultipleInstances
+	}
+
+	loc, err := common.FindLocation(ctx, commands.Config.Client, location)
+	if err != nil {
+		return objectstorage.Credential{}, err
+	}
+
+	for _, credential := range credentials {
+		if credential.Location.ID == loc.ID {
+			return credential, nil
+		}
+	}
+
+	return objectstorage.Credential{}, fmt.Errorf("no object storage instance found in location %s", loc.Name)
+}
+
+// findBucketCredential returns the credential of the object storage instance in the location. Without a location and
+// instances in multiple locations, the credential of the instance holding the bucket is returned.
+func findBucketCredential(ctx context.Context, location string, bucket string) (objectstorage.Credential, error) {
+	credential, err := findCredential(ctx, location)
+	if !errors.Is(err, errMultipleInstances) {
+		return credential, err
+	}
+
+	credentials, err := objectstorage.NewCredentialService(commands.Config.Client).List(ctx)
+	if err != nil {
+		return objectstorage.Credential{}, fmt.Errorf("fetch object storage credentials: %w", err)
+	}
+
+	for _, credential := range credentials {
+		client, err := newClient(credential)
+		if err != nil {
+			return objectstorage.Credential{}, err
+		}
+
+		err = client.HeadBucket(ctx, bucket)
+		if err == nil {
+			return credential, nil
+		}
+
+		// buckets of other instances are either not found or forbidden
+		var s3Err *s3.Error
+		if !errors.As(err, &s3Err) {
+			return objectstorage.Credential{}, fmt.Errorf("fetch bucket %s: %w", bucket, err)
+		}
+	}
+
+	return objectstorage.Credential{}, fmt.Errorf("bucket %s not found in any object storage instance", bucket)
+}
+
+// objectPath is either a path on the local file system or, if the bucket is set, an object or prefix within a bucket
+// given as s3://bucket/key.
+type objectPath struct {
+	local  string
+	bucket string
+	key    string
+}
+
+func parseObjectPath(arg string) (objectPath, error) {
+	if !strings.HasPrefix(arg, uriScheme) {
+		return objectPath{local: arg}, nil
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(arg, uriScheme), "/")
+	if bucket == "" {
+		return objectPath{}, fmt.Errorf("missing bucket in %q, expected %sbucket/key", arg, uriScheme)
+	}
+
+	return objectPath{bucket: bucket, key: key}, nil
+}
+
+// parseRemotePath parses an argument which must refer to a bucket.
+func parseRemotePath(arg string) (objectPath, error) {
+	path, err := parseObjectPath(arg)
+	if err != nil {
+		return objectPath{}, err
+	}
+
+	if !path.remote() {
+		return objectPath{}, fmt.Errorf("expected %sbucket/key, got %q", uriScheme, arg)
+	}
+
+	return path, nil
+}
+
+func (o objectPath) remote() bool {
+	return o.bucket != ""
+}
+
+func (o objectPath) String() string {
+	if o.remote() {
+		return uriScheme + o.bucket + "/" + o.key
+	}
+
+	return o.local
+}
+
+// completeObjectPath completes the buckets and keys of an s3:// argument. Local paths are completed by the shell.
+func completeObjectPath(ctx context.Context, opts storageOptions, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if toComplete == "" || (!strings.HasPrefix(toComplete, uriScheme) && !strings.HasPrefix(uriScheme, toComplete)) {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	client, err := opts.client(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	path, _ := parseObjectPath(toComplete)
+	if !strings.Contains(strings.TrimPrefix(toComplete, uriScheme), "/") {
+		buckets, err := objectstorage.NewBucketService(client).List(ctx)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		names := make([]string, len(buckets))
+		for idx, bucket := range buckets {
+			names[idx] = uriScheme + bucket.Name + "/"
+		}
+
+		return names, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+
+	objects, err := objectstorage.NewObjectService(client, path.bucket).List(ctx, path.key, false)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	keys := make([]string, len(objects))
+	for idx, object := range objects {
+		keys[idx] = uriScheme + path.bucket + "/" + object.Key
+	}
+
+	return keys, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
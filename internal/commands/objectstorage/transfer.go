@@ -0,0 +1,399 @@
+package objectstorage
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cloudbit-ch/cli/v2/internal/commands"
+	"github.com/cloudbit-ch/cli/v2/pkg/api/objectstorage"
+	"github.com/cloudbit-ch/cli/v2/pkg/console"
+	"github.com/cloudbit-ch/cli/v2/pkg/s3"
+)
+
+const defaultConcurrency = 4
+
+// entry is a file or object found below a source or destination, identified by its slash separated path relative to
+// it.
+type entry struct {
+	path     objectPath
+	rel      string
+	size     int64
+	etag     string
+	modified time.Time
+}
+
+func (e entry) String() string {
+	return e.path.String()
+}
+
+// listEntries returns the file or object at the path or, if recursive is set, all files or objects below it.
+func listEntries(ctx context.Context, client *s3.Client, root objectPath, recursive bool) ([]entry, error) {
+	if root.remote() {
+		return listRemoteEntries(ctx, client, root, recursive)
+	}
+
+	return listLocalEntries(root, recursive)
+}
+
+func listLocalEntries(root objectPath, recursive bool) ([]entry, error) {
+	info, err := os.Stat(root.local)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []entry{{path: root, rel: filepath.Base(root.local), size: info.Size(), modified: info.ModTime()}}, nil
+	}
+
+	if !recursive {
+		return nil, fmt.Errorf("%s is a directory, use --recursive to copy its content", root.local)
+	}
+
+	var entries []entry
+	err = filepath.WalkDir(root.local, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root.local, file)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry{
+			path:     objectPath{local: file},
+			rel:      filepath.ToSlash(rel),
+			size:     info.Size(),
+			modified: info.ModTime(),
+		})
+
+		return nil
+	})
+
+	return entries, err
+}
+
+func listRemoteEntries(ctx context.Context, client *s3.Client, root objectPath, recursive bool) ([]entry, error) {
+	service := objectstorage.NewObjectService(client, root.bucket)
+
+	if !recursive {
+		if root.key == "" || strings.HasSuffix(root.key, "/") {
+			return nil, fmt.Errorf("%s is a prefix, use --recursive to include all objects below it", root)
+		}
+
+		object, err := service.Head(ctx, root.key)
+		if s3.IsNotFound(err) {
+			return nil, fmt.Errorf("object %s not found, use --recursive to include all objects below it: %w", root, err)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("fetch object %s: %w", root, err)
+		}
+
+		return []entry{remoteEntry(root.bucket, path.Base(root.key), object)}, nil
+	}
+
+	prefix := dirKey(root.key)
+	objects, err := service.List(ctx, prefix, true)
+	if err != nil {
+		return nil, fmt.Errorf("fetch objects of %s: %w", root, err)
+	}
+
+	entries := make([]entry, 0, len(objects))
+	for _, object := range objects {
+		// objects ending with a slash are placeholders of directories created by some clients
+		if strings.HasSuffix(object.Key, "/") {
+			continue
+		}
+
+		entries = append(entries, remoteEntry(root.bucket, strings.TrimPrefix(object.Key, prefix), object))
+	}
+
+	return entries, nil
+}
+
+func remoteEntry(bucket string, rel string, object objectstorage.Object) entry {
+	return entry{
+		path:     objectPath{bucket: bucket, key: object.Key},
+		rel:      rel,
+		size:     object.Size,
+		etag:     object.ETag,
+		modified: object.LastModified,
+	}
+}
+
+// dirKey returns the key as prefix of a directory, which ends with a slash unless it is the root of the bucket.
+func dirKey(key string) string {
+	if key == "" || strings.HasSuffix(key, "/") {
+		return key
+	}
+
+	return key + "/"
+}
+
+// isDirectory reports whether the path refers to a directory, in which case the sources are copied into it.
+func isDirectory(p objectPath) bool {
+	if p.remote() {
+		return p.key == "" || strings.HasSuffix(p.key, "/")
+	}
+
+	if strings.HasSuffix(p.local, string(filepath.Separator)) || strings.HasSuffix(p.local, "/") {
+		return true
+	}
+
+	info, err := os.Stat(p.local)
+	return err == nil && info.IsDir()
+}
+
+// join returns the path of the entry with the relative path below the directory. Relative paths leaving the directory,
+// e.g. of keys containing "..", are rejected.
+func join(dir objectPath, rel string) (objectPath, error) {
+	if dir.remote() {
+		return objectPath{bucket: dir.bucket, key: dirKey(dir.key) + rel}, nil
+	}
+
+	file := filepath.Join(dir.local, filepath.FromSlash(rel))
+	if res, err := filepath.Rel(dir.local, file); err != nil || res == ".." || strings.HasPrefix(res, ".."+string(filepath.Separator)) {
+		return objectPath{}, fmt.Errorf("refusing to write %s outside of %s", rel, dir.local)
+	}
+
+	return objectPath{local: file}, nil
+}
+
+// transferOptions configure how files are uploaded and downloaded.
+type transferOptions struct {
+	partSize        int
+	partConcurrency int
+
+	// progress shows a progress bar of the transferred bytes, which is only enabled while copying a single file.
+	progress bool
+}
+
+func addTransferFlags(cmd *cobra.Command, opts *transferOptions) {
+	cmd.Flags().IntVar(&opts.partSize, "part-size", s3.DefaultPartSize>>20, "size in MiB of the parts, in which larger files are uploaded")
+	cmd.Flags().IntVar(&opts.partConcurrency, "part-concurrency", defaultConcurrency, "maximum number of parts of a file uploaded in parallel")
+}
+
+func (t transferOptions) validate() error {
+	if t.partSize < s3.MinPartSize>>20 {
+		return fmt.Errorf("--part-size must be at least %d MiB", s3.MinPartSize>>20)
+	}
+
+	return nil
+}
+
+// runTransfers copies the files and objects. While copying a single file, the transferred bytes are shown in a progress
+// bar.
+func runTransfers(ctx context.Context, client *s3.Client, transfers []transfer, concurrency int, opts transferOptions) error {
+	opts.progress = len(transfers) == 1 && !viper.GetBool(commands.FlagDryRun)
+
+	return commands.RunBulk(ctx, "copy", "object", transfers, concurrency, func(ctx context.Context, t transfer) error {
+		return t.run(ctx, client, opts)
+	})
+}
+
+// transfer copies a single file or object of the given size.
+type transfer struct {
+	source objectPath
+	dest   objectPath
+	size   int64
+}
+
+func (t transfer) String() string {
+	return fmt.Sprintf("%s to %s", t.source, t.dest)
+}
+
+func (t transfer) run(ctx context.Context, client *s3.Client, opts transferOptions) error {
+	switch {
+	case t.source.remote() && t.dest.remote():
+		_, err := objectstorage.NewObjectService(client, t.dest.bucket).Copy(ctx, t.source.bucket, t.source.key, t.dest.key)
+		return err
+	case t.source.remote():
+		return t.download(ctx, client, opts)
+	case t.dest.remote():
+		return t.upload(ctx, client, opts)
+	}
+
+	return fmt.Errorf("either the source or the destination must be a bucket, e.g. %sbucket/key", uriScheme)
+}
+
+// startProgress displays the progress bar, if enabled, and returns the functions counting the transferred bytes and
+// removing the progress bar.
+func (t transfer) startProgress(opts transferOptions) (func(n int64), func()) {
+	if !opts.progress {
+		return func(int64) {}, func() {}
+	}
+
+	progress := console.NewByteProgress(fmt.Sprintf("Copying %s", t), t.size)
+	go progress.Display(commands.Stderr)
+
+	return progress.Add, progress.Done
+}
+
+// upload uploads files larger than the part size in multiple parts. The state of these uploads is kept in the config
+// directory, so running the same upload again after an interruption only uploads the missing parts.
+func (t transfer) upload(ctx context.Context, client *s3.Client, opts transferOptions) error {
+	add, done := t.startProgress(opts)
+	defer done()
+
+	uploader := &s3.Uploader{
+		Client:      client,
+		PartSize:    int64(opts.partSize) << 20,
+		Concurrency: opts.partConcurrency,
+		StateDir:    filepath.Join(commands.ConfigDir(), "uploads"),
+		Progress:    add,
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(t.source.local))
+
+	_, err := uploader.UploadFile(ctx, t.dest.bucket, t.dest.key, t.source.local, s3.PutOptions{ContentType: contentType})
+	return err
+}
+
+// download writes the object to a temporary file next to the destination, which replaces the destination once the
+// object is complete and matches the md5 hash of its ETag.
+func (t transfer) download(ctx context.Context, client *s3.Client, opts transferOptions) error {
+	if viper.GetBool(commands.FlagDryRun) {
+		return commands.ErrDryRun
+	}
+
+	dir := filepath.Dir(t.dest.local)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	body, object, err := objectstorage.NewObjectService(client, t.source.bucket).Get(ctx, t.source.key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(t.dest.local)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	// temporary files are only readable by the owner, while downloads get the permissions of new files
+	if err = tmp.Chmod(0644); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	add, done := t.startProgress(opts)
+	defer done()
+
+	hash := md5.New()
+	written, err := io.Copy(io.MultiWriter(tmp, hash, progressWriter(add)), body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if object.Size >= 0 && written != object.Size {
+		return fmt.Errorf("received %d of %d bytes", written, object.Size)
+	}
+
+	if sum := hex.EncodeToString(hash.Sum(nil)); isMD5(object.ETag) && sum != object.ETag {
+		return fmt.Errorf("verify download: md5 hash %s does not match the etag %q", sum, object.ETag)
+	}
+
+	return os.Rename(tmp.Name(), t.dest.local)
+}
+
+// progressWriter counts the bytes written to it.
+type progressWriter func(n int64)
+
+func (p progressWriter) Write(b []byte) (int, error) {
+	p(int64(len(b)))
+	return len(b), nil
+}
+
+// remove deletes a file or object.
+func remove(ctx context.Context, client *s3.Client, target objectPath) error {
+	if !target.remote() {
+		if viper.GetBool(commands.FlagDryRun) {
+			return commands.ErrDryRun
+		}
+
+		return os.Remove(target.local)
+	}
+
+	return objectstorage.NewObjectService(client, target.bucket).Delete(ctx, target.key)
+}
+
+// modified reports whether the destination differs from the source. Objects uploaded in a single request have the md5
+// hash of their content as ETag, which is compared to local files. Otherwise, the destination is considered outdated
+// if it is older than the source.
+func modified(source entry, dest entry) (bool, error) {
+	if source.size != dest.size {
+		return true, nil
+	}
+
+	if source.etag != "" && dest.etag != "" {
+		return source.etag != dest.etag, nil
+	}
+
+	local, remote := source, dest
+	if source.path.remote() {
+		local, remote = dest, source
+	}
+
+	if !isMD5(remote.etag) {
+		return source.modified.After(dest.modified), nil
+	}
+
+	sum, err := fileMD5(local.path.local)
+	if err != nil {
+		return false, err
+	}
+
+	return sum != remote.etag, nil
+}
+
+func isMD5(etag string) bool {
+	_, err := hex.DecodeString(etag)
+	return err == nil && len(etag) == 2*md5.Size
+}
+
+func fileMD5(name string) (string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := md5.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// notExist reports whether the error is caused by a missing file, object or bucket.
+func notExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || s3.IsNotFound(err)
+}
@@ -0,0 +1,398 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cloudbit-ch/cli/v2/pkg/console"
+	"github.com/cloudbit-ch/cli/v2/pkg/jsonpath"
+)
+
+const (
+	FormatJSON       = "json"
+	FormatTable      = "table"
+	FormatCSV        = "csv"
+	FormatWide       = "wide"
+	FormatYAML       = "yaml"
+	FormatName       = "name"
+	FormatJSONPath   = "jsonpath"
+	FormatGoTemplate = "go-template"
+
+	FormatCustomColumns = "custom-columns"
+)
+
+func Print(out console.Writer, val interface{}) error {
+	val, err := applyListQuery(val)
+	if err != nil {
+		return err
+	}
+
+	format, arg := splitFormat(viper.GetString(FlagFormat))
+
+	switch format {
+	case FormatJSON:
+		return json.NewEncoder(out).Encode(val)
+	case FormatYAML:
+		return printYAML(out, val)
+	case FormatName:
+		return printNames(out, val)
+	case FormatJSONPath:
+		return printJSONPath(out, arg, val)
+	case FormatGoTemplate:
+		return printGoTemplate(out, arg, val)
+	case FormatTable, FormatWide, FormatCSV, FormatCustomColumns:
+		return printTable(out, format, arg, val)
+	}
+
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+// checkFormat verifies the output format before any request is sent.
+func checkFormat(format string) error {
+	name, arg := splitFormat(format)
+
+	switch name {
+	case FormatJSON, FormatYAML, FormatName, FormatTable, FormatWide, FormatCSV:
+		return nil
+	case FormatJSONPath, FormatGoTemplate:
+		if arg == "" {
+			return fmt.Errorf("output format %s requires a template, e.g. %s=TEMPLATE", name, name)
+		}
+
+		return nil
+	case FormatCustomColumns:
+		_, err := parseCustomColumns(arg)
+		return err
+	}
+
+	return fmt.Errorf("unknown output format %q", name)
+}
+
+// CommandFormat returns the format selected by --format out of the formats given by the AnnotationFormats of the
+// command. The first format is used unless another one has been selected explicitly.
+func CommandFormat(cmd *cobra.Command) (string, error) {
+	formats := strings.Split(cmd.Annotations[AnnotationFormats], ",")
+
+	format := viper.GetString(FlagFormat)
+	for _, allowed := range formats {
+		if format == allowed {
+			return format, nil
+		}
+	}
+
+	if !cmd.Flags().Changed(FlagFormat) {
+		return formats[0], nil
+	}
+
+	return "", fmt.Errorf("unknown output format %q, allowed values: %s", format, strings.Join(formats, ", "))
+}
+
+func PrintStdout(val interface{}) error {
+	return Print(Stdout, val)
+}
+
+// splitFormat splits formats with an argument like jsonpath=TEMPLATE into the format and its argument.
+func splitFormat(format string) (string, string) {
+	name, arg, _ := strings.Cut(format, "=")
+	return strings.ToLower(strings.TrimSpace(name)), arg
+}
+
+func printTable(out console.Writer, format string, arg string, val interface{}) error {
+	separator := "   "
+	pretty := true
+
+	if format == FormatCSV {
+		separator = ","
+		pretty = false
+	}
+
+	overflow, maxWidth := tableOverflow(out)
+
+	table := console.Table{
+		Wide:      format == FormatWide,
+		Select:    selectedColumns(),
+		NoHeaders: viper.GetBool(FlagNoHeaders),
+		Overflow:  overflow,
+		MaxWidth:  maxWidth,
+	}
+
+	if format == FormatCustomColumns {
+		columns, err := parseCustomColumns(arg)
+		if err != nil {
+			return err
+		}
+
+		table.Custom = columns
+		table.Select = nil
+	}
+
+	err := table.Insert(val)
+	if err != nil {
+		return err
+	}
+
+	table.Format(out, separator, pretty)
+
+	Stderr.Printf("Found a total of %d items\n", len(table.Rows))
+	return nil
+}
+
+func printYAML(out console.Writer, val interface{}) error {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	// every JSON document is valid YAML, decoding it into a node preserves the order of the fields
+	node := &yaml.Node{}
+	if err = yaml.Unmarshal(data, node); err != nil {
+		return err
+	}
+
+	resetStyle(node)
+
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+
+	if err = encoder.Encode(node); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// printNames prints the name of every item on a separate line.
+func printNames(out console.Writer, val interface{}) error {
+	value := reflect.ValueOf(val)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		out.Println(nameOf(val))
+		return nil
+	}
+
+	for i := 0; i < value.Len(); i++ {
+		out.Println(nameOf(value.Index(i).Interface()))
+	}
+
+	return nil
+}
+
+func nameOf(item interface{}) string {
+	if displayable, ok := item.(console.Displayable); ok {
+		if name, ok := displayable.Values()["name"]; ok {
+			return fmt.Sprint(name)
+		}
+	}
+
+	if stringer, ok := item.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+
+	return fmt.Sprint(item)
+}
+
+func printJSONPath(out console.Writer, text string, val interface{}) error {
+	tmpl, err := jsonpath.Parse(text)
+	if err != nil {
+		return fmt.Errorf("parse jsonpath: %w", err)
+	}
+
+	data, err := templateData(val)
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	if err = tmpl.Execute(buf, data); err != nil {
+		return fmt.Errorf("execute jsonpath: %w", err)
+	}
+
+	writeTemplateOutput(out, buf)
+	return nil
+}
+
+func printGoTemplate(out console.Writer, text string, val interface{}) error {
+	tmpl, err := template.New("output").Parse(text)
+	if err != nil {
+		return fmt.Errorf("parse template: %w", err)
+	}
+
+	data, err := templateData(val)
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	if err = tmpl.Execute(buf, data); err != nil {
+		return fmt.Errorf("execute template: %w", err)
+	}
+
+	writeTemplateOutput(out, buf)
+	return nil
+}
+
+// templateData converts the value into its JSON representation, so templates address fields by their JSON names.
+// Lists are wrapped into an object with an items field, the same way kubectl does.
+func templateData(val interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+
+	var data interface{}
+	if err = json.Unmarshal(encoded, &data); err != nil {
+		return nil, err
+	}
+
+	if items, ok := data.([]interface{}); ok {
+		return map[string]interface{}{"items": items}, nil
+	}
+
+	if data == nil && reflect.ValueOf(val).Kind() == reflect.Slice {
+		return map[string]interface{}{"items": []interface{}{}}, nil
+	}
+
+	return data, nil
+}
+
+func writeTemplateOutput(out console.Writer, buf *bytes.Buffer) {
+	out.Print(buf.String())
+
+	if buf.Len() != 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		out.Println()
+	}
+}
+
+// tableOverflow determines how the table is fitted into the terminal. Tables written to a terminal are truncated by
+// default. Otherwise, the width is only limited if requested explicitly, using the COLUMNS environment variable.
+func tableOverflow(out console.Writer) (console.Overflow, int) {
+	overflow := console.OverflowTruncate
+	explicit := viper.GetBool(FlagTruncate)
+
+	if viper.GetBool(FlagWrap) {
+		overflow = console.OverflowWrap
+		explicit = true
+	}
+
+	if console.TerminalWidth(out) != 0 {
+		return overflow, 0
+	}
+
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if !explicit || err != nil || width <= 0 {
+		return console.OverflowNone, 0
+	}
+
+	return overflow, width
+}
+
+func selectedColumns() []string {
+	var columns []string
+	for _, col := range strings.Split(viper.GetString(FlagColumns), ",") {
+		if col = strings.TrimSpace(col); col != "" {
+			columns = append(columns, col)
+		}
+	}
+
+	return columns
+}
+
+// parseCustomColumns parses the specification of custom columns in the form HEADER:PATH[,HEADER:PATH...]. The path is
+// a jsonpath expression evaluated on the JSON representation of every item.
+func parseCustomColumns(spec string) ([]console.CustomColumn, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, fmt.Errorf("output format %[1]s requires a column specification, e.g. %[1]s=NAME:.name,ID:.id", FormatCustomColumns)
+	}
+
+	var columns []console.CustomColumn
+	for _, part := range splitCustomColumns(spec) {
+		name, path, ok := strings.Cut(part, ":")
+		if !ok || strings.TrimSpace(name) == "" || strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("invalid custom column %q, expected HEADER:PATH", part)
+		}
+
+		path = strings.TrimSpace(path)
+		if !strings.HasPrefix(path, "{") {
+			path = "{" + path + "}"
+		}
+
+		tmpl, err := jsonpath.Parse(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom column %q: %w", part, err)
+		}
+
+		columns = append(columns, console.CustomColumn{
+			Name:  strings.TrimSpace(name),
+			Value: customColumnValue(tmpl),
+		})
+	}
+
+	return columns, nil
+}
+
+// splitCustomColumns splits the specification of custom columns on the commas outside of braces and brackets. Commas
+// within them belong to the jsonpath, e.g. to quoted text like {", "} or to the values of filters.
+func splitCustomColumns(spec string) []string {
+	var (
+		parts []string
+		depth int
+		quote rune
+		start int
+	)
+
+	for idx, r := range spec {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case depth > 0 && (r == '\'' || r == '"'):
+			quote = r
+		case r == '{' || r == '[':
+			depth++
+		case (r == '}' || r == ']') && depth > 0:
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, spec[start:idx])
+			start = idx + 1
+		}
+	}
+
+	return append(parts, spec[start:])
+}
+
+func customColumnValue(tmpl *jsonpath.Template) func(item interface{}) (string, error) {
+	return func(item interface{}) (string, error) {
+		data, err := templateData(item)
+		if err != nil {
+			return "", err
+		}
+
+		buf := &bytes.Buffer{}
+		if err = tmpl.Execute(buf, data); err != nil {
+			return "", err
+		}
+
+		if buf.Len() == 0 {
+			return "<none>", nil
+		}
+
+		return buf.String(), nil
+	}
+}
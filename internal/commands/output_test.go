@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCustomColumns(t *testing.T) {
+	item := map[string]interface{}{
+		"name": "web",
+		"networks": []interface{}{
+			map[string]interface{}{"name": "public", "ip": "1.2.3.4"},
+			map[string]interface{}{"name": "private, internal", "ip": "10.0.0.1"},
+		},
+	}
+
+	tests := []struct {
+		spec   string
+		header []string
+		values []string
+		err    string
+	}{
+		{spec: "NAME:.name,IP:.networks[0].ip", header: []string{"NAME", "IP"}, values: []string{"web", "1.2.3.4"}},
+		{spec: `NETWORKS:{.networks[0].name}{", "}{.networks[1].ip}`, header: []string{"NETWORKS"}, values: []string{"public, 10.0.0.1"}},
+		{spec: `IP:.networks[0]['ip'],NAME:{.name}`, header: []string{"IP", "NAME"}, values: []string{"1.2.3.4", "web"}},
+		{spec: `PRIVATE:.networks[?(@.name=="private, internal")].ip,NAME:.name`, header: []string{"PRIVATE", "NAME"}, values: []string{"10.0.0.1", "web"}},
+		{spec: "NAME:.name,", err: "expected HEADER:PATH"},
+		{spec: "NAME", err: "expected HEADER:PATH"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			columns, err := parseCustomColumns(test.spec)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var header, values []string
+			for _, column := range columns {
+				value, err := column.Value(item)
+				if err != nil {
+					t.Fatalf("column %s: %v", column.Name, err)
+				}
+
+				header = append(header, column.Name)
+				values = append(values, value)
+			}
+
+			if strings.Join(header, "|") != strings.Join(test.header, "|") || strings.Join(values, "|") != strings.Join(test.values, "|") {
+				t.Errorf("expected %v %v, got %v %v", test.header, test.values, header, values)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+type Profile struct {
+	Token    string `json:"token,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	Location string `json:"location,omitempty"`
+	Keyring  bool   `json:"keyring,omitempty"`
+}
+
+// profileKeys contains the keys of Profile in the config file.
+var profileKeys = []string{"token", "endpoint", "location", "keyring"}
+
+// ConfigFile provides direct access to the contents of the config file. In contrast to viper, it only contains the
+// values stored in the file and is therefore safe to be written back. Unknown keys are preserved.
+type ConfigFile struct {
+	Path string
+
+	values map[string]interface{}
+}
+
+// ConfigFilePath returns the path of the config file in use, regardless of whether it exists or not.
+func ConfigFilePath() string {
+	if len(configFile) != 0 {
+		return configFile
+	}
+
+	if used := viper.ConfigFileUsed(); len(used) != 0 {
+		return used
+	}
+
+	return filepath.Join(configDir, "config.json")
+}
+
+func LoadConfigFile() (*ConfigFile, error) {
+	file := &ConfigFile{
+		Path:   ConfigFilePath(),
+		values: map[string]interface{}{},
+	}
+
+	data, err := os.ReadFile(file.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return file, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return file, nil
+	}
+
+	if err = json.Unmarshal(data, &file.values); err != nil {
+		return nil, fmt.Errorf("parse config file: %w", err)
+	}
+
+	return file, nil
+}
+
+func (c *ConfigFile) Save() error {
+	data, err := json.MarshalIndent(c.values, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(c.Path), 0700); err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(c.Path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("write config file: %w", err)
+	}
+
+	// the permissions of an already existing file are not changed by os.WriteFile
+	return os.Chmod(c.Path, 0600)
+}
+
+func (c *ConfigFile) Get(key string) interface{} {
+	return c.values[key]
+}
+
+func (c *ConfigFile) Set(key string, value interface{}) {
+	if value == nil {
+		delete(c.values, key)
+		return
+	}
+
+	c.values[key] = value
+}
+
+func (c *ConfigFile) CurrentProfile() string {
+	name, _ := c.values[KeyCurrentProfile].(string)
+	return name
+}
+
+func (c *ConfigFile) SetCurrentProfile(name string) {
+	if len(name) == 0 {
+		c.Set(KeyCurrentProfile, nil)
+		return
+	}
+
+	c.Set(KeyCurrentProfile, strings.ToLower(name))
+}
+
+// rawProfiles returns the profiles as stored in the config file, including keys which are unknown to Profile.
+func (c *ConfigFile) rawProfiles() (map[string]map[string]interface{}, error) {
+	profiles := map[string]map[string]interface{}{}
+
+	raw, ok := c.values[KeyProfiles]
+	if !ok {
+		return profiles, nil
+	}
+
+	entries, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("parse profiles: expected an object, got %T", raw)
+	}
+
+	for name, entry := range entries {
+		values, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("parse profiles: expected profile %s to be an object, got %T", name, entry)
+		}
+
+		profiles[name] = values
+	}
+
+	return profiles, nil
+}
+
+func (c *ConfigFile) setRawProfiles(profiles map[string]map[string]interface{}) {
+	values := make(map[string]interface{}, len(profiles))
+	for name, profile := range profiles {
+		values[name] = profile
+	}
+
+	c.values[KeyProfiles] = values
+}
+
+func (c *ConfigFile) Profiles() (map[string]Profile, error) {
+	profiles := map[string]Profile{}
+
+	raw, err := c.rawProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("parse profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+func (c *ConfigFile) Profile(name string) (Profile, bool, error) {
+	profiles, err := c.Profiles()
+	if err != nil {
+		return Profile{}, false, err
+	}
+
+	profile, ok := profiles[strings.ToLower(name)]
+	return profile, ok, nil
+}
+
+// SetProfile stores the profile under the given name. Only the keys known to Profile are overwritten, other keys of an
+// existing profile are kept.
+func (c *ConfigFile) SetProfile(name string, profile Profile) error {
+	profiles, err := c.rawProfiles()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+
+	known := map[string]interface{}{}
+	if err = json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+
+	values, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		values = map[string]interface{}{}
+	}
+
+	// empty fields are omitted when marshalling, so they have to be removed explicitly
+	for _, key := range profileKeys {
+		delete(values, key)
+	}
+
+	for key, value := range known {
+		values[key] = value
+	}
+
+	profiles[strings.ToLower(name)] = values
+	c.setRawProfiles(profiles)
+	return nil
+}
+
+func (c *ConfigFile) DeleteProfile(name string) error {
+	profiles, err := c.rawProfiles()
+	if err != nil {
+		return err
+	}
+
+	delete(profiles, strings.ToLower(name))
+	c.setRawProfiles(profiles)
+
+	if c.CurrentProfile() == strings.ToLower(name) {
+		c.SetCurrentProfile("")
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetProfilePreservesUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{
+  "current_profile": "staging",
+  "editor": "vim",
+  "profiles": {
+    "staging": {"token": "old", "location": "ALP1", "color": "yellow"},
+    "production": {"endpoint": "https://api.example.com", "tags": ["critical"]}
+  }
+}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := configFile
+	configFile = path
+	t.Cleanup(func() { configFile = previous })
+
+	file, err := LoadConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile, ok, err := file.Profile("Staging")
+	if err != nil || !ok {
+		t.Fatalf("expected profile staging, got %v, %v", ok, err)
+	}
+
+	profile.Token = "new"
+	profile.Location = ""
+	if err = file.SetProfile("staging", profile); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = file.SetProfile("development", Profile{Endpoint: "http://localhost:8080"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = file.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var values struct {
+		Editor   string                            `json:"editor"`
+		Profiles map[string]map[string]interface{} `json:"profiles"`
+	}
+	if err = json.Unmarshal(written, &values); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"editor":      "vim",
+		"staging":     `{"color":"yellow","token":"new"}`,
+		"production":  `{"endpoint":"https://api.example.com","tags":["critical"]}`,
+		"development": `{"endpoint":"http://localhost:8080"}`,
+	}
+
+	actual := map[string]string{"editor": values.Editor}
+	for name, profile := range values.Profiles {
+		encoded, _ := json.Marshal(profile)
+		actual[name] = string(encoded)
+	}
+
+	for key, value := range expected {
+		if actual[key] != value {
+			t.Errorf("expected %s to be %s, got %s", key, value, actual[key])
+		}
+	}
+
+	if len(values.Profiles) != 3 {
+		t.Errorf("expected 3 profiles, got %d", len(values.Profiles))
+	}
+
+	if err = file.DeleteProfile("production"); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := file.Profiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok = profiles["production"]; ok || len(profiles) != 2 {
+		t.Errorf("expected production to be deleted, got %v", profiles)
+	}
+}
+
+func TestProfilesInvalid(t *testing.T) {
+	file := &ConfigFile{values: map[string]interface{}{KeyProfiles: map[string]interface{}{"staging": "token"}}}
+
+	if _, err := file.Profiles(); err == nil {
+		t.Error("expected an error for a profile which is not an object")
+	}
+
+	if err := file.SetProfile("staging", Profile{Token: "new"}); err == nil {
+		t.Error("expected an error when overwriting a profile which is not an object")
+	}
+}
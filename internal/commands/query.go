@@ -0,0 +1,408 @@
+package commands
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudbit-ch/cli/v2/pkg/console"
+)
+
+const (
+	FlagWhere  = "where"
+	FlagSortBy = "sort-by"
+	FlagLimit  = "limit"
+)
+
+// listQuery holds the flags of the list commands, which are evaluated on the values of the printed items. Since only
+// a single command is executed, all list commands share the same instance.
+var listQuery struct {
+	where  string
+	sortBy string
+	limit  int
+}
+
+// setupListFlags adds the query flags to every list command of the tree.
+func setupListFlags(cmd *cobra.Command) {
+	for _, child := range cmd.Commands() {
+		setupListFlags(child)
+	}
+
+	if cmd.Name() != "list" {
+		return
+	}
+
+	cmd.Flags().StringVar(&listQuery.where, FlagWhere, "", "only show items matching the conditions, e.g. 'status==running,location=~ALP'")
+	cmd.Flags().StringVar(&listQuery.sortBy, FlagSortBy, "", "comma separated columns to sort by, prefix a column with - to sort in descending order")
+	cmd.Flags().IntVar(&listQuery.limit, FlagLimit, 0, "maximum number of items to show")
+}
+
+type conditionOperator string
+
+const (
+	operatorEqual        conditionOperator = "=="
+	operatorNotEqual     conditionOperator = "!="
+	operatorMatch        conditionOperator = "=~"
+	operatorNotMatch     conditionOperator = "!~"
+	operatorLess         conditionOperator = "<"
+	operatorLessEqual    conditionOperator = "<="
+	operatorGreater      conditionOperator = ">"
+	operatorGreaterEqual conditionOperator = ">="
+)
+
+// operators are ordered such that no operator is a prefix of a later one.
+var operators = []conditionOperator{
+	operatorEqual, operatorNotEqual, operatorMatch, operatorNotMatch,
+	operatorLessEqual, operatorGreaterEqual, operatorLess, operatorGreater,
+}
+
+type condition struct {
+	column   string
+	operator conditionOperator
+	value    string
+	regex    *regexp.Regexp
+}
+
+type sortKey struct {
+	column     string
+	descending bool
+}
+
+// parseConditions parses a comma separated list of conditions, which all have to match. Commas within a value can be
+// escaped with a backslash. A single equal sign is accepted as alias for "==".
+func parseConditions(expr string) ([]condition, error) {
+	var conditions []condition
+
+	for _, part := range splitEscaped(expr, ',') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		cond, err := parseCondition(part)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, cond)
+	}
+
+	return conditions, nil
+}
+
+func parseCondition(expr string) (condition, error) {
+	idx := strings.IndexAny(expr, "=!<>")
+	if idx <= 0 {
+		return condition{}, fmt.Errorf("invalid condition %q: expected COLUMN OPERATOR VALUE", expr)
+	}
+
+	cond := condition{
+		column:   strings.ToLower(strings.TrimSpace(expr[:idx])),
+		operator: operatorEqual,
+	}
+
+	rest := expr[idx:]
+	matched := false
+
+	for _, op := range operators {
+		if strings.HasPrefix(rest, string(op)) {
+			cond.operator = op
+			cond.value = rest[len(op):]
+			matched = true
+			break
+		}
+	}
+
+	if !matched {
+		if rest[0] != '=' {
+			return condition{}, fmt.Errorf("invalid operator in condition %q", expr)
+		}
+
+		cond.value = rest[1:]
+	}
+
+	cond.value = strings.TrimSpace(cond.value)
+
+	if cond.operator == operatorMatch || cond.operator == operatorNotMatch {
+		regex, err := regexp.Compile("(?i)" + cond.value)
+		if err != nil {
+			return condition{}, fmt.Errorf("invalid regular expression in condition %q: %w", expr, err)
+		}
+
+		cond.regex = regex
+	}
+
+	return cond, nil
+}
+
+func (c condition) matches(value string) bool {
+	switch c.operator {
+	case operatorEqual:
+		return strings.EqualFold(value, c.value)
+	case operatorNotEqual:
+		return !strings.EqualFold(value, c.value)
+	case operatorMatch:
+		return c.regex.MatchString(value)
+	case operatorNotMatch:
+		return !c.regex.MatchString(value)
+	}
+
+	cmp := compareValues(value, c.value)
+	switch c.operator {
+	case operatorLess:
+		return cmp < 0
+	case operatorLessEqual:
+		return cmp <= 0
+	case operatorGreater:
+		return cmp > 0
+	case operatorGreaterEqual:
+		return cmp >= 0
+	}
+
+	return false
+}
+
+func parseSortKeys(expr string) []sortKey {
+	var keys []sortKey
+
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		key := sortKey{column: part}
+		if strings.HasPrefix(part, "-") {
+			key = sortKey{column: strings.TrimSpace(part[1:]), descending: true}
+		} else if strings.HasPrefix(part, "+") {
+			key.column = strings.TrimSpace(part[1:])
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// compareValues compares two rendered values numerically if both are numbers and lexicographically otherwise.
+func compareValues(a, b string) int {
+	numA, errA := strconv.ParseFloat(a, 64)
+	numB, errB := strconv.ParseFloat(b, 64)
+
+	if errA == nil && errB == nil {
+		switch {
+		case numA < numB:
+			return -1
+		case numA > numB:
+			return 1
+		}
+
+		return 0
+	}
+
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// applyListQuery filters, sorts and limits the items of a slice of displayables according to the list flags. Any other
+// value is returned unchanged. The columns used by the flags are checked against the type of the items, so that typos
+// are reported even if the list is empty.
+func applyListQuery(val interface{}) (interface{}, error) {
+	slice := reflect.ValueOf(val)
+	if slice.Kind() != reflect.Slice {
+		return val, nil
+	}
+
+	conditions, err := parseConditions(listQuery.where)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := parseSortKeys(listQuery.sortBy)
+
+	// slices of interfaces may contain items of different types, which are checked one by one
+	columns, typed := typeColumns(slice.Type().Elem())
+	if typed {
+		if err = checkColumns(columns, conditions, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	if listQuery.where == "" && listQuery.sortBy == "" && listQuery.limit <= 0 {
+		return val, nil
+	}
+
+	type entry struct {
+		item   reflect.Value
+		values map[string]string
+	}
+
+	var entries []entry
+	for i := 0; i < slice.Len(); i++ {
+		item := slice.Index(i)
+
+		displayable, ok := item.Interface().(console.Displayable)
+		if !ok {
+			return val, nil
+		}
+
+		if !typed {
+			if err = checkColumns(displayableColumns(displayable), conditions, keys); err != nil {
+				return nil, err
+			}
+		}
+
+		values := renderValues(displayable)
+		if matchesAll(values, conditions) {
+			entries = append(entries, entry{item: item, values: values})
+		}
+	}
+
+	if len(keys) != 0 {
+		sort.SliceStable(entries, func(i, j int) bool {
+			for _, key := range keys {
+				cmp := compareValues(entries[i].values[key.column], entries[j].values[key.column])
+				if cmp == 0 {
+					continue
+				}
+
+				if key.descending {
+					return cmp > 0
+				}
+
+				return cmp < 0
+			}
+
+			return false
+		})
+	}
+
+	if listQuery.limit > 0 && len(entries) > listQuery.limit {
+		entries = entries[:listQuery.limit]
+	}
+
+	res := reflect.MakeSlice(slice.Type(), len(entries), len(entries))
+	for i, e := range entries {
+		res.Index(i).Set(e.item)
+	}
+
+	return res.Interface(), nil
+}
+
+// renderValues renders the values of the displayable the same way the table does, using lower case column names.
+func renderValues(displayable console.Displayable) map[string]string {
+	values := map[string]string{}
+	for key, val := range displayable.Values() {
+		values[strings.ToLower(key)] = fmt.Sprintf("%+v", val)
+	}
+
+	return values
+}
+
+func matchesAll(values map[string]string, conditions []condition) bool {
+	for _, cond := range conditions {
+		if !cond.matches(values[cond.column]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// typeColumns returns the columns of a displayable type including its wide columns. The columns are taken from the
+// zero value of the type, as they do not depend on the values of the items.
+func typeColumns(typ reflect.Type) ([]string, bool) {
+	item := reflect.New(typ).Elem()
+	if typ.Kind() == reflect.Pointer {
+		item = reflect.New(typ.Elem())
+	}
+
+	displayable, ok := item.Interface().(console.Displayable)
+	if !ok {
+		return nil, false
+	}
+
+	return displayableColumns(displayable), true
+}
+
+func displayableColumns(displayable console.Displayable) []string {
+	columns := displayable.Columns()
+	if wide, ok := displayable.(console.WideDisplayable); ok {
+		columns = append(columns, wide.WideColumns()...)
+	}
+
+	return columns
+}
+
+// checkColumns verifies that the columns selected by --columns and used by --where and --sort-by exist.
+func checkColumns(columns []string, conditions []condition, keys []sortKey) error {
+	known := map[string]bool{}
+	for _, column := range columns {
+		known[strings.ToLower(column)] = true
+	}
+
+	check := func(column string) error {
+		if known[column] {
+			return nil
+		}
+
+		return fmt.Errorf("unknown column %q, available columns: %s", column, strings.Join(columns, ", "))
+	}
+
+	for _, column := range selectedColumns() {
+		if err := check(strings.ToLower(column)); err != nil {
+			return err
+		}
+	}
+
+	for _, cond := range conditions {
+		if err := check(cond.column); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range keys {
+		if err := check(key.column); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func splitEscaped(s string, sep rune) []string {
+	var (
+		parts   []string
+		current strings.Builder
+		escaped bool
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			if r != sep && r != '\\' {
+				current.WriteRune('\\')
+			}
+
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == sep:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	if escaped {
+		current.WriteRune('\\')
+	}
+
+	return append(parts, current.String())
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+type queryItem struct {
+	Name string
+	Size int
+}
+
+func (q queryItem) Columns() []string {
+	return []string{"name"}
+}
+
+func (q queryItem) WideColumns() []string {
+	return []string{"size"}
+}
+
+func (q queryItem) Values() map[string]interface{} {
+	return map[string]interface{}{"name": q.Name, "size": q.Size}
+}
+
+func TestApplyListQuery(t *testing.T) {
+	items := []queryItem{{"b", 2}, {"a", 10}, {"c", 1}}
+
+	tests := []struct {
+		name    string
+		where   string
+		sortBy  string
+		columns string
+		items   []queryItem
+		result  string
+		err     string
+	}{
+		{name: "sort by wide column", sortBy: "-size", items: items, result: "a,b,c"},
+		{name: "filter", where: "size>1", sortBy: "name", items: items, result: "a,b"},
+		{name: "unknown sort column", sortBy: "nmae", items: items, err: `unknown column "nmae"`},
+		{name: "unknown column of empty list", where: "nmae=a", items: []queryItem{}, err: `unknown column "nmae"`},
+		{name: "unknown selected column of empty list", columns: "name,sizee", err: `unknown column "sizee"`},
+		{name: "selected column", columns: "Name, SIZE", items: items, result: "b,a,c"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			listQuery.where, listQuery.sortBy = test.where, test.sortBy
+			viper.Set(FlagColumns, test.columns)
+			defer func() {
+				listQuery.where, listQuery.sortBy = "", ""
+				viper.Set(FlagColumns, "")
+			}()
+
+			res, err := applyListQuery(test.items)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var names []string
+			for _, item := range res.([]queryItem) {
+				names = append(names, item.Name)
+			}
+
+			if strings.Join(names, ",") != test.result {
+				t.Errorf("expected %s, got %s", test.result, strings.Join(names, ","))
+			}
+		})
+	}
+}
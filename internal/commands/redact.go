@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const redacted = "REDACTED"
+
+// redactedHeaders contain credentials and are never written to dumps or trace files.
+var redactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Auth-Token",
+}
+
+// redactedFields are the json fields of request and response bodies holding secrets, e.g. the password of windows
+// servers and mac bare metal devices, the secret key of object storage credentials, the private key of certificates and
+// the kube config of kubernetes clusters.
+var redactedFields = map[string]bool{
+	"password":    true,
+	"secret_key":  true,
+	"private_key": true,
+	"kube_config": true,
+	"token":       true,
+}
+
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range redactedHeaders {
+		if values := header.Values(name); len(values) != 0 {
+			header.Set(name, redacted)
+		}
+	}
+
+	return header
+}
+
+// redactBody replaces the values of secret fields within a json body. Bodies which are not json are returned as is.
+func redactBody(body []byte) []byte {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return body
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(trimmed, &data); err != nil {
+		return body
+	}
+
+	if !redactValue(data) {
+		return body
+	}
+
+	res, err := json.Marshal(data)
+	if err != nil {
+		return body
+	}
+
+	return res
+}
+
+// redactValue redacts the secret fields within the generic json value in place and reports whether any were found.
+func redactValue(value interface{}) bool {
+	found := false
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if _, ok := item.(string); ok && redactedFields[strings.ToLower(key)] {
+				value[key] = redacted
+				found = true
+				continue
+			}
+
+			found = redactValue(item) || found
+		}
+	case []interface{}:
+		for _, item := range value {
+			found = redactValue(item) || found
+		}
+	}
+
+	return found
+}
@@ -2,9 +2,13 @@ package commands
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"os/exec"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/cloudbit-ch/cli/v2/pkg/console"
 )
@@ -16,6 +20,14 @@ var (
 
 var Root = cobra.Command{}
 
+// AnnotationNoAuthentication marks commands which do not need an authentication token. The annotation is inherited by
+// all subcommands.
+const AnnotationNoAuthentication = "no-authentication"
+
+// AnnotationFormats lists the formats of commands which do not print their output using the common output formats,
+// separated by commas. The global --format flag selects one of them and defaults to the first.
+const AnnotationFormats = "formats"
+
 type ModuleFactory func(app Application) *cobra.Command
 
 type Application struct {
@@ -40,14 +52,86 @@ func Run(app Application) {
 		root.AddCommand(cmd)
 	}
 
+	silenceDryRunUsage(&root)
+
 	setupFlags(app, &root)
-	cobra.OnInitialize(func() {
-		loadConfig(app)
-	})
+	setupListFlags(&root)
+	cancel := func() {}
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		err := loadConfig(app, cmd)
+		if err != nil {
+			// configuration errors are unrelated to the usage of the command
+			cmd.SilenceUsage = true
+			return err
+		}
+
+		if timeout := viper.GetDuration(FlagTimeout); timeout > 0 {
+			var ctx context.Context
+			ctx, cancel = context.WithTimeout(cmd.Context(), timeout)
+			cmd.SetContext(ctx)
+		}
+
+		return nil
+	}
 
 	err := root.ExecuteContext(context.Background())
+	cancel()
+
+	if traceErr := finishTrace(); traceErr != nil {
+		Stderr.Errorf("%v\n", traceErr)
+	}
+
+	// the dry run aborts the command at the first request modifying a resource, after its payload has been printed
+	if errors.Is(err, ErrDryRun) {
+		err = nil
+	}
+
+	// external programs, like ssh, already reported their failure and the exit code is passed on to the caller
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		os.Exit(exitErr.ExitCode())
+	}
+
 	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("%w (--%s %s)", err, FlagTimeout, WaitTimeout())
+		}
+
 		Stderr.Errorf("%v\n", err)
 		os.Exit(1)
 	}
 }
+
+// silenceDryRunUsage prevents the usage from being printed if a command got aborted by the dry run, since the command
+// has been used correctly in that case.
+func silenceDryRunUsage(cmd *cobra.Command) {
+	if run := cmd.RunE; run != nil {
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			err := run(cmd, args)
+			if errors.Is(err, ErrDryRun) {
+				cmd.SilenceUsage = true
+			}
+
+			return err
+		}
+	}
+
+	for _, child := range cmd.Commands() {
+		silenceDryRunUsage(child)
+	}
+}
+
+func requiresAuthentication(cmd *cobra.Command) bool {
+	// completions must not fail because of a missing token, they are empty in that case anyway
+	if cmd.Name() == cobra.ShellCompRequestCmd {
+		return false
+	}
+
+	for c := cmd; c != nil; c = c.Parent() {
+		if _, ok := c.Annotations[AnnotationNoAuthentication]; ok {
+			return false
+		}
+	}
+
+	return true
+}
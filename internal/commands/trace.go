@@ -0,0 +1,402 @@
+package commands
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// traceRecorder collects the requests of the command for the --trace-file flag. Files ending in .har are written as
+// HTTP archive once the command has finished, all other files are written as JSON lines, one entry per request, as soon
+// as the request has finished.
+type traceRecorder struct {
+	path    string
+	app     Application
+	har     bool
+	mu      sync.Mutex
+	file    *os.File
+	entries []harEntry
+}
+
+var activeTrace *traceRecorder
+
+// startTrace creates the recorder of the trace file, which is shared by all clients of the command.
+func startTrace(app Application, path string) *traceRecorder {
+	if activeTrace == nil || activeTrace.path != path {
+		activeTrace = &traceRecorder{
+			path: path,
+			app:  app,
+			har:  strings.EqualFold(filepath.Ext(path), ".har"),
+		}
+	}
+
+	return activeTrace
+}
+
+// finishTrace writes the HTTP archive of the command, if a trace file was requested.
+func finishTrace() error {
+	if activeTrace == nil {
+		return nil
+	}
+
+	return activeTrace.close()
+}
+
+func (t *traceRecorder) record(entry harEntry) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.har {
+		t.entries = append(t.entries, entry)
+		return nil
+	}
+
+	if t.file == nil {
+		file, err := os.OpenFile(t.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return fmt.Errorf("open trace file: %w", err)
+		}
+
+		t.file = file
+	}
+
+	return json.NewEncoder(t.file).Encode(entry)
+}
+
+func (t *traceRecorder) close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.har {
+		if t.file == nil {
+			return nil
+		}
+
+		return t.file.Close()
+	}
+
+	entries := t.entries
+	if entries == nil {
+		entries = []harEntry{}
+	}
+
+	archive := harArchive{
+		Log: harLog{
+			Version: "1.2",
+			Creator: harCreator{Name: t.app.Name, Version: t.app.Version},
+			Entries: entries,
+		},
+	}
+
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode trace file: %w", err)
+	}
+
+	if err = os.WriteFile(t.path, data, 0600); err != nil {
+		return fmt.Errorf("write trace file: %w", err)
+	}
+
+	return nil
+}
+
+var _ http.RoundTripper = (*traceTransport)(nil)
+
+// traceTransport records every request and its response including the timings. Credentials and secrets are redacted
+// the same way as for the dump and bodies are omitted according to bodyLimit, see recordBody.
+type traceTransport struct {
+	delegate  http.RoundTripper
+	recorder  *traceRecorder
+	bodyLimit int64
+}
+
+func (t traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := recordRequestBody(req, t.bodyLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	timings := &traceTimings{start: time.Now()}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), timings.clientTrace()))
+
+	res, err := t.base().RoundTrip(req)
+
+	var resBody recordedBody
+	if err == nil {
+		timings.mark(&timings.firstByte)
+		resBody, err = recordResponseBody(res, t.bodyLimit)
+	}
+
+	timings.end = time.Now()
+
+	entry := newHAREntry(req, body, res, resBody, err, timings)
+	if recordErr := t.recorder.record(entry); recordErr != nil {
+		Stderr.Errorf("%v\n", recordErr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (t traceTransport) base() http.RoundTripper {
+	if t.delegate == nil {
+		return http.DefaultTransport
+	}
+
+	return t.delegate
+}
+
+type traceTimings struct {
+	mu sync.Mutex
+
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	end          time.Time
+}
+
+func (t *traceTimings) mark(field *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if field.IsZero() {
+		*field = time.Now()
+	}
+}
+
+func (t *traceTimings) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.mark(&t.dnsDone) },
+		ConnectStart:         func(string, string) { t.mark(&t.connectStart) },
+		ConnectDone:          func(string, string, error) { t.mark(&t.connectDone) },
+		TLSHandshakeStart:    func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.mark(&t.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.mark(&t.wroteRequest) },
+		GotFirstResponseByte: func() { t.mark(&t.firstByte) },
+	}
+}
+
+// harTimings converts the timings into the phases of a HAR entry. Phases which did not happen, e.g. because an existing
+// connection got reused, are reported as -1.
+func (t *traceTimings) harTimings() harTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	between := func(from, to time.Time) float64 {
+		if from.IsZero() || to.IsZero() {
+			return -1
+		}
+
+		return float64(to.Sub(from).Microseconds()) / 1000
+	}
+
+	nonNegative := func(value float64) float64 {
+		if value < 0 {
+			return 0
+		}
+
+		return value
+	}
+
+	sendStart := t.start
+	if !t.connectDone.IsZero() {
+		sendStart = t.connectDone
+	}
+
+	if !t.tlsDone.IsZero() {
+		sendStart = t.tlsDone
+	}
+
+	wroteRequest := t.wroteRequest
+	if wroteRequest.IsZero() {
+		wroteRequest = sendStart
+	}
+
+	firstByte := t.firstByte
+	if firstByte.IsZero() {
+		firstByte = t.end
+	}
+
+	return harTimings{
+		Blocked: -1,
+		DNS:     between(t.dnsStart, t.dnsDone),
+		Connect: between(t.connectStart, t.connectDone),
+		SSL:     between(t.tlsStart, t.tlsDone),
+		Send:    nonNegative(between(sendStart, wroteRequest)),
+		Wait:    nonNegative(between(wroteRequest, firstByte)),
+		Receive: nonNegative(between(firstByte, t.end)),
+	}
+}
+
+type harArchive struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func newHAREntry(req *http.Request, reqBody recordedBody, res *http.Response, resBody recordedBody, err error, timings *traceTimings) harEntry {
+	entry := harEntry{
+		StartedDateTime: timings.start.Format(time.RFC3339Nano),
+		Time:            float64(timings.end.Sub(timings.start).Microseconds()) / 1000,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         req.URL.String(),
+			HTTPVersion: req.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(redactHeader(req.Header)),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    int(reqBody.size),
+		},
+		Response: harResponse{
+			Cookies:     []harNameValue{},
+			Headers:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: timings.harTimings(),
+	}
+
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	if reqBody.size != 0 {
+		entry.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     reqBody.text(),
+		}
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if res != nil {
+		mimeType := res.Header.Get("Content-Type")
+		if mediaType, _, parseErr := mime.ParseMediaType(mimeType); parseErr == nil {
+			mimeType = mediaType
+		}
+
+		entry.Response.Status = res.StatusCode
+		entry.Response.StatusText = strings.TrimSpace(strings.TrimPrefix(res.Status, fmt.Sprint(res.StatusCode)))
+		entry.Response.HTTPVersion = res.Proto
+		entry.Response.Headers = harHeaders(redactHeader(res.Header))
+		entry.Response.BodySize = int(resBody.size)
+		entry.Response.Content = harContent{
+			Size:     int(resBody.size),
+			MimeType: mimeType,
+			Text:     resBody.text(),
+		}
+	}
+
+	return entry
+}
+
+func harHeaders(header http.Header) []harNameValue {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	headers := make([]harNameValue, 0, len(header))
+	for _, name := range names {
+		for _, value := range header[name] {
+			headers = append(headers, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	return headers
+}
@@ -1,32 +1,76 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"mime"
 	"net/http"
 	"net/http/httputil"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/cloudbit-ch/cli/v2/pkg/console"
 )
 
+// ErrDryRun is returned by the client in dry run mode instead of sending a request which would modify a resource. The
+// command is aborted by it and exits successfully.
+var ErrDryRun = errors.New("dry run")
+
 var _ http.RoundTripper = (*dryRunTransport)(nil)
 
+// dryRunTransport sends all requests reading resources, so the payload of the command can be resolved. Any other request
+// is printed as json to stdout and aborted with ErrDryRun, so the command neither modifies nor waits for anything.
 type dryRunTransport struct {
 	delegate http.RoundTripper
 }
 
+type dryRunRequest struct {
+	Method  string          `json:"method"`
+	URL     string          `json:"url"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+var dryRunMutex sync.Mutex
+
 func (d dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	if req.Method == http.MethodGet {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
 		return d.base().RoundTrip(req)
 	}
 
-	Stderr.Color(console.Bright + console.Black)
-	_ = req.Write(Stderr)
-	Stderr.Reset()
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	request := dryRunRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+	}
+
+	if body = bytes.TrimSpace(redactBody(body)); json.Valid(body) {
+		request.Payload = body
+	}
+
+	data, err := json.MarshalIndent(request, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode dry run request: %w", err)
+	}
+
+	// bulk commands send their requests in parallel
+	dryRunMutex.Lock()
+	defer dryRunMutex.Unlock()
+
+	if _, err = fmt.Fprintf(Stdout, "%s\n", data); err != nil {
+		return nil, err
+	}
 
-	return &http.Response{
-		Status:     "200 OK",
-		StatusCode: http.StatusOK,
-		Body:       http.NoBody,
-	}, nil
+	return nil, ErrDryRun
 }
 
 func (d dryRunTransport) base() http.RoundTripper {
@@ -65,8 +109,11 @@ func (l logRequestTransport) base() http.RoundTripper {
 
 var _ http.RoundTripper = (*dumpRequestTransport)(nil)
 
+// dumpRequestTransport prints all requests and responses to stderr. Credentials and secrets are redacted, so the output
+// is safe to be shared. Bodies are omitted according to bodyLimit, see recordBody.
 type dumpRequestTransport struct {
-	delegate http.RoundTripper
+	delegate  http.RoundTripper
+	bodyLimit int64
 }
 
 func (d dumpRequestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -74,12 +121,35 @@ func (d dumpRequestTransport) RoundTrip(req *http.Request) (*http.Response, erro
 	defer Stderr.Reset()
 
 	// dump request
-	data, err := httputil.DumpRequestOut(req, true)
+	body, err := recordRequestBody(req, d.bodyLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	dump := req.Clone(req.Context())
+	dump.Header = redactHeader(req.Header)
+	switch {
+	case body.omitted:
+		// the body is not read, the content length is restored in the dump below
+		dump.Body, dump.GetBody, dump.ContentLength = nil, nil, 0
+	case body.data != nil:
+		setRequestBody(dump, redactBody(body.data))
+	}
+
+	data, err := httputil.DumpRequestOut(dump, true)
 	if err != nil {
 		return nil, err
 	}
+
+	if body.omitted && body.size > 0 {
+		data = bytes.Replace(data, []byte("Content-Length: 0\r\n"), []byte(fmt.Sprintf("Content-Length: %d\r\n", body.size)), 1)
+	}
 	Stderr.Print(string(data))
 
+	if body.omitted {
+		Stderr.Println(body.text())
+	}
+
 	// make request
 	res, err := d.base().RoundTrip(req)
 	if err != nil {
@@ -87,12 +157,32 @@ func (d dumpRequestTransport) RoundTrip(req *http.Request) (*http.Response, erro
 	}
 
 	// dump response
-	data, err = httputil.DumpResponse(res, true)
+	body, err = recordResponseBody(res, d.bodyLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	dumpRes := *res
+	dumpRes.Header = redactHeader(res.Header)
+
+	if body.omitted {
+		dumpRes.Body = http.NoBody
+		data, err = httputil.DumpResponse(&dumpRes, false)
+	} else {
+		dumpRes.Body = io.NopCloser(bytes.NewReader(redactBody(body.data)))
+		dumpRes.ContentLength = -1
+		data, err = httputil.DumpResponse(&dumpRes, true)
+	}
+
 	if err != nil {
 		return nil, err
 	}
 	Stderr.Print(string(data))
 
+	if body.omitted {
+		Stderr.Println(body.text())
+	}
+
 	Stderr.Println()
 	Stderr.Println()
 
@@ -106,3 +196,289 @@ func (d dumpRequestTransport) base() http.RoundTripper {
 
 	return d.delegate
 }
+
+const (
+	retryInitialBackoff = 500 * time.Millisecond
+	retryMaxBackoff     = 30 * time.Second
+	retryMaxRetryAfter  = 5 * time.Minute
+)
+
+var (
+	jitterMu sync.Mutex
+	jitter   = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+var _ http.RoundTripper = (*retryTransport)(nil)
+
+// retryTransport retries requests which failed temporarily. Requests with an idempotent method are retried on network
+// errors and on 502, 503 and 504 responses. Requests with any method are retried on 429 responses, since the api did
+// not process them at all. Retrying stops as soon as the context of the request is done, e.g. because of --timeout.
+type retryTransport struct {
+	delegate http.RoundTripper
+	retries  int
+}
+
+func (r retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		attemptReq, err := attemptRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := r.base().RoundTrip(attemptReq)
+
+		if !r.retryable(req, res, err) {
+			return res, err
+		}
+
+		if attempt > r.retries {
+			if attempt == 1 {
+				return res, err
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+			}
+
+			_ = res.Body.Close()
+			return nil, fmt.Errorf("giving up after %d attempts: server responded with %s", attempt, res.Status)
+		}
+
+		wait := retryBackoff(attempt)
+		if res != nil {
+			if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+				wait = retryAfter
+			}
+
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, fmt.Errorf("giving up after %d attempts: %w", attempt, req.Context().Err())
+		}
+	}
+}
+
+func (r retryTransport) retryable(req *http.Request, res *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		return isIdempotent(req.Method)
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(req.Method)
+	}
+
+	return false
+}
+
+func (r retryTransport) base() http.RoundTripper {
+	if r.delegate == nil {
+		return http.DefaultTransport
+	}
+
+	return r.delegate
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// retryBackoff returns the exponential backoff before the next attempt with a random jitter of up to half of it.
+func retryBackoff(attempt int) time.Duration {
+	backoff := retryInitialBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > retryMaxBackoff {
+		backoff = retryMaxBackoff
+	}
+
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+
+	return backoff/2 + time.Duration(jitter.Int63n(int64(backoff/2)+1))
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number of seconds or a http date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = time.Until(date)
+	} else {
+		return 0, false
+	}
+
+	if wait < 0 {
+		wait = 0
+	}
+
+	if wait > retryMaxRetryAfter {
+		wait = retryMaxRetryAfter
+	}
+
+	return wait, true
+}
+
+// attemptRequest returns a copy of the request for every attempt, since inner transports modify the request, e.g. to
+// add the authorization header. The body is rewound for every retry.
+func attemptRequest(req *http.Request, attempt int) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if attempt == 1 || req.GetBody == nil {
+		return clone, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("rewind request body: %w", err)
+	}
+
+	clone.Body = body
+	return clone, nil
+}
+
+// readRequestBody returns a copy of the request body without consuming it.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody == nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+
+		setRequestBody(req, body)
+		return body, nil
+	}
+
+	reader, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func setRequestBody(req *http.Request, body []byte) {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.ContentLength = int64(len(body))
+}
+
+// maxRecordedBodySize limits the bodies of the requests and responses recorded by the dump and trace of clients
+// transferring files, like the S3 client, which would otherwise keep whole files in memory.
+const maxRecordedBodySize = 64 << 10
+
+// recordedBody is the body of a request or response as recorded by the dump and trace.
+type recordedBody struct {
+	data     []byte
+	size     int64
+	mimeType string
+	omitted  bool
+}
+
+// text returns the redacted body or, if it has been omitted, a note about its size.
+func (r recordedBody) text() string {
+	if r.omitted {
+		size := "unknown size"
+		if r.size >= 0 {
+			size = fmt.Sprintf("%d bytes", r.size)
+		}
+
+		return fmt.Sprintf("[body of %s omitted (%s)]", size, r.mimeType)
+	}
+
+	return string(redactBody(r.data))
+}
+
+// recordBody reports whether a body with the headers and length is recorded. Without limit all bodies are recorded,
+// otherwise only json, xml and text bodies of at most limit bytes, so binary and large bodies are streamed as usual.
+func recordBody(header http.Header, length int64, limit int64) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "unknown type"
+	}
+
+	if limit <= 0 || length == 0 {
+		return mediaType, true
+	}
+
+	if length < 0 || length > limit {
+		return mediaType, false
+	}
+
+	textual := strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "json") || strings.HasSuffix(mediaType, "xml")
+	return mediaType, textual
+}
+
+// recordRequestBody returns the body of the request without consuming it, unless it is omitted according to limit.
+func recordRequestBody(req *http.Request, limit int64) (recordedBody, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return recordedBody{}, nil
+	}
+
+	mimeType, ok := recordBody(req.Header, req.ContentLength, limit)
+	if !ok {
+		return recordedBody{size: req.ContentLength, mimeType: mimeType, omitted: true}, nil
+	}
+
+	data, err := readRequestBody(req)
+	return recordedBody{data: data, size: int64(len(data)), mimeType: mimeType}, err
+}
+
+// recordResponseBody returns the body of the response and replaces it with a copy, unless it is omitted according to
+// limit.
+func recordResponseBody(res *http.Response, limit int64) (recordedBody, error) {
+	// e.g. the responses to head requests
+	if res.Body == http.NoBody {
+		return recordedBody{}, nil
+	}
+
+	mimeType, ok := recordBody(res.Header, res.ContentLength, limit)
+	if !ok {
+		return recordedBody{size: res.ContentLength, mimeType: mimeType, omitted: true}, nil
+	}
+
+	data, err := readResponseBody(res)
+	return recordedBody{data: data, size: int64(len(data)), mimeType: mimeType}, err
+}
+
+// readResponseBody reads the whole response body and replaces it with a copy, so it can still be read by the caller.
+func readResponseBody(res *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
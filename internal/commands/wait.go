@@ -0,0 +1,206 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/flowswiss/goclient"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cloudbit-ch/cli/v2/pkg/console"
+)
+
+const (
+	waitInitialInterval = 2 * time.Second
+	waitMaxInterval     = 30 * time.Second
+
+	// DefaultWaitTimeout limits waiting if the global --timeout is not set, so a resource never reaching the condition
+	// does not block scripts forever.
+	DefaultWaitTimeout = 30 * time.Minute
+)
+
+// ErrNotFound is returned by the fetch function of WaitUntil if the resource does not exist (anymore).
+var ErrNotFound = errors.New("resource not found")
+
+// WaitOptions holds the flags of the wait commands. The time to wait is limited by WaitTimeout.
+type WaitOptions struct {
+	For string
+}
+
+// Deletion reports whether the options wait for the deletion of the resource.
+func (w WaitOptions) Deletion() bool {
+	return strings.EqualFold(strings.TrimSpace(w.For), "delete")
+}
+
+// AddWaitFlags registers the --for flag of a wait command.
+func AddWaitFlags(cmd *cobra.Command, opts *WaitOptions) {
+	cmd.Flags().StringVar(&opts.For, "for", "", "condition to wait for, either COLUMN=VALUE (e.g. status=running) or delete")
+
+	_ = cmd.MarkFlagRequired("for")
+	_ = cmd.RegisterFlagCompletionFunc("for", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"status=", "delete"}, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+type waitCondition struct {
+	column string
+	value  string
+	delete bool
+}
+
+func parseWaitCondition(expr string) (waitCondition, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.EqualFold(expr, "delete") {
+		return waitCondition{delete: true}, nil
+	}
+
+	column, value, ok := strings.Cut(expr, "=")
+	if !ok || strings.TrimSpace(column) == "" || strings.TrimSpace(value) == "" {
+		return waitCondition{}, fmt.Errorf("invalid condition %q: expected COLUMN=VALUE or delete", expr)
+	}
+
+	return waitCondition{
+		column: strings.ToLower(strings.TrimSpace(column)),
+		value:  strings.TrimSpace(value),
+	}, nil
+}
+
+func (w waitCondition) String() string {
+	if w.delete {
+		return "be deleted"
+	}
+
+	return fmt.Sprintf("reach %s=%s", w.column, w.value)
+}
+
+// WaitUntil polls the resource using fetch until it matches the condition of the options, backing off between the
+// requests. The condition is evaluated on the values of the displayable, the same way --where does. The fetch function
+// has to return ErrNotFound, if the resource does not exist.
+func WaitUntil[T console.Displayable](ctx context.Context, kind string, name string, opts WaitOptions, fetch func(ctx context.Context) (T, error)) (T, error) {
+	var empty, result T
+
+	cond, err := parseWaitCondition(opts.For)
+	if err != nil {
+		return empty, err
+	}
+
+	progress := console.NewProgress(fmt.Sprintf("Waiting for %s %s to %s", kind, name, cond))
+	defer progress.Done()
+
+	go progress.Display(Stderr)
+
+	err = Poll(ctx, func(ctx context.Context) (bool, error) {
+		item, err := fetch(ctx)
+		switch {
+		case errors.Is(err, ErrNotFound) || IsNotFound(err):
+			if cond.delete {
+				return true, nil
+			}
+
+			return false, fmt.Errorf("%s %s does not exist", kind, name)
+		case err != nil && ctx.Err() != nil:
+			return false, nil
+		case err != nil:
+			return false, fmt.Errorf("fetch %s: %w", kind, err)
+		case cond.delete:
+			return false, nil
+		}
+
+		values := renderValues(item)
+
+		current, ok := values[cond.column]
+		if !ok {
+			return false, fmt.Errorf("unknown column %q, available columns: %s", cond.column, strings.Join(item.Columns(), ", "))
+		}
+
+		if strings.EqualFold(current, cond.value) {
+			result = item
+			return true, nil
+		}
+
+		progress.SetMessage(fmt.Sprintf("Waiting for %s %s to %s (current %s: %s)", kind, name, cond, cond.column, current))
+		return false, nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return empty, fmt.Errorf("timed out after %s waiting for %s %s to %s", WaitTimeout(), kind, name, cond)
+	}
+
+	return result, err
+}
+
+// WaitTimeout returns the maximum time to wait, which is the global --timeout or DefaultWaitTimeout if it is not set.
+func WaitTimeout() time.Duration {
+	if timeout := viper.GetDuration(FlagTimeout); timeout > 0 {
+		return timeout
+	}
+
+	return DefaultWaitTimeout
+}
+
+// Poll calls check until it reports to be done or returns an error. The interval between the calls starts at two
+// seconds and grows up to 30 seconds. If the context is done before or WaitTimeout has passed, the error of the
+// context is returned.
+func Poll(ctx context.Context, check func(ctx context.Context) (bool, error)) error {
+	// the global --timeout is already applied to the context of the command
+	if viper.GetDuration(FlagTimeout) <= 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultWaitTimeout)
+		defer cancel()
+	}
+
+	interval := waitInitialInterval
+	for {
+		done, err := check(ctx)
+		if err != nil || done {
+			return err
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		interval = interval * 3 / 2
+		if interval > waitMaxInterval {
+			interval = waitMaxInterval
+		}
+	}
+}
+
+// FindByID returns the item of the list with the given id or ErrNotFound.
+func FindByID[T any](items []T, id int, idOf func(T) int) (T, error) {
+	for _, item := range items {
+		if idOf(item) == id {
+			return item, nil
+		}
+	}
+
+	var empty T
+	return empty, ErrNotFound
+}
+
+// IsNotFound reports whether the error is an api error with status not found.
+func IsNotFound(err error) bool {
+	var apiError goclient.APIError
+	if !errors.As(err, &apiError) || apiError.Response() == nil {
+		return false
+	}
+
+	return apiError.Response().StatusCode == http.StatusNotFound
+}
+
+// WaitFor displays the action as progress while polling check until it reports to be done.
+func WaitFor(ctx context.Context, action string, check func(ctx context.Context) (bool, error)) error {
+	progress := console.NewProgress(action)
+	defer progress.Done()
+
+	go progress.Display(Stderr)
+
+	return Poll(ctx, check)
+}
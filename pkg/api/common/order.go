@@ -2,17 +2,143 @@ package common
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/flowswiss/goclient"
 	"github.com/flowswiss/goclient/common"
+
+	"github.com/cloudbit-ch/cli/v2/pkg/console"
+	"github.com/cloudbit-ch/cli/v2/pkg/filter"
 )
 
 var ErrOrderFailed = common.ErrOrderFailed
 
+const (
+	OrderStatusCreated    = common.OrderStatusCreated
+	OrderStatusProcessing = common.OrderStatusProcessing
+	OrderStatusSucceeded  = common.OrderStatusSucceeded
+	OrderStatusFailed     = common.OrderStatusFailed
+)
+
+var (
+	_ filter.Filterable   = (*Order)(nil)
+	_ console.Displayable = (*Order)(nil)
+	_ console.Displayable = (*OrderReference)(nil)
+)
+
 type Ordering = common.Ordering
 
-type Order = common.Order
+type Order common.Order
+
+func (o Order) String() string {
+	return fmt.Sprint(o.ID)
+}
+
+func (o Order) Keys() []string {
+	return []string{fmt.Sprint(o.ID), o.Status.Name}
+}
+
+func (o Order) Columns() []string {
+	return []string{"id", "status", "product instance", "created at"}
+}
+
+func (o Order) Values() map[string]interface{} {
+	instance := ""
+	if o.Product.ID != 0 {
+		instance = fmt.Sprint(o.Product.ID)
+	}
+
+	return map[string]interface{}{
+		"id":               o.ID,
+		"status":           o.Status.Name,
+		"product instance": instance,
+		"created at":       o.CreatedAt,
+	}
+}
+
+// Processed reports whether the order has either succeeded or failed.
+func (o Order) Processed() bool {
+	return o.Status.ID == OrderStatusSucceeded || o.Status.ID == OrderStatusFailed
+}
+
+// OrderReference is the reference to an order returned by asynchronous requests, e.g. when creating a server.
+type OrderReference struct {
+	ID  int    `json:"id"`
+	Ref string `json:"ref"`
+}
+
+func NewOrderReference(ordering Ordering) (OrderReference, error) {
+	id, err := ordering.ExtractIdentifier()
+	if err != nil {
+		return OrderReference{}, err
+	}
+
+	return OrderReference{ID: id, Ref: ordering.Ref}, nil
+}
+
+func (o OrderReference) String() string {
+	return fmt.Sprint(o.ID)
+}
+
+func (o OrderReference) Columns() []string {
+	return []string{"id", "ref"}
+}
+
+func (o OrderReference) Values() map[string]interface{} {
+	return map[string]interface{}{
+		"id":  o.ID,
+		"ref": o.Ref,
+	}
+}
+
+var orderIdentifierRegex = regexp.MustCompile(`(?:^|/orders/)(\d+)/?$`)
+
+// ParseOrderIdentifier extracts the order id from either the plain id or an order reference like /v4/orders/123.
+func ParseOrderIdentifier(term string) (int, error) {
+	match := orderIdentifierRegex.FindStringSubmatch(strings.TrimSpace(term))
+	if match == nil {
+		return 0, fmt.Errorf("invalid order %q: expected an order id or reference", term)
+	}
+
+	return strconv.Atoi(match[1])
+}
+
+type OrderService struct {
+	client   goclient.Client
+	delegate common.OrderService
+}
+
+func NewOrderService(client goclient.Client) OrderService {
+	return OrderService{
+		client:   client,
+		delegate: common.NewOrderService(client),
+	}
+}
+
+// List fetches the orders of the organization. The order service of goclient does not support listing orders yet.
+func (o OrderService) List(ctx context.Context) ([]Order, error) {
+	var res []common.Order
+	if _, err := o.client.List(ctx, "/v4/orders", goclient.Cursor{NoFilter: 1}, &res); err != nil {
+		return nil, err
+	}
+
+	items := make([]Order, len(res))
+	for idx, item := range res {
+		items[idx] = Order(item)
+	}
+
+	return items, nil
+}
+
+func (o OrderService) Get(ctx context.Context, id int) (Order, error) {
+	order, err := o.delegate.Get(ctx, id)
+	return Order(order), err
+}
 
 func WaitForOrder(ctx context.Context, client goclient.Client, ordering Ordering) (Order, error) {
-	return common.NewOrderService(client).WaitUntilProcessed(ctx, ordering)
+	order, err := common.NewOrderService(client).WaitUntilProcessed(ctx, ordering)
+	return Order(order), err
 }
@@ -0,0 +1,24 @@
+package common
+
+import (
+	"context"
+
+	"github.com/flowswiss/goclient"
+)
+
+// Organization is the organization an application token belongs to. It is not part of goclient yet.
+type Organization struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (o Organization) String() string {
+	return o.Name
+}
+
+// CurrentOrganization fetches the organization of the token used by the client.
+func CurrentOrganization(ctx context.Context, client goclient.Client) (Organization, error) {
+	organization := Organization{}
+	err := client.Get(ctx, "/v4/organization", &organization)
+	return organization, err
+}
@@ -29,6 +29,35 @@ func (i Image) IsWindows() bool {
 	return i.Category == ImageCategoryWindows
 }
 
+// DefaultUser returns the user to log in with on servers running the image. The username provided by the api takes
+// precedence, otherwise it is derived from the operating system using the conventions of the cloud images.
+func (i Image) DefaultUser() string {
+	if i.Username != "" {
+		return i.Username
+	}
+
+	if i.IsWindows() {
+		return "Administrator"
+	}
+
+	system := strings.ToLower(i.OperatingSystem)
+	for _, candidate := range []struct{ name, user string }{
+		{"ubuntu", "ubuntu"},
+		{"debian", "debian"},
+		{"centos", "centos"},
+		{"rocky", "rocky"},
+		{"alma", "almalinux"},
+		{"fedora", "fedora"},
+		{"freebsd", "freebsd"},
+	} {
+		if strings.Contains(system, candidate.name) {
+			return candidate.user
+		}
+	}
+
+	return "root"
+}
+
 func (i Image) AvailableAt(location common.Location) bool {
 	for _, available := range i.AvailableLocations {
 		if available == location.ID {
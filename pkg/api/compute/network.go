@@ -3,6 +3,7 @@ package compute
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/flowswiss/goclient"
 	"github.com/flowswiss/goclient/compute"
@@ -20,6 +21,10 @@ func (n Network) Columns() []string {
 	return []string{"id", "name", "location", "cidr", "usage"}
 }
 
+func (n Network) WideColumns() []string {
+	return []string{"gateway ip", "allocation pool", "domain name servers", "description"}
+}
+
 func (n Network) Values() map[string]interface{} {
 	return map[string]interface{}{
 		"id":       n.ID,
@@ -27,6 +32,11 @@ func (n Network) Values() map[string]interface{} {
 		"location": common.Location(n.Location),
 		"cidr":     n.CIDR,
 		"usage":    fmt.Sprintf("%d/%d", n.UsedIPs, n.TotalIPs),
+
+		"gateway ip":          n.GatewayIP,
+		"allocation pool":     fmt.Sprintf("%s - %s", n.AllocationPoolStart, n.AllocationPoolEnd),
+		"domain name servers": strings.Join(n.DomainNameServers, ", "),
+		"description":         n.Description,
 	}
 }
 
@@ -9,6 +9,13 @@ import (
 	"github.com/flowswiss/goclient/compute"
 )
 
+const (
+	ProtocolAny  = compute.ProtocolAny
+	ProtocolICMP = compute.ProtocolICMP
+	ProtocolTCP  = compute.ProtocolTCP
+	ProtocolUDP  = compute.ProtocolUDP
+)
+
 var IPRangeAny = net.IPNet{
 	IP:   net.IPv4zero,
 	Mask: net.IPv4Mask(0, 0, 0, 0),
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"unicode"
 
 	"github.com/flowswiss/goclient"
 	"github.com/flowswiss/goclient/compute"
@@ -33,9 +34,14 @@ func (s Server) Columns() []string {
 	return []string{"id", "name", "status", "product", "operating system", "location", "public ip", "network"}
 }
 
+func (s Server) WideColumns() []string {
+	return []string{"key pair", "private ip"}
+}
+
 func (s Server) Values() map[string]interface{} {
 	networkBuffer := &strings.Builder{}
 	publicIPBuffer := &strings.Builder{}
+	var privateIPs []string
 
 	for i, network := range s.Networks {
 		if i != 0 {
@@ -49,6 +55,7 @@ func (s Server) Values() map[string]interface{} {
 			}
 
 			networkBuffer.WriteString(iface.PrivateIP)
+			privateIPs = append(privateIPs, iface.PrivateIP)
 
 			if iface.PublicIP != "" {
 				publicIPBuffer.WriteString(fmt.Sprintf("%s, ", iface.PublicIP))
@@ -71,7 +78,35 @@ func (s Server) Values() map[string]interface{} {
 		"location":         common.Location(s.Location),
 		"public ip":        publicIP,
 		"network":          networkBuffer.String(),
+		"key pair":         s.KeyPair.Name,
+		"private ip":       strings.Join(privateIPs, ", "),
+	}
+}
+
+// PublicIP returns the first public ip attached to a network interface of the server.
+func (s Server) PublicIP() string {
+	for _, network := range s.Networks {
+		for _, iface := range network.Interfaces {
+			if iface.PublicIP != "" {
+				return iface.PublicIP
+			}
+		}
+	}
+
+	return ""
+}
+
+// PrivateIP returns the private ip of the first network interface of the server.
+func (s Server) PrivateIP() string {
+	for _, network := range s.Networks {
+		for _, iface := range network.Interfaces {
+			if iface.PrivateIP != "" {
+				return iface.PrivateIP
+			}
+		}
 	}
+
+	return ""
 }
 
 type ServerService struct {
@@ -131,3 +166,61 @@ func (s ServerService) Upgrade(ctx context.Context, id int, data ServerUpgrade)
 func (s ServerService) Delete(ctx context.Context, id int, deleteElasticIPs bool) error {
 	return s.delegate.Delete(ctx, id, deleteElasticIPs)
 }
+
+const specialChars = "~!@#$%^&*_-+=`|\\(){}[]:;\"'<>,.?/"
+
+// CheckWindowsPassword checks the password of the windows admin user against the complexity requirements of windows,
+// which are enforced when the server is created.
+func CheckWindowsPassword(password string) error {
+	// https://docs.microsoft.com/en-us/windows/security/threat-protection/security-policy-settings/password-must-meet-complexity-requirements
+
+	// 1. Passwords may not contain the user's samAccountName (Account Name) value or entire displayName (Full
+	//	  Name value). Both checks aren't case-sensitive.
+	if strings.Contains(strings.ToLower(password), "administrator") {
+		return fmt.Errorf("windows user password cannot contain the username")
+	}
+
+	// 2. The password contains characters from three of the following categories:
+	//    	- Uppercase letters of European languages (A through Z, with diacritic marks, Greek and Cyrillic
+	//   		characters)
+	//   	- Lowercase letters of European languages (a through z, with diacritic marks, Greek and Cyrillic
+	//   		characters)
+	//   	- Base 10 digits (0 through 9)
+	//		- Non-alphanumeric characters (special characters): (~!@#$%^&*_-+=`|\(){}[]:;"'<>,.?/) Currency symbols such
+	//			as the Euro or British Pound aren't counted as special characters for this policy setting.
+	// 		- Any Unicode character that's categorized as an alphabetic character but isn't uppercase or lowercase. This
+	//			group includes Unicode characters from Asian languages. (NOTE: not implemented)
+	hasUppercase := false
+	hasLowercase := false
+	hasDigit := false
+	hasSpecial := false
+	count := 0
+
+	for _, char := range password {
+		if unicode.IsUpper(char) && !hasUppercase {
+			hasUppercase = true
+			count++
+		}
+
+		if unicode.IsLower(char) && !hasLowercase {
+			hasLowercase = true
+			count++
+		}
+
+		if char >= '0' && char <= '9' && !hasDigit {
+			hasDigit = true
+			count++
+		}
+
+		if strings.ContainsRune(specialChars, char) && !hasSpecial {
+			hasSpecial = true
+			count++
+		}
+	}
+
+	if count < 3 {
+		return fmt.Errorf("windows user password must contain at least 3 of the following categories: uppercase letters, lowercase letters, digits, and non-alphanumeric characters")
+	}
+
+	return nil
+}
@@ -10,6 +10,13 @@ import (
 	"github.com/cloudbit-ch/cli/v2/pkg/api/common"
 )
 
+const (
+	VolumeStatusAvailable = compute.VolumeStatusAvailable
+	VolumeStatusInUse     = compute.VolumeStatusInUse
+	VolumeStatusWorking   = compute.VolumeStatusWorking
+	VolumeStatusError     = compute.VolumeStatusError
+)
+
 type Volume compute.Volume
 
 func (v Volume) String() string {
@@ -24,6 +31,10 @@ func (v Volume) Columns() []string {
 	return []string{"id", "name", "location", "status", "size", "attached to"}
 }
 
+func (v Volume) WideColumns() []string {
+	return []string{"product", "serial", "bootable", "snapshots", "created at"}
+}
+
 func (v Volume) Values() map[string]interface{} {
 	return map[string]interface{}{
 		"id":          v.ID,
@@ -32,6 +43,11 @@ func (v Volume) Values() map[string]interface{} {
 		"status":      v.Status.Name,
 		"size":        fmt.Sprint(v.Size, " GiB"),
 		"attached to": Server(v.AttachedTo),
+		"product":     common.Product(v.Product),
+		"serial":      v.SerialNumber,
+		"bootable":    v.Bootable,
+		"snapshots":   v.Snapshots,
+		"created at":  v.CreatedAt,
 	}
 }
 
@@ -59,6 +75,11 @@ func (v VolumeService) List(ctx context.Context) ([]Volume, error) {
 	return items, nil
 }
 
+func (v VolumeService) Get(ctx context.Context, id int) (Volume, error) {
+	volume, err := v.delegate.Get(ctx, id)
+	return Volume(volume), err
+}
+
 type VolumeCreate = compute.VolumeCreate
 
 func (v VolumeService) Create(ctx context.Context, data VolumeCreate) (Volume, error) {
@@ -25,6 +25,10 @@ func (c Cluster) Columns() []string {
 	return []string{"id", "name", "status", "product", "location", "network", "address", "control plane", "worker"}
 }
 
+func (c Cluster) WideColumns() []string {
+	return []string{"version", "security group", "locked", "kube config expires at"}
+}
+
 func (c Cluster) Values() map[string]interface{} {
 	return map[string]interface{}{
 		"id":            c.ID,
@@ -36,6 +40,11 @@ func (c Cluster) Values() map[string]interface{} {
 		"address":       fmt.Sprintf("%s (%s)", c.DNSName, c.PublicAddress),
 		"control plane": fmt.Sprintf("%d/%d (%s)", c.NodeCount.Current.ControlPlane, c.NodeCount.Expected.ControlPlane, c.ExpectedPreset.ControlPlane.Name),
 		"worker":        fmt.Sprintf("%d/%d (%s)", c.NodeCount.Current.Worker, c.NodeCount.Expected.Worker, c.ExpectedPreset.Worker.Name),
+
+		"version":                c.Version.Name,
+		"security group":         c.SecurityGroup.Name,
+		"locked":                 c.Locked,
+		"kube config expires at": c.KubeConfig.ExpiresAt,
 	}
 }
 
@@ -25,6 +25,10 @@ func (d Device) Columns() []string {
 	return []string{"id", "name", "location", "product", "operating system", "public ip", "network", "hostname", "status"}
 }
 
+func (d Device) WideColumns() []string {
+	return []string{"price", "metal control"}
+}
+
 func (d Device) Values() map[string]interface{} {
 	networkBuffer := &bytes.Buffer{}
 	publicIPBuffer := &bytes.Buffer{}
@@ -58,6 +62,8 @@ func (d Device) Values() map[string]interface{} {
 		"network":          networkBuffer.String(),
 		"hostname":         d.Hostname,
 		"status":           d.Status.Name,
+		"price":            d.Price,
+		"metal control":    d.MetalControl,
 	}
 }
 
@@ -10,6 +10,10 @@ import (
 
 type Router macbaremetal.Router
 
+func (r Router) String() string {
+	return r.Name
+}
+
 func (r Router) Keys() []string {
 	return []string{fmt.Sprint(r.ID), r.Name, r.PublicIP}
 }
@@ -0,0 +1,95 @@
+package objectstorage
+
+import (
+	"context"
+
+	"github.com/cloudbit-ch/cli/v2/pkg/s3"
+)
+
+// timeFormat is used to display the timestamps of buckets and objects.
+const timeFormat = "2006-01-02 15:04:05"
+
+type Bucket s3.Bucket
+
+func (b Bucket) String() string {
+	return b.Name
+}
+
+func (b Bucket) Keys() []string {
+	return []string{b.Name}
+}
+
+func (b Bucket) Columns() []string {
+	return []string{"name", "created at"}
+}
+
+func (b Bucket) Values() map[string]interface{} {
+	return map[string]interface{}{
+		"name":       b.Name,
+		"created at": b.CreationDate.Local().Format(timeFormat),
+	}
+}
+
+type BucketService struct {
+	client *s3.Client
+}
+
+func NewBucketService(client *s3.Client) BucketService {
+	return BucketService{
+		client: client,
+	}
+}
+
+func (b BucketService) List(ctx context.Context) ([]Bucket, error) {
+	res, err := b.client.ListBuckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Bucket, len(res))
+	for idx, item := range res {
+		items[idx] = Bucket(item)
+	}
+
+	return items, nil
+}
+
+func (b BucketService) Create(ctx context.Context, name string) error {
+	return b.client.CreateBucket(ctx, name)
+}
+
+func (b BucketService) Delete(ctx context.Context, name string) error {
+	return b.client.DeleteBucket(ctx, name)
+}
+
+func (b BucketService) GetLifecycle(ctx context.Context, name string) (*s3.LifecycleConfiguration, error) {
+	return b.client.GetBucketLifecycle(ctx, name)
+}
+
+func (b BucketService) SetLifecycle(ctx context.Context, name string, config s3.LifecycleConfiguration) error {
+	return b.client.PutBucketLifecycle(ctx, name, config)
+}
+
+func (b BucketService) GetVersioning(ctx context.Context, name string) (s3.VersioningConfiguration, error) {
+	return b.client.GetBucketVersioning(ctx, name)
+}
+
+func (b BucketService) SetVersioning(ctx context.Context, name string, config s3.VersioningConfiguration) error {
+	return b.client.PutBucketVersioning(ctx, name, config)
+}
+
+func (b BucketService) GetPolicy(ctx context.Context, name string) ([]byte, error) {
+	return b.client.GetBucketPolicy(ctx, name)
+}
+
+func (b BucketService) SetPolicy(ctx context.Context, name string, policy []byte) error {
+	return b.client.PutBucketPolicy(ctx, name, policy)
+}
+
+func (b BucketService) GetCORS(ctx context.Context, name string) (*s3.CORSConfiguration, error) {
+	return b.client.GetBucketCORS(ctx, name)
+}
+
+func (b BucketService) SetCORS(ctx context.Context, name string, config s3.CORSConfiguration) error {
+	return b.client.PutBucketCORS(ctx, name, config)
+}
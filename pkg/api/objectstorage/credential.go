@@ -36,6 +36,15 @@ func (c Credential) Values() map[string]interface{} {
 	}
 }
 
+// Masked returns the credential with the secret key replaced by asterisks, so it can be shown without revealing it.
+func (c Credential) Masked() Credential {
+	if c.SecretKey != "" {
+		c.SecretKey = "********"
+	}
+
+	return c
+}
+
 type CredentialService struct {
 	delegate objectstorage.CredentialService
 }
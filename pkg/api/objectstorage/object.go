@@ -0,0 +1,112 @@
+package objectstorage
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/cloudbit-ch/cli/v2/pkg/console"
+	"github.com/cloudbit-ch/cli/v2/pkg/s3"
+)
+
+// Object is an object of a bucket or, if the key ends with the delimiter, a common prefix of multiple objects.
+type Object s3.Object
+
+func (o Object) String() string {
+	return o.Key
+}
+
+func (o Object) Keys() []string {
+	return []string{o.Key, o.ETag}
+}
+
+func (o Object) Columns() []string {
+	return []string{"key", "size", "last modified"}
+}
+
+func (o Object) WideColumns() []string {
+	return []string{"etag", "storage class"}
+}
+
+func (o Object) Values() map[string]interface{} {
+	values := map[string]interface{}{
+		"key":           o.Key,
+		"size":          "",
+		"last modified": "",
+		"etag":          o.ETag,
+		"storage class": o.StorageClass,
+	}
+
+	if !o.IsDirectory() {
+		values["size"] = console.FormatBytes(o.Size)
+		values["last modified"] = o.LastModified.Local().Format(timeFormat)
+	}
+
+	return values
+}
+
+// IsDirectory reports whether the object is a common prefix of other objects.
+func (o Object) IsDirectory() bool {
+	return strings.HasSuffix(o.Key, "/") && o.LastModified.IsZero()
+}
+
+type ObjectService struct {
+	client *s3.Client
+	bucket string
+}
+
+func NewObjectService(client *s3.Client, bucket string) ObjectService {
+	return ObjectService{
+		client: client,
+		bucket: bucket,
+	}
+}
+
+// List returns the objects with the prefix. Unless recursive is set, objects in sub directories of the prefix are
+// returned as a single object named after the directory, e.g. "photos/".
+func (o ObjectService) List(ctx context.Context, prefix string, recursive bool) ([]Object, error) {
+	opts := s3.ListOptions{Prefix: prefix}
+	if !recursive {
+		opts.Delimiter = "/"
+	}
+
+	objects, prefixes, err := o.client.ListAllObjects(ctx, o.bucket, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Object, 0, len(prefixes)+len(objects))
+	for _, prefix := range prefixes {
+		items = append(items, Object{Key: prefix})
+	}
+
+	for _, object := range objects {
+		items = append(items, Object(object))
+	}
+
+	return items, nil
+}
+
+func (o ObjectService) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	body, object, err := o.client.GetObject(ctx, o.bucket, key)
+	return body, Object(object), err
+}
+
+func (o ObjectService) Head(ctx context.Context, key string) (Object, error) {
+	object, err := o.client.HeadObject(ctx, o.bucket, key)
+	return Object(object), err
+}
+
+// Put uploads the object and returns its ETag.
+func (o ObjectService) Put(ctx context.Context, key string, body *io.SectionReader, contentType string) (string, error) {
+	return o.client.PutObject(ctx, o.bucket, key, body, s3.PutOptions{ContentType: contentType})
+}
+
+// Copy copies an object of any bucket to the key within this bucket.
+func (o ObjectService) Copy(ctx context.Context, srcBucket string, srcKey string, key string) (string, error) {
+	return o.client.CopyObject(ctx, srcBucket, srcKey, o.bucket, key)
+}
+
+func (o ObjectService) Delete(ctx context.Context, key string) error {
+	return o.client.DeleteObject(ctx, o.bucket, key)
+}
@@ -0,0 +1,1001 @@
+package apitest
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/flowswiss/goclient/common"
+	"github.com/flowswiss/goclient/compute"
+)
+
+var (
+	serverActionStart   = compute.ServerAction{ID: 1, Name: "Start", Command: "start", Sorting: 1}
+	serverActionStop    = compute.ServerAction{ID: 2, Name: "Stop", Command: "stop", Sorting: 2}
+	serverActionRestart = compute.ServerAction{ID: 3, Name: "Restart", Command: "restart", Sorting: 3}
+)
+
+func serverStatus(id int) compute.ServerStatus {
+	switch id {
+	case compute.ServerStatusRunning:
+		return compute.ServerStatus{ID: id, Name: "Running", Key: "running", Actions: []compute.ServerAction{serverActionStop, serverActionRestart}}
+	case compute.ServerStatusStopped:
+		return compute.ServerStatus{ID: id, Name: "Stopped", Key: "stopped", Actions: []compute.ServerAction{serverActionStart}}
+	case compute.ServerStatusStarting:
+		return compute.ServerStatus{ID: id, Name: "Starting", Key: "starting", Actions: []compute.ServerAction{}}
+	case compute.ServerStatusStopping:
+		return compute.ServerStatus{ID: id, Name: "Stopping", Key: "stopping", Actions: []compute.ServerAction{}}
+	case compute.ServerStatusUpgrading:
+		return compute.ServerStatus{ID: id, Name: "Upgrading", Key: "upgrading", Actions: []compute.ServerAction{}}
+	}
+
+	return compute.ServerStatus{ID: compute.ServerStatusError, Name: "Error", Key: "error", Actions: []compute.ServerAction{}}
+}
+
+func volumeStatus(id int) compute.VolumeStatus {
+	switch id {
+	case compute.VolumeStatusAvailable:
+		return compute.VolumeStatus{ID: id, Name: "Available", Key: "available"}
+	case compute.VolumeStatusInUse:
+		return compute.VolumeStatus{ID: id, Name: "In Use", Key: "in-use"}
+	case compute.VolumeStatusWorking:
+		return compute.VolumeStatus{ID: id, Name: "Working", Key: "working"}
+	}
+
+	return compute.VolumeStatus{ID: compute.VolumeStatusError, Name: "Error", Key: "error"}
+}
+
+func (s *Server) registerComputeRoutes() {
+	s.handle(http.MethodGet, "/v4/compute/instances", s.listServers)
+	s.handle(http.MethodPost, "/v4/compute/instances", s.createServer)
+	s.handle(http.MethodGet, "/v4/compute/instances/*", s.getServer)
+	s.handle(http.MethodPatch, "/v4/compute/instances/*", s.updateServer)
+	s.handle(http.MethodDelete, "/v4/compute/instances/*", s.deleteServer)
+	s.handle(http.MethodPost, "/v4/compute/instances/*/action", s.performServerAction)
+	s.handle(http.MethodPost, "/v4/compute/instances/*/upgrade", s.upgradeServer)
+	s.handle(http.MethodPost, "/v4/compute/instances/*/elastic-ips", s.attachElasticIP)
+	s.handle(http.MethodDelete, "/v4/compute/instances/*/elastic-ips/*", s.detachElasticIP)
+
+	s.handle(http.MethodGet, "/v4/compute/networks", s.listNetworks)
+	s.handle(http.MethodPost, "/v4/compute/networks", s.createNetwork)
+	s.handle(http.MethodGet, "/v4/compute/networks/*", s.getNetwork)
+	s.handle(http.MethodPatch, "/v4/compute/networks/*", s.updateNetwork)
+	s.handle(http.MethodDelete, "/v4/compute/networks/*", s.deleteNetwork)
+
+	s.handle(http.MethodGet, "/v4/compute/volumes", s.listVolumes)
+	s.handle(http.MethodPost, "/v4/compute/volumes", s.createVolume)
+	s.handle(http.MethodGet, "/v4/compute/volumes/*", s.getVolume)
+	s.handle(http.MethodPatch, "/v4/compute/volumes/*", s.updateVolume)
+	s.handle(http.MethodDelete, "/v4/compute/volumes/*", s.deleteVolume)
+	s.handle(http.MethodPost, "/v4/compute/volumes/*/instances", s.attachVolume)
+	s.handle(http.MethodDelete, "/v4/compute/volumes/*/instances/*", s.detachVolume)
+	s.handle(http.MethodPost, "/v4/compute/volumes/*/upgrade", s.expandVolume)
+
+	s.handle(http.MethodGet, "/v4/compute/key-pairs", s.listKeyPairs)
+	s.handle(http.MethodPost, "/v4/compute/key-pairs", s.createKeyPair)
+	s.handle(http.MethodDelete, "/v4/compute/key-pairs/*", s.deleteKeyPair)
+
+	s.handle(http.MethodGet, "/v4/compute/elastic-ips", s.listElasticIPs)
+	s.handle(http.MethodPost, "/v4/compute/elastic-ips", s.createElasticIP)
+	s.handle(http.MethodDelete, "/v4/compute/elastic-ips/*", s.deleteElasticIP)
+
+	// security groups, routers and load balancers are not supported yet, but listed as empty, so commands reading all
+	// resources of the organization, like the manifest planner, work against the server
+	s.handle(http.MethodGet, "/v4/compute/security-groups", s.listUnsupported)
+	s.handle(http.MethodGet, "/v4/compute/routers", s.listUnsupported)
+	s.handle(http.MethodGet, "/v4/compute/load-balancers", s.listUnsupported)
+}
+
+func serverID(server *compute.Server) int {
+	return server.ID
+}
+
+func networkID(network *compute.Network) int {
+	return network.ID
+}
+
+func volumeID(volume *compute.Volume) int {
+	return volume.ID
+}
+
+func keyPairID(keyPair *compute.KeyPair) int {
+	return keyPair.ID
+}
+
+func elasticIPID(elasticIP *compute.ElasticIP) int {
+	return elasticIP.ID
+}
+
+func (s *Server) findServer(param string) (*compute.Server, error) {
+	id, err := parseID(param)
+	if err != nil {
+		return nil, err
+	}
+
+	server, _ := find(s.servers, id, serverID)
+	if server == nil {
+		return nil, notFound("server", id)
+	}
+
+	return server, nil
+}
+
+// renderServer returns a copy of the server with the current state of its networks.
+func (s *Server) renderServer(server *compute.Server) compute.Server {
+	res := *server
+	res.Networks = make([]compute.ServerNetworkAttachment, len(server.Networks))
+
+	for idx, attachment := range server.Networks {
+		res.Networks[idx] = attachment
+		if network, _ := find(s.networks, attachment.ID, networkID); network != nil {
+			res.Networks[idx].Network = *network
+		}
+	}
+
+	return res
+}
+
+func (s *Server) listServers(r *http.Request, params []string) (int, interface{}, error) {
+	res := make([]compute.Server, len(s.servers))
+	for idx, server := range s.servers {
+		res[idx] = s.renderServer(server)
+	}
+
+	return http.StatusOK, res, nil
+}
+
+func (s *Server) getServer(r *http.Request, params []string) (int, interface{}, error) {
+	server, err := s.findServer(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, s.renderServer(server), nil
+}
+
+func (s *Server) createServer(r *http.Request, params []string) (int, interface{}, error) {
+	var body compute.ServerCreate
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	if strings.TrimSpace(body.Name) == "" {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "name must not be empty")
+	}
+
+	location, err := findLocation(body.LocationID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	image, err := findImage(body.ImageID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	available := false
+	for _, id := range image.AvailableLocations {
+		available = available || id == location.ID
+	}
+
+	if !available {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "image %s is not available in location %s", image.Key, location.Name)
+	}
+
+	product, err := findProduct(body.ProductID, productTypeComputeServer)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	windows := image.Category == "windows"
+
+	var keyPair compute.KeyPair
+	switch {
+	case windows && body.Password == "":
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "password is required for windows images")
+	case !windows && body.KeyPairID == 0:
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "key pair is required for linux images")
+	case body.KeyPairID != 0:
+		found, _ := find(s.keyPairs, body.KeyPairID, keyPairID)
+		if found == nil {
+			return 0, nil, errorf(http.StatusUnprocessableEntity, "key pair with id %d not found", body.KeyPairID)
+		}
+
+		keyPair = *found
+	}
+
+	if body.CloudInit != "" {
+		if _, err := base64.StdEncoding.DecodeString(body.CloudInit); err != nil {
+			return 0, nil, errorf(http.StatusUnprocessableEntity, "cloud init must be base64 encoded")
+		}
+	}
+
+	network, err := s.serverNetwork(location, body.NetworkID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	privateIP, err := s.allocateAddress(network, body.PrivateIP)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	server := &compute.Server{
+		ID:       s.newID(),
+		Name:     body.Name,
+		Status:   serverStatus(compute.ServerStatusStarting),
+		Image:    image,
+		Product:  product,
+		Location: location,
+		KeyPair:  keyPair,
+	}
+
+	iface := compute.AttachedNetworkInterface{ID: s.newID(), PrivateIP: privateIP}
+	if body.AttachExternalIP {
+		iface.PublicIP = fmt.Sprintf("203.0.113.%d", iface.ID%254+1)
+	}
+
+	server.Networks = []compute.ServerNetworkAttachment{
+		{Network: *network, Interfaces: []compute.AttachedNetworkInterface{iface}},
+	}
+
+	rootVolume := &compute.Volume{
+		ID:         s.newID(),
+		Location:   location,
+		Status:     volumeStatus(compute.VolumeStatusInUse),
+		Name:       fmt.Sprintf("%s root", body.Name),
+		Size:       image.MinRootDiskSize,
+		Bootable:   true,
+		RootVolume: true,
+		AttachedTo: compute.Server{ID: server.ID, Name: server.Name},
+		CreatedAt:  now(),
+	}
+
+	s.servers = append(s.servers, server)
+	s.volumes = append(s.volumes, rootVolume)
+
+	ordering := s.placeOrder(server.ID, func() {
+		server.Status = serverStatus(compute.ServerStatusRunning)
+	})
+
+	return http.StatusCreated, ordering, nil
+}
+
+// serverNetwork returns the network a new server is attached to. Without a network, the default network of the
+// location is used, which is created if it does not exist yet.
+func (s *Server) serverNetwork(location common.Location, id int) (*compute.Network, error) {
+	if id != 0 {
+		network, _ := find(s.networks, id, networkID)
+		if network == nil {
+			return nil, errorf(http.StatusUnprocessableEntity, "network with id %d not found", id)
+		}
+
+		if network.Location.ID != location.ID {
+			return nil, errorf(http.StatusUnprocessableEntity, "network %s is not available in location %s", network.Name, location.Name)
+		}
+
+		return network, nil
+	}
+
+	for _, network := range s.networks {
+		if network.Location.ID == location.ID && network.Name == "Default Network" {
+			return network, nil
+		}
+	}
+
+	return s.addNetwork(compute.NetworkCreate{Name: "Default Network", LocationID: location.ID, CIDR: "172.31.0.0/20"})
+}
+
+// allocateAddress reserves the requested address in the network, or the next free address if none is requested.
+func (s *Server) allocateAddress(network *compute.Network, requested string) (string, error) {
+	prefix, err := netip.ParsePrefix(network.CIDR)
+	if err != nil {
+		return "", errorf(http.StatusInternalServerError, "invalid cidr of network %s", network.Name)
+	}
+
+	used := s.addresses[network.ID]
+	if used == nil {
+		used = map[string]bool{}
+		s.addresses[network.ID] = used
+	}
+
+	if requested != "" {
+		addr, err := netip.ParseAddr(requested)
+		if err != nil || !prefix.Contains(addr) {
+			return "", errorf(http.StatusUnprocessableEntity, "private ip %s is not in network %s", requested, network.CIDR)
+		}
+
+		if used[addr.String()] {
+			return "", errorf(http.StatusConflict, "private ip %s is already in use", addr)
+		}
+
+		used[addr.String()] = true
+		network.UsedIPs = len(used)
+		return addr.String(), nil
+	}
+
+	start, _ := netip.ParseAddr(network.AllocationPoolStart)
+	end, _ := netip.ParseAddr(network.AllocationPoolEnd)
+
+	for addr := start; addr.IsValid() && addr.Compare(end) <= 0; addr = addr.Next() {
+		if !used[addr.String()] {
+			used[addr.String()] = true
+			network.UsedIPs = len(used)
+			return addr.String(), nil
+		}
+	}
+
+	return "", errorf(http.StatusConflict, "network %s has no free addresses", network.Name)
+}
+
+func (s *Server) releaseAddresses(id int, interfaces []compute.AttachedNetworkInterface) {
+	used := s.addresses[id]
+	for _, iface := range interfaces {
+		delete(used, iface.PrivateIP)
+	}
+
+	if network, _ := find(s.networks, id, networkID); network != nil {
+		network.UsedIPs = len(used)
+	}
+}
+
+func (s *Server) updateServer(r *http.Request, params []string) (int, interface{}, error) {
+	server, err := s.findServer(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var body compute.ServerUpdate
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	if strings.TrimSpace(body.Name) == "" {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "name must not be empty")
+	}
+
+	server.Name = body.Name
+	return http.StatusOK, s.renderServer(server), nil
+}
+
+func (s *Server) deleteServer(r *http.Request, params []string) (int, interface{}, error) {
+	server, err := s.findServer(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	_, idx := find(s.servers, server.ID, serverID)
+	s.servers = remove(s.servers, idx)
+
+	for _, attachment := range server.Networks {
+		s.releaseAddresses(attachment.ID, attachment.Interfaces)
+	}
+
+	for _, elasticIP := range s.elasticIPs {
+		if elasticIP.Attachment.ID == server.ID {
+			elasticIP.Attachment = compute.ElasticIPAttachment{}
+			elasticIP.PrivateIP = ""
+		}
+	}
+
+	volumes := s.volumes[:0]
+	for _, volume := range s.volumes {
+		if volume.AttachedTo.ID == server.ID {
+			if volume.RootVolume {
+				continue
+			}
+
+			volume.AttachedTo = compute.Server{}
+			volume.Status = volumeStatus(compute.VolumeStatusAvailable)
+		}
+
+		volumes = append(volumes, volume)
+	}
+
+	s.volumes = volumes
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) performServerAction(r *http.Request, params []string) (int, interface{}, error) {
+	server, err := s.findServer(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var body compute.ServerPerform
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	possible := false
+	for _, action := range server.Status.Actions {
+		possible = possible || action.Command == body.Action
+	}
+
+	if !possible {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "action %q is not possible while the server is %s", body.Action, server.Status.Key)
+	}
+
+	transition, target := compute.ServerStatusStarting, compute.ServerStatusRunning
+	if body.Action == serverActionStop.Command {
+		transition, target = compute.ServerStatusStopping, compute.ServerStatusStopped
+	}
+
+	server.Status = serverStatus(transition)
+	s.schedule(s.options.ActionDelay, func() {
+		server.Status = serverStatus(target)
+	})
+
+	return http.StatusOK, s.renderServer(server), nil
+}
+
+func (s *Server) upgradeServer(r *http.Request, params []string) (int, interface{}, error) {
+	server, err := s.findServer(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var body compute.ServerUpgrade
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	product, err := findProduct(body.ProductID, productTypeComputeServer)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	previous := server.Status.ID
+	server.Status = serverStatus(compute.ServerStatusUpgrading)
+
+	ordering := s.placeOrder(server.ID, func() {
+		server.Product = product
+		server.Status = serverStatus(previous)
+	})
+
+	return http.StatusCreated, ordering, nil
+}
+
+func (s *Server) findNetwork(param string) (*compute.Network, error) {
+	id, err := parseID(param)
+	if err != nil {
+		return nil, err
+	}
+
+	network, _ := find(s.networks, id, networkID)
+	if network == nil {
+		return nil, notFound("network", id)
+	}
+
+	return network, nil
+}
+
+func (s *Server) listNetworks(r *http.Request, params []string) (int, interface{}, error) {
+	return list(s.networks)
+}
+
+func (s *Server) getNetwork(r *http.Request, params []string) (int, interface{}, error) {
+	network, err := s.findNetwork(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, network, nil
+}
+
+func (s *Server) createNetwork(r *http.Request, params []string) (int, interface{}, error) {
+	var body compute.NetworkCreate
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	if strings.TrimSpace(body.Name) == "" {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "name must not be empty")
+	}
+
+	network, err := s.addNetwork(body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusCreated, network, nil
+}
+
+func (s *Server) addNetwork(body compute.NetworkCreate) (*compute.Network, error) {
+	location, err := findLocation(body.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if body.CIDR == "" {
+		body.CIDR = "172.31.0.0/20"
+	}
+
+	prefix, err := netip.ParsePrefix(body.CIDR)
+	if err != nil || !prefix.Addr().Is4() || prefix.Bits() > 29 {
+		return nil, errorf(http.StatusUnprocessableEntity, "cidr %q must be an ipv4 network with a prefix of at most 29 bits", body.CIDR)
+	}
+
+	prefix = prefix.Masked()
+	gateway := prefix.Addr().Next()
+	start := gateway.Next()
+
+	end := start
+	total := 1<<(32-prefix.Bits()) - 3
+	for i := 1; i < total; i++ {
+		end = end.Next()
+	}
+
+	network := &compute.Network{
+		ID:                  s.newID(),
+		Name:                body.Name,
+		Description:         body.Description,
+		CIDR:                prefix.String(),
+		Location:            location,
+		DomainNameServers:   body.DomainNameServers,
+		AllocationPoolStart: start.String(),
+		AllocationPoolEnd:   end.String(),
+		GatewayIP:           gateway.String(),
+		TotalIPs:            total,
+	}
+
+	if network.DomainNameServers == nil {
+		network.DomainNameServers = []string{"1.1.1.1", "8.8.8.8"}
+	}
+
+	if body.GatewayIP != "" {
+		network.GatewayIP = body.GatewayIP
+	}
+
+	if body.AllocationPoolStart != "" && body.AllocationPoolEnd != "" {
+		network.AllocationPoolStart = body.AllocationPoolStart
+		network.AllocationPoolEnd = body.AllocationPoolEnd
+	}
+
+	s.networks = append(s.networks, network)
+	return network, nil
+}
+
+func (s *Server) updateNetwork(r *http.Request, params []string) (int, interface{}, error) {
+	network, err := s.findNetwork(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var body compute.NetworkUpdate
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	if body.Name != "" {
+		network.Name = body.Name
+	}
+
+	if body.Description != "" {
+		network.Description = body.Description
+	}
+
+	if body.DomainNameServers != nil {
+		network.DomainNameServers = body.DomainNameServers
+	}
+
+	if body.GatewayIP != "" {
+		network.GatewayIP = body.GatewayIP
+	}
+
+	return http.StatusOK, network, nil
+}
+
+func (s *Server) deleteNetwork(r *http.Request, params []string) (int, interface{}, error) {
+	network, err := s.findNetwork(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if network.UsedIPs != 0 {
+		return 0, nil, errorf(http.StatusConflict, "network %s is still in use", network.Name)
+	}
+
+	_, idx := find(s.networks, network.ID, networkID)
+	s.networks = remove(s.networks, idx)
+	delete(s.addresses, network.ID)
+
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) findVolume(param string) (*compute.Volume, error) {
+	id, err := parseID(param)
+	if err != nil {
+		return nil, err
+	}
+
+	volume, _ := find(s.volumes, id, volumeID)
+	if volume == nil {
+		return nil, notFound("volume", id)
+	}
+
+	return volume, nil
+}
+
+func (s *Server) listVolumes(r *http.Request, params []string) (int, interface{}, error) {
+	return list(s.volumes)
+}
+
+func (s *Server) getVolume(r *http.Request, params []string) (int, interface{}, error) {
+	volume, err := s.findVolume(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, volume, nil
+}
+
+func (s *Server) createVolume(r *http.Request, params []string) (int, interface{}, error) {
+	var body compute.VolumeCreate
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	if strings.TrimSpace(body.Name) == "" {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "name must not be empty")
+	}
+
+	if body.Size < 1 {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "size must be at least 1 GiB")
+	}
+
+	location, err := findLocation(body.LocationID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if body.SnapshotID != 0 {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "snapshot with id %d not found", body.SnapshotID)
+	}
+
+	volume := &compute.Volume{
+		ID:        s.newID(),
+		Location:  location,
+		Status:    volumeStatus(compute.VolumeStatusAvailable),
+		Name:      body.Name,
+		Size:      body.Size,
+		CreatedAt: now(),
+	}
+
+	if body.InstanceID != 0 {
+		if err := s.attach(volume, body.InstanceID); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	s.volumes = append(s.volumes, volume)
+	return http.StatusCreated, volume, nil
+}
+
+func (s *Server) attach(volume *compute.Volume, instanceID int) error {
+	server, _ := find(s.servers, instanceID, serverID)
+	if server == nil {
+		return errorf(http.StatusUnprocessableEntity, "server with id %d not found", instanceID)
+	}
+
+	if server.Location.ID != volume.Location.ID {
+		return errorf(http.StatusUnprocessableEntity, "server %s is not in location %s", server.Name, volume.Location.Name)
+	}
+
+	if volume.AttachedTo.ID != 0 {
+		return errorf(http.StatusConflict, "volume %s is already attached to server %s", volume.Name, volume.AttachedTo.Name)
+	}
+
+	volume.AttachedTo = compute.Server{ID: server.ID, Name: server.Name}
+	volume.Status = volumeStatus(compute.VolumeStatusInUse)
+	return nil
+}
+
+func (s *Server) updateVolume(r *http.Request, params []string) (int, interface{}, error) {
+	volume, err := s.findVolume(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var body compute.VolumeUpdate
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	if strings.TrimSpace(body.Name) == "" {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "name must not be empty")
+	}
+
+	volume.Name = body.Name
+	return http.StatusOK, volume, nil
+}
+
+func (s *Server) deleteVolume(r *http.Request, params []string) (int, interface{}, error) {
+	volume, err := s.findVolume(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if volume.RootVolume {
+		return 0, nil, errorf(http.StatusConflict, "volume %s is the root volume of server %s", volume.Name, volume.AttachedTo.Name)
+	}
+
+	if volume.AttachedTo.ID != 0 {
+		return 0, nil, errorf(http.StatusConflict, "volume %s is still attached to server %s", volume.Name, volume.AttachedTo.Name)
+	}
+
+	_, idx := find(s.volumes, volume.ID, volumeID)
+	s.volumes = remove(s.volumes, idx)
+
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) attachVolume(r *http.Request, params []string) (int, interface{}, error) {
+	volume, err := s.findVolume(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var body compute.VolumeAttach
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	if err := s.attach(volume, body.InstanceID); err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, volume, nil
+}
+
+func (s *Server) detachVolume(r *http.Request, params []string) (int, interface{}, error) {
+	volume, err := s.findVolume(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	instanceID, err := parseID(params[1])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if volume.AttachedTo.ID != instanceID {
+		return 0, nil, errorf(http.StatusConflict, "volume %s is not attached to server with id %d", volume.Name, instanceID)
+	}
+
+	if volume.RootVolume {
+		return 0, nil, errorf(http.StatusConflict, "the root volume %s can not be detached", volume.Name)
+	}
+
+	volume.AttachedTo = compute.Server{}
+	volume.Status = volumeStatus(compute.VolumeStatusAvailable)
+
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) expandVolume(r *http.Request, params []string) (int, interface{}, error) {
+	volume, err := s.findVolume(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var body compute.VolumeExpand
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	if body.Size <= volume.Size {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "size must be larger than the current size of %d GiB", volume.Size)
+	}
+
+	if volume.Status.ID == compute.VolumeStatusWorking {
+		return 0, nil, errorf(http.StatusConflict, "volume %s is currently being modified", volume.Name)
+	}
+
+	previous := volume.Status.ID
+	volume.Status = volumeStatus(compute.VolumeStatusWorking)
+
+	s.schedule(s.options.ActionDelay, func() {
+		volume.Size = body.Size
+		volume.Status = volumeStatus(previous)
+	})
+
+	return http.StatusOK, volume, nil
+}
+
+func (s *Server) listKeyPairs(r *http.Request, params []string) (int, interface{}, error) {
+	return list(s.keyPairs)
+}
+
+func (s *Server) createKeyPair(r *http.Request, params []string) (int, interface{}, error) {
+	var body compute.KeyPairCreate
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	if strings.TrimSpace(body.Name) == "" {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "name must not be empty")
+	}
+
+	fields := strings.Fields(body.PublicKey)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "ssh-") && !strings.HasPrefix(fields[0], "ecdsa-") {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "public key must be in the openssh format")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "public key must be in the openssh format")
+	}
+
+	for _, keyPair := range s.keyPairs {
+		if keyPair.Name == body.Name {
+			return 0, nil, errorf(http.StatusConflict, "key pair %s already exists", body.Name)
+		}
+	}
+
+	sum := md5.Sum(key)
+	fingerprint := make([]string, len(sum))
+	for idx, b := range sum {
+		fingerprint[idx] = fmt.Sprintf("%02x", b)
+	}
+
+	keyPair := &compute.KeyPair{
+		ID:          s.newID(),
+		Name:        body.Name,
+		Fingerprint: strings.Join(fingerprint, ":"),
+	}
+
+	s.keyPairs = append(s.keyPairs, keyPair)
+	return http.StatusCreated, keyPair, nil
+}
+
+func (s *Server) deleteKeyPair(r *http.Request, params []string) (int, interface{}, error) {
+	id, err := parseID(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	_, idx := find(s.keyPairs, id, keyPairID)
+	if idx < 0 {
+		return 0, nil, notFound("key pair", id)
+	}
+
+	s.keyPairs = remove(s.keyPairs, idx)
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) findElasticIP(param string) (*compute.ElasticIP, error) {
+	id, err := parseID(param)
+	if err != nil {
+		return nil, err
+	}
+
+	elasticIP, _ := find(s.elasticIPs, id, elasticIPID)
+	if elasticIP == nil {
+		return nil, notFound("elastic ip", id)
+	}
+
+	return elasticIP, nil
+}
+
+func (s *Server) listElasticIPs(r *http.Request, params []string) (int, interface{}, error) {
+	return list(s.elasticIPs)
+}
+
+func (s *Server) createElasticIP(r *http.Request, params []string) (int, interface{}, error) {
+	var body compute.ElasticIPCreate
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	location, err := findLocation(body.LocationID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	id := s.newID()
+	elasticIP := &compute.ElasticIP{
+		ID:       id,
+		Location: location,
+		PublicIP: fmt.Sprintf("198.51.100.%d", id%254+1),
+	}
+
+	s.elasticIPs = append(s.elasticIPs, elasticIP)
+	return http.StatusCreated, elasticIP, nil
+}
+
+func (s *Server) deleteElasticIP(r *http.Request, params []string) (int, interface{}, error) {
+	elasticIP, err := s.findElasticIP(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if elasticIP.Attachment.ID != 0 {
+		return 0, nil, errorf(http.StatusConflict, "elastic ip %s is attached to server %s", elasticIP.PublicIP, elasticIP.Attachment.Name)
+	}
+
+	_, idx := find(s.elasticIPs, elasticIP.ID, elasticIPID)
+	s.elasticIPs = remove(s.elasticIPs, idx)
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) attachElasticIP(r *http.Request, params []string) (int, interface{}, error) {
+	server, err := s.findServer(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var body compute.ElasticIPAttach
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	elasticIP, _ := find(s.elasticIPs, body.ElasticIPID, elasticIPID)
+	if elasticIP == nil {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "elastic ip with id %d not found", body.ElasticIPID)
+	}
+
+	if elasticIP.Attachment.ID != 0 {
+		return 0, nil, errorf(http.StatusConflict, "elastic ip %s is already attached to server %s", elasticIP.PublicIP, elasticIP.Attachment.Name)
+	}
+
+	if elasticIP.Location.ID != server.Location.ID {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "elastic ip %s is not available in location %s", elasticIP.PublicIP, server.Location.Name)
+	}
+
+	for _, attachment := range server.Networks {
+		for idx := range attachment.Interfaces {
+			iface := &attachment.Interfaces[idx]
+			if iface.ID != body.NetworkInterfaceID {
+				continue
+			}
+
+			if iface.PublicIP != "" {
+				return 0, nil, errorf(http.StatusConflict, "network interface %d already has the public ip %s", iface.ID, iface.PublicIP)
+			}
+
+			iface.PublicIP = elasticIP.PublicIP
+			elasticIP.PrivateIP = iface.PrivateIP
+			elasticIP.Attachment = compute.ElasticIPAttachment{ID: server.ID, Name: server.Name, Type: "instance"}
+
+			return http.StatusCreated, elasticIP, nil
+		}
+	}
+
+	return 0, nil, errorf(http.StatusUnprocessableEntity, "network interface with id %d not found", body.NetworkInterfaceID)
+}
+
+func (s *Server) detachElasticIP(r *http.Request, params []string) (int, interface{}, error) {
+	server, err := s.findServer(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	elasticIP, err := s.findElasticIP(params[1])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if elasticIP.Attachment.ID != server.ID {
+		return 0, nil, errorf(http.StatusConflict, "elastic ip %s is not attached to server %s", elasticIP.PublicIP, server.Name)
+	}
+
+	for _, attachment := range server.Networks {
+		for idx := range attachment.Interfaces {
+			if attachment.Interfaces[idx].PublicIP == elasticIP.PublicIP {
+				attachment.Interfaces[idx].PublicIP = ""
+			}
+		}
+	}
+
+	elasticIP.Attachment = compute.ElasticIPAttachment{}
+	elasticIP.PrivateIP = ""
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) listUnsupported(r *http.Request, params []string) (int, interface{}, error) {
+	return http.StatusOK, []interface{}{}, nil
+}
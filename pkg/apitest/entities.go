@@ -0,0 +1,193 @@
+package apitest
+
+import (
+	"net/http"
+
+	"github.com/flowswiss/goclient/common"
+	"github.com/flowswiss/goclient/compute"
+)
+
+const (
+	productTypeComputeServer  = "compute-engine-vm"
+	productTypeKubernetesNode = "compute-kubernetes-node"
+	productTypeVolume         = "compute-storage"
+)
+
+var (
+	moduleCompute       = common.Module{ID: 2, Name: "Compute", Sorting: 1}
+	moduleKubernetes    = common.Module{ID: 7, Name: "Kubernetes", Sorting: 2}
+	moduleObjectStorage = common.Module{ID: 8, Name: "Object Storage", Sorting: 3}
+
+	locations = []common.Location{
+		{ID: 1, Name: "ALP1", Key: "alp1", City: "Lucerne", Modules: []common.Module{moduleCompute, moduleKubernetes, moduleObjectStorage}},
+		{ID: 2, Name: "ZRH1", Key: "zrh1", City: "Zürich", Modules: []common.Module{moduleCompute, moduleObjectStorage}},
+	}
+
+	productTypes = []common.ProductType{
+		{ID: 1, Name: "Compute Engine VM", Key: productTypeComputeServer},
+		{ID: 2, Name: "Compute Storage", Key: productTypeVolume},
+		{ID: 3, Name: "Kubernetes Node", Key: productTypeKubernetesNode},
+	}
+
+	products = []common.Product{
+		newProduct(101, "b1.1x1", productTypes[0], 1, 1, 18),
+		newProduct(102, "b1.2x4", productTypes[0], 2, 4, 54),
+		newProduct(103, "b1.4x8", productTypes[0], 4, 8, 108),
+		newProduct(111, "ssd", productTypes[1], 0, 0, 0.1),
+		newProduct(121, "k1.2x4", productTypes[2], 2, 4, 60),
+		newProduct(122, "k1.4x8", productTypes[2], 4, 8, 120),
+	}
+
+	images = []compute.Image{
+		{ID: 201, OperatingSystem: "Ubuntu", Version: "22.04 LTS", Key: "linux-ubuntu-22.04-lts", Category: "linux", Type: "server", Username: "ubuntu", MinRootDiskSize: 10, Sorting: 1, AvailableLocations: []int{1, 2}},
+		{ID: 202, OperatingSystem: "Debian", Version: "11", Key: "linux-debian-11", Category: "linux", Type: "server", Username: "debian", MinRootDiskSize: 10, Sorting: 2, AvailableLocations: []int{1, 2}},
+		{ID: 203, OperatingSystem: "Windows Server", Version: "2022", Key: "microsoft-windows-server-2022", Category: "windows", Type: "server", Username: "Administrator", MinRootDiskSize: 50, Sorting: 3, AvailableLocations: []int{1}},
+	}
+
+	organization = map[string]interface{}{"id": 1, "name": "Sandbox"}
+)
+
+func newProduct(id int, name string, productType common.ProductType, cpu int, memory int, price float64) common.Product {
+	product := common.Product{
+		ID:         id,
+		Name:       name,
+		Type:       productType,
+		Visibility: "public",
+		UsageCycle: common.ProductUsageCycle{ID: 1, Name: "Hour", Duration: 1},
+		Price:      price,
+	}
+
+	if cpu != 0 {
+		product.Items = []common.ProductItem{
+			{ID: 1, Name: "vCPU", Amount: cpu},
+			{ID: 2, Name: "GB RAM", Amount: memory},
+		}
+	}
+
+	for _, location := range locations {
+		product.Availability = append(product.Availability, common.ProductAvailability{Location: location, Available: 100})
+	}
+
+	return product
+}
+
+func (s *Server) registerEntityRoutes() {
+	s.handle(http.MethodGet, "/v4/entities/locations", func(r *http.Request, params []string) (int, interface{}, error) {
+		return http.StatusOK, locations, nil
+	})
+
+	s.handle(http.MethodGet, "/v4/entities/locations/*", func(r *http.Request, params []string) (int, interface{}, error) {
+		id, err := parseID(params[0])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		location, err := findLocation(id)
+		if err != nil {
+			return 0, nil, notFound("location", id)
+		}
+
+		return http.StatusOK, location, nil
+	})
+
+	s.handle(http.MethodGet, "/v4/entities/modules", func(r *http.Request, params []string) (int, interface{}, error) {
+		modules := []common.Module{moduleCompute, moduleKubernetes, moduleObjectStorage}
+		for idx, module := range modules {
+			for _, location := range locations {
+				for _, available := range location.Modules {
+					if available.ID == module.ID {
+						modules[idx].Locations = append(modules[idx].Locations, common.Location{ID: location.ID, Name: location.Name, Key: location.Key, City: location.City})
+					}
+				}
+			}
+		}
+
+		return http.StatusOK, modules, nil
+	})
+
+	s.handle(http.MethodGet, "/v4/entities/product-types", func(r *http.Request, params []string) (int, interface{}, error) {
+		return http.StatusOK, productTypes, nil
+	})
+
+	s.handle(http.MethodGet, "/v4/products", func(r *http.Request, params []string) (int, interface{}, error) {
+		return http.StatusOK, products, nil
+	})
+
+	s.handle(http.MethodGet, "/v4/products/*", func(r *http.Request, params []string) (int, interface{}, error) {
+		if id, err := parseID(params[0]); err == nil {
+			product, err := findProduct(id, "")
+			if err != nil {
+				return 0, nil, notFound("product", id)
+			}
+
+			return http.StatusOK, product, nil
+		}
+
+		var res []common.Product
+		for _, product := range products {
+			if product.Type.Key == params[0] {
+				res = append(res, product)
+			}
+		}
+
+		if res == nil {
+			return 0, nil, errorf(http.StatusNotFound, "product type %q not found", params[0])
+		}
+
+		return http.StatusOK, res, nil
+	})
+
+	s.handle(http.MethodGet, "/v4/entities/compute/images", func(r *http.Request, params []string) (int, interface{}, error) {
+		return http.StatusOK, images, nil
+	})
+
+	s.handle(http.MethodGet, "/v4/entities/compute/images/*", func(r *http.Request, params []string) (int, interface{}, error) {
+		id, err := parseID(params[0])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		image, err := findImage(id)
+		if err != nil {
+			return 0, nil, notFound("image", id)
+		}
+
+		return http.StatusOK, image, nil
+	})
+
+	s.handle(http.MethodGet, "/v4/organization", func(r *http.Request, params []string) (int, interface{}, error) {
+		return http.StatusOK, organization, nil
+	})
+}
+
+// findLocation returns the location referenced by the body of a request.
+func findLocation(id int) (common.Location, error) {
+	for _, location := range locations {
+		if location.ID == id {
+			return location, nil
+		}
+	}
+
+	return common.Location{}, errorf(http.StatusUnprocessableEntity, "location with id %d not found", id)
+}
+
+// findProduct returns the product with the id, which must be of the product type unless it is empty.
+func findProduct(id int, productType string) (common.Product, error) {
+	for _, product := range products {
+		if product.ID == id && (productType == "" || product.Type.Key == productType) {
+			return product, nil
+		}
+	}
+
+	return common.Product{}, errorf(http.StatusUnprocessableEntity, "product with id %d not found", id)
+}
+
+func findImage(id int) (compute.Image, error) {
+	for _, image := range images {
+		if image.ID == id {
+			return image, nil
+		}
+	}
+
+	return compute.Image{}, errorf(http.StatusUnprocessableEntity, "image with id %d not found", id)
+}
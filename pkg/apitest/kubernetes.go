@@ -0,0 +1,316 @@
+package apitest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/flowswiss/goclient/common"
+	"github.com/flowswiss/goclient/compute"
+	"github.com/flowswiss/goclient/kubernetes"
+)
+
+var (
+	clusterVersion = kubernetes.ClusterVersion{ID: 1, Name: "1.27", Major: 1, Minor: 27}
+
+	nodeRoleControlPlane = kubernetes.NodeRole{ID: 1, Key: "control-plane", Name: "Control Plane"}
+	nodeRoleWorker       = kubernetes.NodeRole{ID: 2, Key: "worker", Name: "Worker"}
+)
+
+func clusterStatus(id int) kubernetes.ClusterStatus {
+	switch id {
+	case compute.ClusterStatusHealthy:
+		return kubernetes.ClusterStatus{ID: id, Key: "healthy", Name: "Healthy", Actions: []kubernetes.ClusterAction{}}
+	case compute.ClusterStatusWorking:
+		return kubernetes.ClusterStatus{ID: id, Key: "working", Name: "Working", Actions: []kubernetes.ClusterAction{}}
+	}
+
+	return kubernetes.ClusterStatus{ID: compute.ClusterStatusUnavailable, Key: "unavailable", Name: "Unavailable", Actions: []kubernetes.ClusterAction{}}
+}
+
+func (s *Server) registerKubernetesRoutes() {
+	s.handle(http.MethodGet, "/v4/kubernetes/clusters", s.listClusters)
+	s.handle(http.MethodPost, "/v4/kubernetes/clusters", s.createCluster)
+	s.handle(http.MethodGet, "/v4/kubernetes/clusters/*", s.getCluster)
+	s.handle(http.MethodPatch, "/v4/kubernetes/clusters/*", s.updateCluster)
+	s.handle(http.MethodDelete, "/v4/kubernetes/clusters/*", s.deleteCluster)
+	s.handle(http.MethodPatch, "/v4/kubernetes/clusters/*/flavor", s.updateClusterFlavor)
+	s.handle(http.MethodGet, "/v4/kubernetes/clusters/*/kube-config", s.getClusterKubeConfig)
+	s.handle(http.MethodGet, "/v4/kubernetes/clusters/*/nodes", s.listNodes)
+}
+
+func clusterID(cluster *kubernetes.Cluster) int {
+	return cluster.ID
+}
+
+func (s *Server) findCluster(param string) (*kubernetes.Cluster, error) {
+	id, err := parseID(param)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, _ := find(s.clusters, id, clusterID)
+	if cluster == nil {
+		return nil, notFound("cluster", id)
+	}
+
+	return cluster, nil
+}
+
+func (s *Server) listClusters(r *http.Request, params []string) (int, interface{}, error) {
+	return list(s.clusters)
+}
+
+func (s *Server) getCluster(r *http.Request, params []string) (int, interface{}, error) {
+	cluster, err := s.findCluster(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return http.StatusOK, cluster, nil
+}
+
+func (s *Server) createCluster(r *http.Request, params []string) (int, interface{}, error) {
+	var body kubernetes.ClusterCreate
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	if strings.TrimSpace(body.Name) == "" {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "name must not be empty")
+	}
+
+	location, err := findLocation(body.LocationID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	supported := false
+	for _, module := range location.Modules {
+		supported = supported || module.ID == moduleKubernetes.ID
+	}
+
+	if !supported {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "kubernetes is not available in location %s", location.Name)
+	}
+
+	worker, err := findProduct(body.Worker.ProductID, productTypeKubernetesNode)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if body.Worker.Count < 1 {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "worker count must be at least 1")
+	}
+
+	network, err := s.serverNetwork(location, body.NetworkID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	controlPlane, _ := findProduct(121, productTypeKubernetesNode)
+
+	cluster := &kubernetes.Cluster{
+		ID:       s.newID(),
+		Name:     body.Name,
+		Location: location,
+		Product:  controlPlane,
+		Network:  *network,
+		DNSName:  fmt.Sprintf("%s.k8s.example.com", strings.ToLower(body.Name)),
+		Version:  clusterVersion,
+		Status:   clusterStatus(compute.ClusterStatusWorking),
+	}
+
+	if body.AttachExternalIP {
+		cluster.PublicAddress = fmt.Sprintf("198.51.100.%d", cluster.ID%254+1)
+	}
+
+	cluster.NodeCount.Expected.ControlPlane = 1
+	cluster.NodeCount.Expected.Worker = body.Worker.Count
+	cluster.ExpectedPreset.ControlPlane = controlPlane
+	cluster.ExpectedPreset.Worker = worker
+
+	s.clusters = append(s.clusters, cluster)
+
+	ordering := s.placeOrder(cluster.ID, func() {
+		s.scaleCluster(cluster)
+
+		expires := time.Now().AddDate(0, 0, 30).Truncate(time.Second)
+		cluster.KubeConfig.UpdatedAt = now()
+		cluster.KubeConfig.ExpiresAt = common.Time(expires)
+		cluster.Status = clusterStatus(compute.ClusterStatusHealthy)
+	})
+
+	return http.StatusCreated, ordering, nil
+}
+
+// scaleCluster creates and removes nodes until the cluster matches the expected node count and preset.
+func (s *Server) scaleCluster(cluster *kubernetes.Cluster) {
+	network, _ := find(s.networks, cluster.Network.ID, networkID)
+
+	var nodes []*kubernetes.Node
+	controlPlanes, workers := 0, 0
+
+	for _, node := range s.nodes[cluster.ID] {
+		worker := node.Roles[0].ID == nodeRoleWorker.ID
+		if worker && workers < cluster.NodeCount.Expected.Worker {
+			node.Product = cluster.ExpectedPreset.Worker
+			nodes = append(nodes, node)
+			workers++
+			continue
+		}
+
+		if !worker && controlPlanes < cluster.NodeCount.Expected.ControlPlane {
+			nodes = append(nodes, node)
+			controlPlanes++
+			continue
+		}
+
+		s.releaseAddresses(cluster.Network.ID, node.Network.Interfaces)
+	}
+
+	addNode := func(role kubernetes.NodeRole, product common.Product, idx int) {
+		node := &kubernetes.Node{
+			ID:      s.newID(),
+			Name:    fmt.Sprintf("%s-%s-%d", cluster.Name, role.Key, idx),
+			Roles:   []kubernetes.NodeRole{role},
+			Product: product,
+			Status:  kubernetes.NodeStatus{ID: compute.ServerStatusRunning, Key: "running", Name: "Running", Actions: []kubernetes.NodeAction{}},
+		}
+
+		node.Network.Network = cluster.Network
+		if network != nil {
+			if address, err := s.allocateAddress(network, ""); err == nil {
+				node.Network.Interfaces = []compute.AttachedNetworkInterface{{ID: s.newID(), PrivateIP: address}}
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	for ; controlPlanes < cluster.NodeCount.Expected.ControlPlane; controlPlanes++ {
+		addNode(nodeRoleControlPlane, cluster.ExpectedPreset.ControlPlane, controlPlanes+1)
+	}
+
+	for ; workers < cluster.NodeCount.Expected.Worker; workers++ {
+		addNode(nodeRoleWorker, cluster.ExpectedPreset.Worker, workers+1)
+	}
+
+	s.nodes[cluster.ID] = nodes
+	cluster.NodeCount.Current = cluster.NodeCount.Expected
+}
+
+func (s *Server) updateCluster(r *http.Request, params []string) (int, interface{}, error) {
+	cluster, err := s.findCluster(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var body kubernetes.ClusterUpdate
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	if body.Name != "" {
+		cluster.Name = body.Name
+	}
+
+	return http.StatusOK, cluster, nil
+}
+
+func (s *Server) updateClusterFlavor(r *http.Request, params []string) (int, interface{}, error) {
+	cluster, err := s.findCluster(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var body kubernetes.ClusterUpdateFlavor
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	if cluster.Locked {
+		return 0, nil, errorf(http.StatusConflict, "cluster %s is locked by another operation", cluster.Name)
+	}
+
+	worker, err := findProduct(body.Worker.ProductID, productTypeKubernetesNode)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if body.Worker.Count < 1 {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, "worker count must be at least 1")
+	}
+
+	cluster.Locked = true
+	cluster.Status = clusterStatus(compute.ClusterStatusWorking)
+	cluster.NodeCount.Expected.Worker = body.Worker.Count
+	cluster.ExpectedPreset.Worker = worker
+
+	s.schedule(s.options.OrderDelay, func() {
+		s.scaleCluster(cluster)
+
+		cluster.Locked = false
+		cluster.Status = clusterStatus(compute.ClusterStatusHealthy)
+	})
+
+	return http.StatusOK, cluster, nil
+}
+
+func (s *Server) deleteCluster(r *http.Request, params []string) (int, interface{}, error) {
+	cluster, err := s.findCluster(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for _, node := range s.nodes[cluster.ID] {
+		s.releaseAddresses(cluster.Network.ID, node.Network.Interfaces)
+	}
+
+	_, idx := find(s.clusters, cluster.ID, clusterID)
+	s.clusters = remove(s.clusters, idx)
+	delete(s.nodes, cluster.ID)
+
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) getClusterKubeConfig(r *http.Request, params []string) (int, interface{}, error) {
+	cluster, err := s.findCluster(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if cluster.Status.ID != compute.ClusterStatusHealthy {
+		return 0, nil, errorf(http.StatusConflict, "cluster %s is not ready yet", cluster.Name)
+	}
+
+	config := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: %[1]s
+  cluster:
+    server: https://%[2]s:6443
+contexts:
+- name: %[1]s
+  context:
+    cluster: %[1]s
+    user: %[1]s-admin
+current-context: %[1]s
+users:
+- name: %[1]s-admin
+  user:
+    token: sandbox
+`, cluster.Name, cluster.DNSName)
+
+	return http.StatusOK, kubernetes.ClusterKubeConfig{KubeConfig: config}, nil
+}
+
+func (s *Server) listNodes(r *http.Request, params []string) (int, interface{}, error) {
+	cluster, err := s.findCluster(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return list(s.nodes[cluster.ID])
+}
@@ -0,0 +1,98 @@
+package apitest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/flowswiss/goclient/objectstorage"
+)
+
+func (s *Server) registerObjectStorageRoutes() {
+	s.handle(http.MethodGet, "/v4/object-storage/instances", s.listObjectStorageInstances)
+	s.handle(http.MethodPost, "/v4/object-storage/instances", s.createObjectStorageInstance)
+	s.handle(http.MethodDelete, "/v4/object-storage/instances/*", s.deleteObjectStorageInstance)
+	s.handle(http.MethodGet, "/v4/object-storage/credentials", s.listObjectStorageCredentials)
+}
+
+func objectStorageInstanceID(instance *objectstorage.Instance) int {
+	return instance.ID
+}
+
+func (s *Server) listObjectStorageInstances(r *http.Request, params []string) (int, interface{}, error) {
+	return list(s.objectStorageInstances)
+}
+
+func (s *Server) createObjectStorageInstance(r *http.Request, params []string) (int, interface{}, error) {
+	var body objectstorage.InstanceCreate
+	if err := decode(r, &body); err != nil {
+		return 0, nil, err
+	}
+
+	location, err := findLocation(body.LocationID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for _, instance := range s.objectStorageInstances {
+		if instance.Location.ID == location.ID {
+			return 0, nil, errorf(http.StatusConflict, "an object storage instance already exists in %s", location.Name)
+		}
+	}
+
+	instance := &objectstorage.Instance{
+		ID:       s.newID(),
+		Name:     fmt.Sprintf("Object Storage %s", location.Name),
+		Location: location,
+	}
+
+	s.objectStorageInstances = append(s.objectStorageInstances, instance)
+	s.buckets[location.Key] = map[string]*s3Bucket{}
+
+	return http.StatusCreated, instance, nil
+}
+
+func (s *Server) deleteObjectStorageInstance(r *http.Request, params []string) (int, interface{}, error) {
+	id, err := parseID(params[0])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	instance, idx := find(s.objectStorageInstances, id, objectStorageInstanceID)
+	if instance == nil {
+		return 0, nil, notFound("object storage instance", id)
+	}
+
+	s.objectStorageInstances = remove(s.objectStorageInstances, idx)
+	delete(s.buckets, instance.Location.Key)
+
+	return http.StatusNoContent, nil, nil
+}
+
+// listObjectStorageCredentials returns one credential per instance. The endpoint points to the S3 stand-in of the
+// location served by this server.
+func (s *Server) listObjectStorageCredentials(r *http.Request, params []string) (int, interface{}, error) {
+	credentials := make([]objectstorage.Credential, len(s.objectStorageInstances))
+	for idx, instance := range s.objectStorageInstances {
+		credentials[idx] = objectStorageCredential(r, instance)
+	}
+
+	return http.StatusOK, credentials, nil
+}
+
+func objectStorageCredential(r *http.Request, instance *objectstorage.Instance) objectstorage.Credential {
+	return objectstorage.Credential{
+		ID:        instance.ID,
+		Location:  instance.Location,
+		Endpoint:  fmt.Sprintf("http://%s%s%s", r.Host, s3PathPrefix, instance.Location.Key),
+		AccessKey: objectStorageAccessKey(instance),
+		SecretKey: objectStorageSecretKey(instance),
+	}
+}
+
+func objectStorageAccessKey(instance *objectstorage.Instance) string {
+	return fmt.Sprintf("SANDBOX%d", instance.ID)
+}
+
+func objectStorageSecretKey(instance *objectstorage.Instance) string {
+	return fmt.Sprintf("sandbox-secret-%d", instance.ID)
+}
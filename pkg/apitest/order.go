@@ -0,0 +1,54 @@
+package apitest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/flowswiss/goclient/common"
+)
+
+var (
+	orderStatusProcessing = common.OrderStatus{ID: common.OrderStatusProcessing, Name: "Processing"}
+	orderStatusSucceeded  = common.OrderStatus{ID: common.OrderStatusSucceeded, Name: "Succeeded"}
+)
+
+func (s *Server) registerOrderRoutes() {
+	s.handle(http.MethodGet, "/v4/orders", func(r *http.Request, params []string) (int, interface{}, error) {
+		return list(s.orders)
+	})
+
+	s.handle(http.MethodGet, "/v4/orders/*", func(r *http.Request, params []string) (int, interface{}, error) {
+		id, err := parseID(params[0])
+		if err != nil {
+			return 0, nil, err
+		}
+
+		order, _ := find(s.orders, id, func(o *common.Order) int { return o.ID })
+		if order == nil {
+			return 0, nil, notFound("order", id)
+		}
+
+		return http.StatusOK, order, nil
+	})
+}
+
+// placeOrder creates an order for the product instance, which is processing for the configured order delay. The
+// completion is run once the order succeeded, e.g. to mark a server as running.
+func (s *Server) placeOrder(instanceID int, completion func()) common.Ordering {
+	order := &common.Order{
+		ID:        s.newID(),
+		Status:    orderStatusProcessing,
+		CreatedAt: now(),
+	}
+
+	s.orders = append(s.orders, order)
+
+	s.schedule(s.options.OrderDelay, func() {
+		order.Status = orderStatusSucceeded
+		order.Product = common.Product{ID: instanceID}
+
+		completion()
+	})
+
+	return common.Ordering{Ref: fmt.Sprintf("/v4/orders/%d", order.ID)}
+}
@@ -0,0 +1,857 @@
+package apitest
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudbit-ch/cli/v2/pkg/s3"
+)
+
+// s3PathPrefix is the prefix of the S3 compatible stand-in, which serves the buckets of each location with an object
+// storage instance below s3PathPrefix + location key, e.g. /s3/alp1/my-bucket/key. Requests are authenticated using
+// the signature of the credentials instead of the api token.
+const s3PathPrefix = "/s3/"
+
+const s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+var bucketNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+type s3Bucket struct {
+	created time.Time
+	objects map[string]*s3Object
+	uploads map[string]*s3Upload
+
+	// settings holds the configuration documents of the bucket by subresource, e.g. lifecycle
+	settings map[string][]byte
+}
+
+type s3Object struct {
+	data        []byte
+	etag        string
+	contentType string
+	modified    time.Time
+}
+
+// s3Upload is an unfinished multipart upload.
+type s3Upload struct {
+	key         string
+	contentType string
+	parts       map[int]*s3Object
+}
+
+// s3Error is an error in the xml format of S3.
+type s3Error struct {
+	XMLName xml.Name `xml:"Error"`
+	Status  int      `xml:"-"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+func (e s3Error) Error() string {
+	return e.Message
+}
+
+func s3Errorf(status int, code string, message string) s3Error {
+	return s3Error{Status: status, Code: code, Message: message}
+}
+
+func noSuchBucket() s3Error {
+	return s3Errorf(http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+}
+
+// s3Request is a request to the stand-in with the path split into bucket and key.
+type s3Request struct {
+	*http.Request
+
+	buckets map[string]*s3Bucket
+	bucket  string
+	key     string
+	body    []byte
+}
+
+type s3Response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (s *Server) serveS3(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, err := s.handleS3(r)
+	if err != nil {
+		s3Err, ok := err.(s3Error)
+		if !ok {
+			s3Err = s3Errorf(http.StatusInternalServerError, "InternalError", err.Error())
+		}
+
+		res = s3Response{status: s3Err.Status}
+		if r.Method != http.MethodHead {
+			res.body = marshalXML(s3Err)
+			res.header = http.Header{"Content-Type": {"application/xml"}}
+		}
+	}
+
+	for name, values := range res.header {
+		w.Header()[name] = values
+	}
+
+	if r.Method != http.MethodHead {
+		w.Header().Set("Content-Length", strconv.Itoa(len(res.body)))
+	}
+
+	w.WriteHeader(res.status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(res.body)
+	}
+}
+
+func (s *Server) handleS3(r *http.Request) (s3Response, error) {
+	location, path, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, s3PathPrefix), "/")
+
+	buckets, ok := s.buckets[location]
+	if !ok {
+		return s3Response{}, s3Errorf(http.StatusNotFound, "NoSuchInstance", "no object storage instance in location "+location)
+	}
+
+	if err := s3.Verify(r, s.s3SecretKey(location)); err != nil {
+		return s3Response{}, s3Errorf(http.StatusForbidden, "SignatureDoesNotMatch", err.Error())
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	// the payload of presigned requests is not signed
+	if hash := r.Header.Get("X-Amz-Content-Sha256"); hash != "" && hash != s3.UnsignedPayload {
+		sum := sha256.Sum256(body)
+		if hash != hex.EncodeToString(sum[:]) {
+			return s3Response{}, s3Errorf(http.StatusBadRequest, "XAmzContentSHA256Mismatch", "The provided x-amz-content-sha256 header does not match what was computed")
+		}
+	}
+
+	req := s3Request{Request: r, buckets: buckets, body: body}
+	req.bucket, req.key, _ = strings.Cut(path, "/")
+
+	switch {
+	case req.bucket == "" && r.Method == http.MethodGet:
+		return listS3Buckets(req)
+	case req.bucket == "":
+	case req.key == "" && s3BucketSetting(r.URL.Query()) != nil:
+		switch r.Method {
+		case http.MethodPut:
+			return putS3BucketSetting(req)
+		case http.MethodGet:
+			return getS3BucketSetting(req)
+		}
+	case req.key == "":
+		switch r.Method {
+		case http.MethodPut:
+			return createS3Bucket(req)
+		case http.MethodDelete:
+			return deleteS3Bucket(req)
+		case http.MethodHead:
+			return headS3Bucket(req)
+		case http.MethodGet:
+			return listS3Objects(req)
+		}
+	case r.URL.Query().Has("uploads"):
+		if r.Method == http.MethodPost {
+			return createS3MultipartUpload(req)
+		}
+	case r.URL.Query().Has("uploadId"):
+		switch r.Method {
+		case http.MethodPut:
+			return uploadS3Part(req)
+		case http.MethodPost:
+			return completeS3MultipartUpload(req)
+		case http.MethodGet:
+			return listS3Parts(req)
+		case http.MethodDelete:
+			return abortS3MultipartUpload(req)
+		}
+	default:
+		switch r.Method {
+		case http.MethodPut:
+			if r.Header.Get("X-Amz-Copy-Source") != "" {
+				return copyS3Object(req)
+			}
+
+			return putS3Object(req)
+		case http.MethodGet, http.MethodHead:
+			return getS3Object(req)
+		case http.MethodDelete:
+			return deleteS3Object(req)
+		}
+	}
+
+	return s3Response{}, s3Errorf(http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource")
+}
+
+// s3SecretKey looks up the secret key of the credentials of the instance in the location.
+func (s *Server) s3SecretKey(location string) func(accessKey string) (string, bool) {
+	return func(accessKey string) (string, bool) {
+		for _, instance := range s.objectStorageInstances {
+			if instance.Location.Key == location && objectStorageAccessKey(instance) == accessKey {
+				return objectStorageSecretKey(instance), true
+			}
+		}
+
+		return "", false
+	}
+}
+
+func (r s3Request) findBucket() (*s3Bucket, error) {
+	bucket, ok := r.buckets[r.bucket]
+	if !ok {
+		return nil, noSuchBucket()
+	}
+
+	return bucket, nil
+}
+
+func (r s3Request) findObject() (*s3Bucket, *s3Object, error) {
+	bucket, err := r.findBucket()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	object, ok := bucket.objects[r.key]
+	if !ok {
+		return nil, nil, s3Errorf(http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+	}
+
+	return bucket, object, nil
+}
+
+func listS3Buckets(r s3Request) (s3Response, error) {
+	type bucket struct {
+		Name         string `xml:"Name"`
+		CreationDate string `xml:"CreationDate"`
+	}
+
+	type result struct {
+		XMLName xml.Name `xml:"ListAllMyBucketsResult"`
+		Xmlns   string   `xml:"xmlns,attr"`
+		Buckets []bucket `xml:"Buckets>Bucket"`
+	}
+
+	res := result{Xmlns: s3Namespace}
+	for _, name := range sortedKeys(r.buckets) {
+		res.Buckets = append(res.Buckets, bucket{Name: name, CreationDate: s3Time(r.buckets[name].created)})
+	}
+
+	return xmlResponse(res), nil
+}
+
+func createS3Bucket(r s3Request) (s3Response, error) {
+	if !bucketNameRegex.MatchString(r.bucket) {
+		return s3Response{}, s3Errorf(http.StatusBadRequest, "InvalidBucketName", "The specified bucket is not valid.")
+	}
+
+	if _, ok := r.buckets[r.bucket]; ok {
+		return s3Response{}, s3Errorf(http.StatusConflict, "BucketAlreadyOwnedByYou", "Your previous request to create the named bucket succeeded and you already own it.")
+	}
+
+	r.buckets[r.bucket] = &s3Bucket{
+		created:  time.Now(),
+		objects:  map[string]*s3Object{},
+		uploads:  map[string]*s3Upload{},
+		settings: map[string][]byte{},
+	}
+
+	return s3Response{status: http.StatusOK, header: http.Header{"Location": {"/" + r.bucket}}}, nil
+}
+
+func deleteS3Bucket(r s3Request) (s3Response, error) {
+	bucket, err := r.findBucket()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	if len(bucket.objects) != 0 {
+		return s3Response{}, s3Errorf(http.StatusConflict, "BucketNotEmpty", "The bucket you tried to delete is not empty.")
+	}
+
+	delete(r.buckets, r.bucket)
+
+	return s3Response{status: http.StatusNoContent}, nil
+}
+
+func headS3Bucket(r s3Request) (s3Response, error) {
+	if _, err := r.findBucket(); err != nil {
+		return s3Response{}, err
+	}
+
+	return s3Response{status: http.StatusOK}, nil
+}
+
+func listS3Objects(r s3Request) (s3Response, error) {
+	bucket, err := r.findBucket()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	type content struct {
+		Key          string `xml:"Key"`
+		LastModified string `xml:"LastModified"`
+		ETag         string `xml:"ETag"`
+		Size         int    `xml:"Size"`
+		StorageClass string `xml:"StorageClass"`
+	}
+
+	type prefix struct {
+		Prefix string `xml:"Prefix"`
+	}
+
+	type result struct {
+		XMLName               xml.Name  `xml:"ListBucketResult"`
+		Xmlns                 string    `xml:"xmlns,attr"`
+		Name                  string    `xml:"Name"`
+		Prefix                string    `xml:"Prefix"`
+		Delimiter             string    `xml:"Delimiter,omitempty"`
+		MaxKeys               int       `xml:"MaxKeys"`
+		KeyCount              int       `xml:"KeyCount"`
+		IsTruncated           bool      `xml:"IsTruncated"`
+		ContinuationToken     string    `xml:"ContinuationToken,omitempty"`
+		NextContinuationToken string    `xml:"NextContinuationToken,omitempty"`
+		Contents              []content `xml:"Contents"`
+		CommonPrefixes        []prefix  `xml:"CommonPrefixes"`
+	}
+
+	query := r.URL.Query()
+	res := result{
+		Xmlns:             s3Namespace,
+		Name:              r.bucket,
+		Prefix:            query.Get("prefix"),
+		Delimiter:         query.Get("delimiter"),
+		MaxKeys:           1000,
+		ContinuationToken: query.Get("continuation-token"),
+	}
+
+	if maxKeys, err := strconv.Atoi(query.Get("max-keys")); err == nil && maxKeys > 0 && maxKeys < res.MaxKeys {
+		res.MaxKeys = maxKeys
+	}
+
+	// the continuation token is the last key or common prefix of the previous page
+	last := ""
+	for _, key := range sortedKeys(bucket.objects) {
+		if !strings.HasPrefix(key, res.Prefix) || key <= res.ContinuationToken {
+			continue
+		}
+
+		entry := key
+		if res.Delimiter != "" {
+			if idx := strings.Index(key[len(res.Prefix):], res.Delimiter); idx >= 0 {
+				entry = key[:len(res.Prefix)+idx+len(res.Delimiter)]
+			}
+		}
+
+		if entry == last || (entry != key && entry <= res.ContinuationToken) {
+			continue
+		}
+
+		if res.KeyCount == res.MaxKeys {
+			res.IsTruncated = true
+			res.NextContinuationToken = last
+			break
+		}
+
+		if entry != key {
+			res.CommonPrefixes = append(res.CommonPrefixes, prefix{Prefix: entry})
+		} else {
+			object := bucket.objects[key]
+			res.Contents = append(res.Contents, content{
+				Key:          key,
+				LastModified: s3Time(object.modified),
+				ETag:         `"` + object.etag + `"`,
+				Size:         len(object.data),
+				StorageClass: "STANDARD",
+			})
+		}
+
+		res.KeyCount++
+		last = entry
+	}
+
+	return xmlResponse(res), nil
+}
+
+// newS3Object creates an object from the body of the request, which is rejected if it does not match the md5 hash of the
+// Content-MD5 header.
+func newS3Object(r s3Request) (*s3Object, error) {
+	sum := md5.Sum(r.body)
+
+	if header := r.Header.Get("Content-MD5"); header != "" {
+		if err := verifyS3ContentMD5(header, sum); err != nil {
+			return nil, err
+		}
+	}
+
+	return &s3Object{
+		data:        r.body,
+		etag:        hex.EncodeToString(sum[:]),
+		contentType: r.Header.Get("Content-Type"),
+		modified:    time.Now(),
+	}, nil
+}
+
+func putS3Object(r s3Request) (s3Response, error) {
+	bucket, err := r.findBucket()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	object, err := newS3Object(r)
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	if object.contentType == "" {
+		object.contentType = "binary/octet-stream"
+	}
+
+	bucket.objects[r.key] = object
+
+	return s3Response{status: http.StatusOK, header: http.Header{"Etag": {`"` + object.etag + `"`}}}, nil
+}
+
+func copyS3Object(r s3Request) (s3Response, error) {
+	bucket, err := r.findBucket()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	source := strings.TrimPrefix(r.Header.Get("X-Amz-Copy-Source"), "/")
+	if unescaped, err := url.PathUnescape(source); err == nil {
+		source = unescaped
+	}
+
+	srcBucket, srcKey, _ := strings.Cut(source, "/")
+
+	_, src, err := s3Request{buckets: r.buckets, bucket: srcBucket, key: srcKey}.findObject()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	object := *src
+	object.modified = time.Now()
+	bucket.objects[r.key] = &object
+
+	type result struct {
+		XMLName      xml.Name `xml:"CopyObjectResult"`
+		LastModified string   `xml:"LastModified"`
+		ETag         string   `xml:"ETag"`
+	}
+
+	return xmlResponse(result{LastModified: s3Time(object.modified), ETag: `"` + object.etag + `"`}), nil
+}
+
+func getS3Object(r s3Request) (s3Response, error) {
+	_, object, err := r.findObject()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	header := http.Header{
+		"Etag":          {`"` + object.etag + `"`},
+		"Content-Type":  {object.contentType},
+		"Last-Modified": {object.modified.UTC().Format(http.TimeFormat)},
+	}
+
+	if r.Method == http.MethodHead {
+		header.Set("Content-Length", strconv.Itoa(len(object.data)))
+	}
+
+	return s3Response{status: http.StatusOK, header: header, body: object.data}, nil
+}
+
+func deleteS3Object(r s3Request) (s3Response, error) {
+	bucket, err := r.findBucket()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	delete(bucket.objects, r.key)
+
+	return s3Response{status: http.StatusNoContent}, nil
+}
+
+func createS3MultipartUpload(r s3Request) (s3Response, error) {
+	bucket, err := r.findBucket()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	id := make([]byte, 16)
+	if _, err = rand.Read(id); err != nil {
+		return s3Response{}, err
+	}
+
+	upload := &s3Upload{key: r.key, contentType: r.Header.Get("Content-Type"), parts: map[int]*s3Object{}}
+	if upload.contentType == "" {
+		upload.contentType = "binary/octet-stream"
+	}
+
+	uploadID := hex.EncodeToString(id)
+	bucket.uploads[uploadID] = upload
+
+	type result struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		Xmlns    string   `xml:"xmlns,attr"`
+		Bucket   string   `xml:"Bucket"`
+		Key      string   `xml:"Key"`
+		UploadID string   `xml:"UploadId"`
+	}
+
+	return xmlResponse(result{Xmlns: s3Namespace, Bucket: r.bucket, Key: r.key, UploadID: uploadID}), nil
+}
+
+func (r s3Request) findUpload() (*s3Bucket, *s3Upload, error) {
+	bucket, err := r.findBucket()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	upload, ok := bucket.uploads[r.URL.Query().Get("uploadId")]
+	if !ok || upload.key != r.key {
+		return nil, nil, s3Errorf(http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist. The upload ID may be invalid, or the upload may have been aborted or completed.")
+	}
+
+	return bucket, upload, nil
+}
+
+func uploadS3Part(r s3Request) (s3Response, error) {
+	_, upload, err := r.findUpload()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	number, err := strconv.Atoi(r.URL.Query().Get("partNumber"))
+	if err != nil || number < 1 || number > s3.MaxParts {
+		return s3Response{}, s3Errorf(http.StatusBadRequest, "InvalidArgument", "Part number must be an integer between 1 and 10000, inclusive")
+	}
+
+	part, err := newS3Object(r)
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	upload.parts[number] = part
+
+	return s3Response{status: http.StatusOK, header: http.Header{"Etag": {`"` + part.etag + `"`}}}, nil
+}
+
+func listS3Parts(r s3Request) (s3Response, error) {
+	_, upload, err := r.findUpload()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	type part struct {
+		PartNumber   int    `xml:"PartNumber"`
+		LastModified string `xml:"LastModified"`
+		ETag         string `xml:"ETag"`
+		Size         int    `xml:"Size"`
+	}
+
+	type result struct {
+		XMLName              xml.Name `xml:"ListPartsResult"`
+		Xmlns                string   `xml:"xmlns,attr"`
+		Bucket               string   `xml:"Bucket"`
+		Key                  string   `xml:"Key"`
+		UploadID             string   `xml:"UploadId"`
+		PartNumberMarker     int      `xml:"PartNumberMarker"`
+		NextPartNumberMarker int      `xml:"NextPartNumberMarker"`
+		MaxParts             int      `xml:"MaxParts"`
+		IsTruncated          bool     `xml:"IsTruncated"`
+		Parts                []part   `xml:"Part"`
+	}
+
+	query := r.URL.Query()
+	res := result{Xmlns: s3Namespace, Bucket: r.bucket, Key: r.key, UploadID: query.Get("uploadId"), MaxParts: 1000}
+	res.PartNumberMarker, _ = strconv.Atoi(query.Get("part-number-marker"))
+
+	if maxParts, err := strconv.Atoi(query.Get("max-parts")); err == nil && maxParts > 0 && maxParts < res.MaxParts {
+		res.MaxParts = maxParts
+	}
+
+	numbers := make([]int, 0, len(upload.parts))
+	for number := range upload.parts {
+		if number > res.PartNumberMarker {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Ints(numbers)
+
+	for _, number := range numbers {
+		if len(res.Parts) == res.MaxParts {
+			res.IsTruncated = true
+			break
+		}
+
+		uploaded := upload.parts[number]
+		res.Parts = append(res.Parts, part{
+			PartNumber:   number,
+			LastModified: s3Time(uploaded.modified),
+			ETag:         `"` + uploaded.etag + `"`,
+			Size:         len(uploaded.data),
+		})
+		res.NextPartNumberMarker = number
+	}
+
+	return xmlResponse(res), nil
+}
+
+func completeS3MultipartUpload(r s3Request) (s3Response, error) {
+	bucket, upload, err := r.findUpload()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	var body struct {
+		Parts []struct {
+			PartNumber int    `xml:"PartNumber"`
+			ETag       string `xml:"ETag"`
+		} `xml:"Part"`
+	}
+
+	if err = xml.Unmarshal(r.body, &body); err != nil || len(body.Parts) == 0 {
+		return s3Response{}, s3Errorf(http.StatusBadRequest, "MalformedXML", "The XML you provided was not well-formed or did not validate against our published schema.")
+	}
+
+	var (
+		data  []byte
+		etags []string
+	)
+
+	for idx, completed := range body.Parts {
+		if idx > 0 && completed.PartNumber <= body.Parts[idx-1].PartNumber {
+			return s3Response{}, s3Errorf(http.StatusBadRequest, "InvalidPartOrder", "The list of parts was not in ascending order. The parts list must be specified in order by part number.")
+		}
+
+		part, ok := upload.parts[completed.PartNumber]
+		if !ok || strings.Trim(completed.ETag, `"`) != part.etag {
+			return s3Response{}, s3Errorf(http.StatusBadRequest, "InvalidPart", "One or more of the specified parts could not be found. The part might not have been uploaded, or the specified entity tag might not have matched the part's entity tag.")
+		}
+
+		if idx < len(body.Parts)-1 && len(part.data) < s3.MinPartSize {
+			return s3Response{}, s3Errorf(http.StatusBadRequest, "EntityTooSmall", "Your proposed upload is smaller than the minimum allowed object size.")
+		}
+
+		data = append(data, part.data...)
+		etags = append(etags, part.etag)
+	}
+
+	etag, err := s3.MultipartETag(etags)
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	bucket.objects[r.key] = &s3Object{data: data, etag: etag, contentType: upload.contentType, modified: time.Now()}
+	delete(bucket.uploads, r.URL.Query().Get("uploadId"))
+
+	type result struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		Xmlns   string   `xml:"xmlns,attr"`
+		Bucket  string   `xml:"Bucket"`
+		Key     string   `xml:"Key"`
+		ETag    string   `xml:"ETag"`
+	}
+
+	return xmlResponse(result{Xmlns: s3Namespace, Bucket: r.bucket, Key: r.key, ETag: `"` + etag + `"`}), nil
+}
+
+func abortS3MultipartUpload(r s3Request) (s3Response, error) {
+	bucket, _, err := r.findUpload()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	delete(bucket.uploads, r.URL.Query().Get("uploadId"))
+
+	return s3Response{status: http.StatusNoContent}, nil
+}
+
+// verifyS3ContentMD5 checks the base64 encoded md5 hash of a Content-MD5 header against the sum of the body.
+func verifyS3ContentMD5(header string, sum [md5.Size]byte) error {
+	expected, err := base64.StdEncoding.DecodeString(header)
+	if err != nil || len(expected) != md5.Size {
+		return s3Errorf(http.StatusBadRequest, "InvalidDigest", "The Content-MD5 you specified was invalid.")
+	}
+
+	if !bytes.Equal(expected, sum[:]) {
+		return s3Errorf(http.StatusBadRequest, "BadDigest", "The Content-MD5 you specified did not match what we received.")
+	}
+
+	return nil
+}
+
+// s3BucketSettingType describes a configuration document of buckets, which is stored as a whole.
+type s3BucketSettingType struct {
+	name        string
+	contentType string
+
+	// parse validates the document and returns it as stored by the bucket
+	parse func(data []byte) ([]byte, error)
+
+	// requireMD5 rejects documents without Content-MD5 header, like S3 does for lifecycle and cors configurations
+	requireMD5 bool
+
+	// missing is returned if the bucket has no such configuration, an empty document is returned otherwise
+	missing *s3Error
+}
+
+var s3BucketSettings = []s3BucketSettingType{
+	{
+		name:        "lifecycle",
+		contentType: "application/xml",
+		parse: func(data []byte) ([]byte, error) {
+			config, err := s3.ParseLifecycleConfiguration(data)
+			return marshalXML(config), err
+		},
+		requireMD5: true,
+		missing:    &s3Error{Status: http.StatusNotFound, Code: "NoSuchLifecycleConfiguration", Message: "The lifecycle configuration does not exist"},
+	},
+	{
+		name:        "versioning",
+		contentType: "application/xml",
+		parse: func(data []byte) ([]byte, error) {
+			config, err := s3.ParseVersioningConfiguration(data)
+			return marshalXML(config), err
+		},
+	},
+	{
+		name:        "policy",
+		contentType: "application/json",
+		parse: func(data []byte) ([]byte, error) {
+			if err := s3.ValidatePolicy(data); err != nil {
+				return nil, s3Errorf(http.StatusBadRequest, "MalformedPolicy", err.Error())
+			}
+
+			return data, nil
+		},
+		missing: &s3Error{Status: http.StatusNotFound, Code: "NoSuchBucketPolicy", Message: "The bucket policy does not exist"},
+	},
+	{
+		name:        "cors",
+		contentType: "application/xml",
+		parse: func(data []byte) ([]byte, error) {
+			config, err := s3.ParseCORSConfiguration(data)
+			return marshalXML(config), err
+		},
+		requireMD5: true,
+		missing:    &s3Error{Status: http.StatusNotFound, Code: "NoSuchCORSConfiguration", Message: "The CORS configuration does not exist"},
+	},
+}
+
+// s3BucketSetting returns the type of the configuration selected by the query or nil if it selects none.
+func s3BucketSetting(query url.Values) *s3BucketSettingType {
+	for idx := range s3BucketSettings {
+		if query.Has(s3BucketSettings[idx].name) {
+			return &s3BucketSettings[idx]
+		}
+	}
+
+	return nil
+}
+
+func putS3BucketSetting(r s3Request) (s3Response, error) {
+	bucket, err := r.findBucket()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	setting := s3BucketSetting(r.URL.Query())
+
+	header := r.Header.Get("Content-MD5")
+	if header == "" && setting.requireMD5 {
+		return s3Response{}, s3Errorf(http.StatusBadRequest, "InvalidRequest", "Missing required header for this request: Content-Md5")
+	}
+
+	if header != "" {
+		if err = verifyS3ContentMD5(header, md5.Sum(r.body)); err != nil {
+			return s3Response{}, err
+		}
+	}
+
+	data, err := setting.parse(r.body)
+	if err != nil {
+		if _, ok := err.(s3Error); !ok {
+			err = s3Errorf(http.StatusBadRequest, "MalformedXML", "The XML you provided was not well-formed or did not validate against our published schema: "+err.Error())
+		}
+
+		return s3Response{}, err
+	}
+
+	bucket.settings[setting.name] = data
+
+	return s3Response{status: http.StatusOK}, nil
+}
+
+func getS3BucketSetting(r s3Request) (s3Response, error) {
+	bucket, err := r.findBucket()
+	if err != nil {
+		return s3Response{}, err
+	}
+
+	setting := s3BucketSetting(r.URL.Query())
+
+	data, ok := bucket.settings[setting.name]
+	if !ok {
+		if setting.missing != nil {
+			return s3Response{}, *setting.missing
+		}
+
+		// versioning is reported without status until it has been enabled
+		data = marshalXML(s3.VersioningConfiguration{})
+	}
+
+	return s3Response{status: http.StatusOK, header: http.Header{"Content-Type": {setting.contentType}}, body: data}, nil
+}
+
+func xmlResponse(body interface{}) s3Response {
+	return s3Response{
+		status: http.StatusOK,
+		header: http.Header{"Content-Type": {"application/xml"}},
+		body:   marshalXML(body),
+	}
+}
+
+func marshalXML(body interface{}) []byte {
+	data, err := xml.Marshal(body)
+	if err != nil {
+		return nil
+	}
+
+	return append([]byte(xml.Header), data...)
+}
+
+func s3Time(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+func sortedKeys[T any](items map[string]T) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,318 @@
+// Package apitest provides an in-memory implementation of the parts of the Cloudbit API used by the CLI. It allows to
+// run scripts and the commands against a sandbox instead of a real organization, e.g. using
+//
+//	server := apitest.NewTestServer(apitest.Options{})
+//	defer server.Close()
+//
+// and passing server.URL as the endpoint of the client. Asynchronous operations, like orders and server actions, are
+// completed after a configurable delay, so waiting for them behaves like against the real API.
+package apitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flowswiss/goclient/common"
+	"github.com/flowswiss/goclient/compute"
+	"github.com/flowswiss/goclient/kubernetes"
+	"github.com/flowswiss/goclient/objectstorage"
+)
+
+const (
+	DefaultOrderDelay  = 5 * time.Second
+	DefaultActionDelay = 2 * time.Second
+)
+
+// Options configure the behaviour of the server.
+type Options struct {
+	// Token is the only authentication token accepted by the server. Any token is accepted if it is empty.
+	Token string
+
+	// OrderDelay is the time orders spend processing before they succeed. Defaults to DefaultOrderDelay.
+	OrderDelay time.Duration
+
+	// ActionDelay is the time actions, like starting a server or expanding a volume, take to complete. Defaults to
+	// DefaultActionDelay.
+	ActionDelay time.Duration
+}
+
+// Server is an http.Handler serving the in-memory API. All state is lost once the server is discarded.
+type Server struct {
+	options Options
+	routes  []route
+
+	mu     sync.Mutex
+	nextID int
+	tasks  []task
+
+	addresses map[int]map[string]bool
+
+	orders     []*common.Order
+	servers    []*compute.Server
+	networks   []*compute.Network
+	volumes    []*compute.Volume
+	keyPairs   []*compute.KeyPair
+	elasticIPs []*compute.ElasticIP
+	clusters   []*kubernetes.Cluster
+	nodes      map[int][]*kubernetes.Node
+
+	objectStorageInstances []*objectstorage.Instance
+	buckets                map[string]map[string]*s3Bucket
+}
+
+// NewServer creates a server with the static entities, like locations, products and images, and no resources.
+func NewServer(options Options) *Server {
+	if options.OrderDelay <= 0 {
+		options.OrderDelay = DefaultOrderDelay
+	}
+
+	if options.ActionDelay <= 0 {
+		options.ActionDelay = DefaultActionDelay
+	}
+
+	s := &Server{
+		options: options,
+		nextID:  1000,
+		nodes:   map[int][]*kubernetes.Node{},
+		buckets: map[string]map[string]*s3Bucket{},
+
+		addresses: map[int]map[string]bool{},
+	}
+
+	s.registerEntityRoutes()
+	s.registerOrderRoutes()
+	s.registerComputeRoutes()
+	s.registerKubernetesRoutes()
+	s.registerObjectStorageRoutes()
+
+	return s
+}
+
+// NewTestServer starts a server listening on a random local port. The caller is responsible for closing it.
+func NewTestServer(options Options) *httptest.Server {
+	return httptest.NewServer(NewServer(options))
+}
+
+// APIError is the error returned for failed requests in the format of the Cloudbit API.
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e APIError) Error() string {
+	return e.Message
+}
+
+func errorf(status int, format string, args ...interface{}) APIError {
+	return APIError{Status: status, Message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(kind string, id int) APIError {
+	return errorf(http.StatusNotFound, "%s with id %d not found", kind, id)
+}
+
+// handler processes a request. The params contain the placeholders of the route in order, the returned value is
+// encoded as json. A nil value results in an empty response with status 204.
+type handler func(r *http.Request, params []string) (int, interface{}, error)
+
+type route struct {
+	method   string
+	segments []string
+	handler  handler
+}
+
+// handle registers the handler for the pattern, where "*" matches any single segment of the path.
+func (s *Server) handle(method string, pattern string, handler handler) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler:  handler,
+	})
+}
+
+func (r route) match(segments []string) ([]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+
+	var params []string
+	for idx, segment := range r.segments {
+		if segment == "*" {
+			params = append(params, segments[idx])
+			continue
+		}
+
+		if segment != segments[idx] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, s3PathPrefix) {
+		s.serveS3(w, r)
+		return
+	}
+
+	if s.options.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.options.Token {
+		writeError(w, errorf(http.StatusUnauthorized, "invalid authentication token"))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.runDueTasks()
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	methodAllowed := true
+
+	for _, route := range s.routes {
+		params, ok := route.match(segments)
+		if !ok {
+			continue
+		}
+
+		if route.method != r.Method {
+			methodAllowed = false
+			continue
+		}
+
+		status, body, err := route.handler(r, params)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, status, body)
+		return
+	}
+
+	if !methodAllowed {
+		writeError(w, errorf(http.StatusMethodNotAllowed, "method %s is not allowed for %s", r.Method, r.URL.Path))
+		return
+	}
+
+	writeError(w, errorf(http.StatusNotFound, "no route found for %s", r.URL.Path))
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	if body == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		writeError(w, errorf(http.StatusInternalServerError, "encode response: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if apiErr, ok := err.(APIError); ok {
+		status = apiErr.Status
+	}
+
+	body := map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    status,
+			"message": map[string]string{"en": err.Error()},
+		},
+	}
+
+	data, _ := json.Marshal(body)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+// list responds with all items at once, as requested by the client using no_filter=1.
+func list[T any](items []*T) (int, interface{}, error) {
+	res := make([]T, len(items))
+	for idx, item := range items {
+		res[idx] = *item
+	}
+
+	return http.StatusOK, res, nil
+}
+
+func decode(r *http.Request, dest interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		return errorf(http.StatusBadRequest, "invalid request body: %v", err)
+	}
+
+	return nil
+}
+
+func parseID(param string) (int, error) {
+	id, err := strconv.Atoi(param)
+	if err != nil {
+		return 0, errorf(http.StatusNotFound, "invalid id %q", param)
+	}
+
+	return id, nil
+}
+
+func find[T any](items []*T, id int, idOf func(*T) int) (*T, int) {
+	for idx, item := range items {
+		if idOf(item) == id {
+			return item, idx
+		}
+	}
+
+	return nil, -1
+}
+
+func remove[T any](items []*T, idx int) []*T {
+	return append(items[:idx], items[idx+1:]...)
+}
+
+func (s *Server) newID() int {
+	s.nextID++
+	return s.nextID
+}
+
+func now() common.Time {
+	return common.Time(time.Now().Truncate(time.Second))
+}
+
+// task is a change of state which is applied once it is due, e.g. the completion of an order.
+type task struct {
+	due time.Time
+	run func()
+}
+
+func (s *Server) schedule(delay time.Duration, run func()) {
+	s.tasks = append(s.tasks, task{due: time.Now().Add(delay), run: run})
+}
+
+// runDueTasks applies all due tasks in the order they became due. Tasks are only run when a request is received, which
+// is indistinguishable from running them in the background for the clients.
+func (s *Server) runDueTasks() {
+	sort.SliceStable(s.tasks, func(i, j int) bool {
+		return s.tasks[i].due.Before(s.tasks[j].due)
+	})
+
+	current := time.Now()
+	for len(s.tasks) != 0 && !s.tasks[0].due.After(current) {
+		next := s.tasks[0]
+		s.tasks = s.tasks[1:]
+		next.run()
+	}
+}
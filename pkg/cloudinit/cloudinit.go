@@ -0,0 +1,257 @@
+// Package cloudinit validates and composes the user data passed to cloud-init when creating a server. Parts can be
+// rendered as Go template before validation, which allows to reuse a file for multiple servers, e.g.
+//
+//	#cloud-config
+//	hostname: {{ .Name }}
+//	write_files:
+//	  - path: /etc/environment
+//	    content: STAGE={{ .Vars.stage }}
+//
+// Parts using the jinja templating of cloud-init itself are passed as is.
+package cloudinit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultMaxSize is the default limit of the base64 encoded user data. The api does not document a limit of its own, so
+// the limit of the user data of OpenStack compute instances is used.
+const DefaultMaxSize = 65535
+
+const (
+	headerCloudConfig = "#cloud-config"
+	headerJinja       = "## template: jinja"
+	headerMultipart   = "Content-Type: multipart/"
+	headerMIME        = "MIME-Version:"
+)
+
+// contentTypes maps the first line of a part to its mime type, as documented in
+// https://cloudinit.readthedocs.io/en/latest/explanation/format.html
+var contentTypes = []struct{ prefix, contentType string }{
+	{"#cloud-config-archive", "text/cloud-config-archive"},
+	{headerCloudConfig, "text/cloud-config"},
+	{"#cloud-boothook", "text/cloud-boothook"},
+	{"#include", "text/x-include-url"},
+	{"#part-handler", "text/part-handler"},
+	{headerJinja, "text/jinja2"},
+	{"#!", "text/x-shellscript"},
+}
+
+// TemplateData is available to the templates of all parts.
+type TemplateData struct {
+	Name     string
+	Location string
+	Vars     map[string]string
+}
+
+// Part is a single file of the user data.
+type Part struct {
+	Name    string
+	Content []byte
+
+	// Template renders the content as Go template. It is opt-in, since scripts often contain braces themselves, e.g.
+	// in the format of docker commands.
+	Template bool
+}
+
+// Build renders, validates and combines the parts into the base64 encoded user data of a server. Multiple parts are
+// combined into a mime multipart message. The encoded user data must not exceed maxSize bytes, which defaults to
+// DefaultMaxSize if it is zero or less.
+func Build(parts []Part, data TemplateData, maxSize int) (string, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+
+	rendered := make([]Part, len(parts))
+	for idx, part := range parts {
+		content, err := render(part, data)
+		if err != nil {
+			return "", err
+		}
+
+		if err = Validate(content); err != nil {
+			return "", fmt.Errorf("%s: %w", part.Name, err)
+		}
+
+		rendered[idx] = Part{Name: part.Name, Content: content}
+	}
+
+	userData, err := Combine(rendered)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(userData)
+	if len(encoded) > maxSize {
+		return "", fmt.Errorf("user data is %d bytes after base64 encoding, which exceeds the limit of %d bytes", len(encoded), maxSize)
+	}
+
+	return encoded, nil
+}
+
+func render(part Part, data TemplateData) ([]byte, error) {
+	if !part.Template || hasHeader(part.Content, headerJinja) {
+		return part.Content, nil
+	}
+
+	tmpl, err := template.New(part.Name).Option("missingkey=error").Parse(string(part.Content))
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err = tmpl.Execute(buf, data); err != nil {
+		return nil, fmt.Errorf("render template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate checks that the user data is either cloud-config yaml, a mime multipart message or another format supported
+// by cloud-init, like a shell script.
+func Validate(content []byte) error {
+	switch {
+	case len(bytes.TrimSpace(content)) == 0:
+		return fmt.Errorf("user data is empty")
+	case hasHeader(content, headerCloudConfig+"-archive"):
+		return nil
+	case hasHeader(content, headerCloudConfig):
+		return validateCloudConfig(content)
+	case isMultipart(content):
+		return validateMultipart(content)
+	case ContentType(content) != "":
+		return nil
+	}
+
+	return fmt.Errorf("unknown user data format, expected %s yaml, a script starting with #! or a mime multipart message", headerCloudConfig)
+}
+
+func validateCloudConfig(content []byte) error {
+	var config interface{}
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return fmt.Errorf("invalid cloud config: %w", err)
+	}
+
+	if _, ok := config.(map[string]interface{}); !ok && config != nil {
+		return fmt.Errorf("invalid cloud config: expected a mapping at the top level")
+	}
+
+	return nil
+}
+
+func validateMultipart(content []byte) error {
+	msg, err := mail.ReadMessage(bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("invalid mime message: %w", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("invalid mime message: %w", err)
+	}
+
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return fmt.Errorf("invalid mime message: expected multipart content, got %s", mediaType)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for idx := 1; ; idx++ {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("invalid mime message: %w", err)
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return fmt.Errorf("invalid mime message: part %d: %w", idx, err)
+		}
+
+		if strings.HasPrefix(part.Header.Get("Content-Type"), "text/cloud-config") {
+			if err = validateCloudConfig(data); err != nil {
+				return fmt.Errorf("part %d: %w", idx, err)
+			}
+		}
+	}
+}
+
+// Combine joins the parts into a mime multipart message. A single part is returned as is.
+func Combine(parts []Part) ([]byte, error) {
+	if len(parts) == 1 {
+		return parts[0].Content, nil
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for _, part := range parts {
+		contentType := ContentType(part.Content)
+		if contentType == "" {
+			return nil, fmt.Errorf("%s: a mime multipart message cannot be combined with other parts", part.Name)
+		}
+
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", fmt.Sprintf("%s; charset=\"utf-8\"", contentType))
+		header.Set("Content-Transfer-Encoding", "7bit")
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": part.Name}))
+
+		w, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = w.Write(part.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "Content-Type: multipart/mixed; boundary=\"%s\"\r\n", writer.Boundary())
+	fmt.Fprint(buf, "MIME-Version: 1.0\r\n\r\n")
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+// ContentType returns the mime type of a part based on its first line, or an empty string if the part is a multipart
+// message or of unknown format.
+func ContentType(content []byte) string {
+	if isMultipart(content) {
+		return ""
+	}
+
+	for _, candidate := range contentTypes {
+		if hasHeader(content, candidate.prefix) {
+			return candidate.contentType
+		}
+	}
+
+	return ""
+}
+
+func isMultipart(content []byte) bool {
+	return hasHeader(content, headerMultipart) || hasHeader(content, headerMIME)
+}
+
+func hasHeader(content []byte, prefix string) bool {
+	line, _, _ := bytes.Cut(content, []byte("\n"))
+	return bytes.HasPrefix(bytes.TrimSpace(line), []byte(prefix))
+}
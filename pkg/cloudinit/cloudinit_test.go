@@ -0,0 +1,242 @@
+package cloudinit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+const (
+	testCloudConfig = "#cloud-config\npackages:\n  - nginx\n"
+	testScript      = "#!/bin/sh\necho ready\n"
+	testMultipart   = "Content-Type: multipart/mixed; boundary=\"b\"\r\nMIME-Version: 1.0\r\n\r\n" +
+		"--b\r\nContent-Type: text/x-shellscript\r\n\r\n#!/bin/sh\r\n--b--\r\n"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{name: "cloud config", content: testCloudConfig},
+		{name: "empty cloud config", content: "#cloud-config\n"},
+		{name: "cloud config list", content: "#cloud-config\n- nginx\n", err: "expected a mapping at the top level"},
+		{name: "cloud config scalar", content: "#cloud-config\nnginx\n", err: "expected a mapping at the top level"},
+		{name: "invalid cloud config", content: "#cloud-config\npackages: [nginx\n", err: "invalid cloud config"},
+		{name: "cloud config archive", content: "#cloud-config-archive\n- type: text/cloud-config\n"},
+		{name: "script", content: testScript},
+		{name: "jinja", content: "## template: jinja\n#cloud-config\nhostname: {{ v1.local_hostname }}\n"},
+		{name: "multipart", content: testMultipart},
+		{name: "empty", content: " \n", err: "user data is empty"},
+		{name: "unknown format", content: "packages:\n  - nginx\n", err: "unknown user data format"},
+		{
+			name:    "multipart without boundary",
+			content: "Content-Type: multipart/mixed\r\nMIME-Version: 1.0\r\n\r\n--b\r\n\r\n#!/bin/sh\r\n--b--\r\n",
+			err:     "invalid mime message",
+		},
+		{
+			name:    "truncated multipart",
+			content: "Content-Type: multipart/mixed; boundary=\"b\"\r\nMIME-Version: 1.0\r\n\r\n--b\r\nContent-Type: text/x-shellscript\r\n\r\n#!/bin/sh\r\n",
+			err:     "invalid mime message",
+		},
+		{
+			name:    "multipart of other content",
+			content: "MIME-Version: 1.0\r\nContent-Type: text/plain\r\n\r\nhello\r\n",
+			err:     "expected multipart content, got text/plain",
+		},
+		{
+			name: "multipart with invalid cloud config",
+			content: "Content-Type: multipart/mixed; boundary=\"b\"\r\nMIME-Version: 1.0\r\n\r\n" +
+				"--b\r\nContent-Type: text/x-shellscript\r\n\r\n#!/bin/sh\r\n" +
+				"--b\r\nContent-Type: text/cloud-config\r\n\r\n#cloud-config\r\n- nginx\r\n--b--\r\n",
+			err: "part 2: invalid cloud config",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkError(t, Validate([]byte(test.content)), test.err)
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		parts   []Part
+		maxSize int
+		result  string
+		err     string
+	}{
+		{
+			name:   "single part",
+			parts:  []Part{{Name: "setup.sh", Content: []byte(testScript)}},
+			result: testScript,
+		},
+		{
+			name:   "template",
+			parts:  []Part{{Name: "base.yaml", Content: []byte("#cloud-config\nhostname: {{ .Name }}-{{ .Vars.stage }}\n"), Template: true}},
+			result: "#cloud-config\nhostname: web-production\n",
+		},
+		{
+			name:   "braces without template",
+			parts:  []Part{{Name: "setup.sh", Content: []byte("#!/bin/sh\ndocker ps --format '{{ .Names }}'\n")}},
+			result: "#!/bin/sh\ndocker ps --format '{{ .Names }}'\n",
+		},
+		{
+			name:   "jinja template",
+			parts:  []Part{{Name: "base.yaml", Content: []byte("## template: jinja\n#cloud-config\nhostname: {{ v1.local_hostname }}\n"), Template: true}},
+			result: "## template: jinja\n#cloud-config\nhostname: {{ v1.local_hostname }}\n",
+		},
+		{
+			name:  "unknown variable",
+			parts: []Part{{Name: "base.yaml", Content: []byte("#cloud-config\nhostname: {{ .Vars.region }}\n"), Template: true}},
+			err:   `map has no entry for key "region"`,
+		},
+		{
+			name:  "invalid template",
+			parts: []Part{{Name: "base.yaml", Content: []byte("#cloud-config\nhostname: {{ .Name }\n"), Template: true}},
+			err:   "parse template",
+		},
+		{
+			name:  "invalid part",
+			parts: []Part{{Name: "base.yaml", Content: []byte("#cloud-config\n- nginx\n")}},
+			err:   "base.yaml: invalid cloud config",
+		},
+		{
+			name:  "default size limit",
+			parts: []Part{{Name: "setup.sh", Content: []byte("#!/bin/sh\n" + strings.Repeat("#", DefaultMaxSize))}},
+			err:   "which exceeds the limit of 65535 bytes",
+		},
+		{
+			name:    "configured size limit",
+			parts:   []Part{{Name: "setup.sh", Content: []byte(testScript)}},
+			maxSize: 16,
+			err:     "user data is 28 bytes after base64 encoding, which exceeds the limit of 16 bytes",
+		},
+		{
+			name:    "raised size limit",
+			parts:   []Part{{Name: "setup.sh", Content: []byte("#!/bin/sh\n" + strings.Repeat("#", DefaultMaxSize))}},
+			maxSize: 2 * DefaultMaxSize,
+			result:  "#!/bin/sh\n" + strings.Repeat("#", DefaultMaxSize),
+		},
+	}
+
+	data := TemplateData{Name: "web", Location: "ALP1", Vars: map[string]string{"stage": "production"}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := Build(test.parts, data, test.maxSize)
+			checkError(t, err, test.err)
+			if err != nil {
+				return
+			}
+
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(decoded) != test.result {
+				t.Errorf("expected %q, got %q", test.result, decoded)
+			}
+		})
+	}
+}
+
+func TestBuildMultipart(t *testing.T) {
+	parts := []Part{
+		{Name: "setup.sh", Content: []byte(testScript)},
+		{Name: "base.yaml", Content: []byte("#cloud-config\nhostname: {{ .Name }}\n"), Template: true},
+	}
+
+	encoded, err := Build(parts, TemplateData{Name: "web"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userData, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = Validate(userData); err != nil {
+		t.Fatalf("expected the combined user data to be valid: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(userData))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct{ contentType, filename, content string }{
+		{contentType: `text/x-shellscript; charset="utf-8"`, filename: "setup.sh", content: testScript},
+		{contentType: `text/cloud-config; charset="utf-8"`, filename: "base.yaml", content: "#cloud-config\nhostname: web\n"},
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for _, part := range expected {
+		actual, err := reader.NextPart()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := io.ReadAll(actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actual.Header.Get("Content-Type") != part.contentType || actual.FileName() != part.filename || string(content) != part.content {
+			t.Errorf("expected part %s of type %s with %q, got %s of type %s with %q", part.filename, part.contentType, part.content,
+				actual.FileName(), actual.Header.Get("Content-Type"), content)
+		}
+	}
+
+	if _, err = reader.NextPart(); err != io.EOF {
+		t.Errorf("expected %d parts, got more: %v", len(expected), err)
+	}
+}
+
+func TestCombineRejectsMultipart(t *testing.T) {
+	parts := []Part{
+		{Name: "setup.sh", Content: []byte(testScript)},
+		{Name: "combined.mime", Content: []byte(testMultipart)},
+	}
+
+	_, err := Combine(parts)
+	checkError(t, err, "combined.mime: a mime multipart message cannot be combined with other parts")
+
+	// a single multipart message is passed as is
+	combined, err := Combine(parts[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(combined) != testMultipart {
+		t.Errorf("expected the message to be unchanged, got %q", combined)
+	}
+}
+
+func checkError(t *testing.T, err error, expected string) {
+	t.Helper()
+
+	switch {
+	case expected == "" && err != nil:
+		t.Errorf("unexpected error: %v", err)
+	case expected != "" && err == nil:
+		t.Errorf("expected error containing %q", expected)
+	case expected != "" && !strings.Contains(err.Error(), expected):
+		t.Errorf("expected error containing %q, got %v", expected, err)
+	}
+}
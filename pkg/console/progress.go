@@ -1,6 +1,8 @@
 package console
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
@@ -9,6 +11,13 @@ import (
 
 type Progress struct {
 	message string
+	mu      sync.Mutex
+
+	// total is the number of bytes to transfer, which enables the progress bar. current is the number of bytes
+	// transferred since start.
+	total   int64
+	current int64
+	start   time.Time
 
 	done chan struct{}
 	wg   sync.WaitGroup
@@ -22,6 +31,78 @@ func NewProgress(message string) *Progress {
 	}
 }
 
+// NewByteProgress creates a progress, which shows a bar of the transferred bytes of total after the message.
+func NewByteProgress(message string, total int64) *Progress {
+	progress := NewProgress(message)
+	progress.total = total
+	progress.start = time.Now()
+
+	return progress
+}
+
+// Add adds n bytes to the transferred bytes of a progress created by NewByteProgress.
+func (p *Progress) Add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current += n
+}
+
+// SetMessage replaces the message of the progress. On plain writers, the new message is printed on a new line.
+func (p *Progress) SetMessage(message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.message = message
+}
+
+func (p *Progress) currentMessage() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.total <= 0 {
+		return p.message
+	}
+
+	return fmt.Sprintf("%s %s", p.message, p.bar(p.percent()))
+}
+
+// plainMessage returns the message with the progress rounded to steps of ten percent, so plain writers only print a
+// new line for every step instead of every change.
+func (p *Progress) plainMessage() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.total <= 0 {
+		return p.message
+	}
+
+	return fmt.Sprintf("%s %d%%", p.message, p.percent()/10*10)
+}
+
+func (p *Progress) percent() int {
+	if p.current >= p.total {
+		return 100
+	}
+
+	return int(p.current * 100 / p.total)
+}
+
+// bar renders the transferred bytes, e.g. [#####     ] 1.2 GiB / 2.4 GiB 50% 12.3 MiB/s.
+func (p *Progress) bar(percent int) string {
+	const width = 20
+
+	filled := percent * width / 100
+	bar := strings.Repeat("#", filled) + strings.Repeat(" ", width-filled)
+
+	rate := ""
+	if elapsed := time.Since(p.start).Seconds(); elapsed >= 1 {
+		rate = fmt.Sprintf(" %s/s", FormatBytes(int64(float64(p.current)/elapsed)))
+	}
+
+	return fmt.Sprintf("[%s] %s / %s %d%%%s", bar, FormatBytes(p.current), FormatBytes(p.total), percent, rate)
+}
+
 func (p *Progress) Done() {
 	close(p.done)
 	p.wg.Wait()
@@ -37,7 +118,7 @@ func (p *Progress) displayAnsi(out Writer) {
 	out.Print("\u001B[s") // save current cursor position
 	for {
 		out.Print("\u001B[u\u001B[0K") // restore cursor position and clear line
-		out.Printf("[%s] %s\n", string(chars[idx]), p.message)
+		out.Printf("[%s] %s\n", string(chars[idx]), p.currentMessage())
 		idx = (idx + 1) % len(chars)
 
 		select {
@@ -56,7 +137,26 @@ func (p *Progress) Display(out Writer) {
 	if _, ok := out.(ansiWriter); ok {
 		p.displayAnsi(out)
 	} else {
-		out.Printf("%s\n", p.message)
-		<-p.done
+		p.displayPlain(out)
+	}
+}
+
+func (p *Progress) displayPlain(out Writer) {
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+
+	message := p.plainMessage()
+	out.Printf("%s\n", message)
+
+	for {
+		select {
+		case <-ticker.C:
+			if current := p.plainMessage(); current != message {
+				message = current
+				out.Printf("%s\n", message)
+			}
+		case <-p.done:
+			return
+		}
 	}
 }
@@ -2,6 +2,8 @@ package console
 
 import (
 	"bufio"
+	"errors"
+	"io"
 	"os"
 	"strings"
 
@@ -109,3 +111,13 @@ func Password(writer Writer, prompt string, valid func(string) error) (string, e
 		writer.Errorf("%v\n", err)
 	}
 }
+
+// ReadLine reads a single line from stdin without prompting, e.g. to receive secrets piped into the application.
+func ReadLine() (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || len(line) == 0) {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
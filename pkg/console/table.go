@@ -1,9 +1,11 @@
 package console
 
 import (
+	"encoding/csv"
 	"fmt"
 	"reflect"
 	"strings"
+	"unicode/utf8"
 )
 
 type Displayable interface {
@@ -11,21 +13,57 @@ type Displayable interface {
 	Values() map[string]interface{}
 }
 
+// WideDisplayable is implemented by displayables which provide additional columns for the wide output. The values of
+// the additional columns have to be included in Values.
+type WideDisplayable interface {
+	Displayable
+	WideColumns() []string
+}
+
 type Column struct {
 	Index int
 	Name  string
 	Width int
 }
 
+// CustomColumn renders a column from the raw item instead of the values of a Displayable.
+type CustomColumn struct {
+	Name  string
+	Value func(item interface{}) (string, error)
+}
+
 type Table struct {
 	Columns []*Column
 	Rows    [][]string
-}
 
-func (c *Column) format() string {
-	return fmt.Sprintf("%%%ds", -c.Width)
+	// Wide enables the additional columns of WideDisplayable values.
+	Wide bool
+	// Select restricts the table to the given columns in the given order.
+	Select []string
+	// Custom replaces the columns of the items with custom columns.
+	Custom []CustomColumn
+	// NoHeaders omits the header line.
+	NoHeaders bool
+	// Overflow defines how cells are handled which do not fit into the maximum width.
+	Overflow Overflow
+	// MaxWidth is the maximum width of the table. If zero, the width of the terminal is used.
+	MaxWidth int
 }
 
+// Overflow defines how a pretty table is fitted into the terminal width.
+type Overflow int
+
+const (
+	// OverflowTruncate cuts cells which are too long and marks them with an ellipsis.
+	OverflowTruncate Overflow = iota
+	// OverflowWrap continues cells which are too long on the next line.
+	OverflowWrap
+	// OverflowNone never limits the width of the table.
+	OverflowNone
+)
+
+const minColumnWidth = 5
+
 func (t *Table) FindColumn(name string) *Column {
 	for _, col := range t.Columns {
 		if col.Name == name {
@@ -40,7 +78,7 @@ func (t *Table) insertColumns(cols []string) {
 		t.Columns = append(t.Columns, &Column{
 			Index: idx,
 			Name:  col,
-			Width: len(col),
+			Width: StringWidth(col),
 		})
 	}
 }
@@ -57,49 +95,157 @@ func (t *Table) insertRow(data map[string]interface{}) {
 		str := fmt.Sprintf("%+v", val)
 		row[col.Index] = str
 
-		if len(str) > col.Width {
-			col.Width = len(str)
+		for _, line := range strings.Split(str, "\n") {
+			if width := StringWidth(line); width > col.Width {
+				col.Width = width
+			}
 		}
 	}
 
 	t.Rows = append(t.Rows, row)
 }
 
+// Format writes the table to the writer. In pretty mode, the columns are aligned and fitted into the terminal width
+// according to the overflow mode. Otherwise, the table is written as RFC 4180 CSV using the separator.
 func (t *Table) Format(out Writer, separator string, pretty bool) {
-	format := "%s"
-	for idx, col := range t.Columns {
-		if pretty {
-			format = col.format()
-		}
+	if !pretty {
+		t.formatCSV(out, separator)
+		return
+	}
 
-		out.Bold().Printf(format, strings.ToUpper(col.Name)).Reset()
+	widths := t.fitColumns(TerminalWidth(out), StringWidth(separator))
 
-		if (idx + 1) < len(t.Columns) {
-			out.Print(separator)
+	if !t.NoHeaders && len(t.Columns) != 0 {
+		header := make([]string, len(t.Columns))
+		for idx, col := range t.Columns {
+			header[idx] = strings.ToUpper(col.Name)
 		}
-	}
 
-	out.Println()
+		out.Bold()
+		t.formatLine(out, separator, widths, header)
+		out.Reset()
+		out.Println()
+	}
 
 	for _, row := range t.Rows {
+		cells := make([][]string, len(row))
+		lines := 1
+
 		for idx, val := range row {
-			if pretty {
-				format = t.Columns[idx].format()
+			if t.Overflow == OverflowWrap {
+				cells[idx] = wrap(val, widths[idx])
+			} else {
+				cells[idx] = []string{truncate(strings.ReplaceAll(val, "\n", " "), widths[idx])}
 			}
 
-			if !pretty && strings.Contains(val, separator) {
-				val = fmt.Sprintf("\"%s\"", strings.ReplaceAll(val, "\"", "\"\""))
+			if len(cells[idx]) > lines {
+				lines = len(cells[idx])
 			}
+		}
+
+		for line := 0; line < lines; line++ {
+			values := make([]string, len(row))
+			for idx := range row {
+				if line < len(cells[idx]) {
+					values[idx] = cells[idx][line]
+				}
+			}
+
+			t.formatLine(out, separator, widths, values)
+			out.Println()
+		}
+	}
+}
+
+func (t *Table) formatLine(out Writer, separator string, widths []int, values []string) {
+	line := &strings.Builder{}
+	for idx, val := range values {
+		line.WriteString(pad(val, widths[idx]))
+
+		if idx+1 < len(values) {
+			line.WriteString(separator)
+		}
+	}
+
+	out.Print(strings.TrimRight(line.String(), " "))
+}
+
+func (t *Table) formatCSV(out Writer, separator string) {
+	writer := csv.NewWriter(out)
+	if comma, _ := utf8.DecodeRuneInString(separator); comma != utf8.RuneError {
+		writer.Comma = comma
+	}
+
+	if !t.NoHeaders && len(t.Columns) != 0 {
+		header := make([]string, len(t.Columns))
+		for idx, col := range t.Columns {
+			header[idx] = strings.ToUpper(col.Name)
+		}
+
+		_ = writer.Write(header)
+	}
+
+	for _, row := range t.Rows {
+		_ = writer.Write(row)
+	}
+
+	writer.Flush()
+}
 
-			out.Printf(format, val)
+// fitColumns calculates the width of every column. If the table is wider than the maximum width, the widest columns
+// are shrunk until the table fits, but never below the width of their header.
+func (t *Table) fitColumns(terminalWidth int, separatorWidth int) []int {
+	widths := make([]int, len(t.Columns))
+	minimums := make([]int, len(t.Columns))
+	total := 0
 
-			if (idx + 1) < len(row) {
-				out.Print(separator)
+	for idx, col := range t.Columns {
+		widths[idx] = col.Width
+
+		// without wrapping, line breaks within cells are replaced with spaces
+		if t.Overflow != OverflowWrap {
+			for _, row := range t.Rows {
+				if width := StringWidth(strings.ReplaceAll(row[idx], "\n", " ")); width > widths[idx] {
+					widths[idx] = width
+				}
 			}
 		}
 
-		out.Println()
+		minimums[idx] = StringWidth(col.Name)
+		if minimums[idx] < minColumnWidth {
+			minimums[idx] = minColumnWidth
+		}
+
+		total += widths[idx]
+	}
+
+	maxWidth := t.MaxWidth
+	if maxWidth == 0 {
+		maxWidth = terminalWidth
 	}
+
+	if t.Overflow == OverflowNone || maxWidth <= 0 || len(widths) == 0 {
+		return widths
+	}
+
+	available := maxWidth - separatorWidth*(len(widths)-1)
+	for total > available {
+		widest := -1
+		for idx := range widths {
+			if widths[idx] > minimums[idx] && (widest == -1 || widths[idx] > widths[widest]) {
+				widest = idx
+			}
+		}
+
+		if widest == -1 {
+			break
+		}
+
+		widths[widest]--
+		total--
+	}
+
+	return widths
 }
 
 func (t *Table) insertMap(value reflect.Value) error {
@@ -130,7 +276,12 @@ func (t *Table) insertStruct(value reflect.Value) error {
 	displayable := value.Interface().(Displayable)
 
 	if t.Columns == nil {
-		t.insertColumns(displayable.Columns())
+		columns, err := t.selectColumns(displayable)
+		if err != nil {
+			return err
+		}
+
+		t.insertColumns(columns)
 	}
 
 	valuesFunc := value.MethodByName("Values")
@@ -138,6 +289,68 @@ func (t *Table) insertStruct(value reflect.Value) error {
 	return t.insertMap(values)
 }
 
+// selectColumns determines the columns of the table, which are either the selected or the default columns.
+func (t *Table) selectColumns(displayable Displayable) ([]string, error) {
+	columns := displayable.Columns()
+	wide, ok := displayable.(WideDisplayable)
+
+	if ok && t.Wide {
+		columns = append(columns, wide.WideColumns()...)
+	}
+
+	if len(t.Select) == 0 {
+		return columns, nil
+	}
+
+	available := displayable.Columns()
+	if ok {
+		available = append(available, wide.WideColumns()...)
+	}
+
+	selected := make([]string, 0, len(t.Select))
+	for _, name := range t.Select {
+		found := false
+
+		for _, col := range available {
+			if strings.EqualFold(strings.TrimSpace(name), col) {
+				selected = append(selected, col)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unknown column %q, available columns: %s", name, strings.Join(available, ", "))
+		}
+	}
+
+	return selected, nil
+}
+
+func (t *Table) insertCustom(value reflect.Value) error {
+	if t.Columns == nil {
+		cols := make([]string, len(t.Custom))
+		for idx, col := range t.Custom {
+			cols[idx] = col.Name
+		}
+
+		t.insertColumns(cols)
+	}
+
+	row := make(map[string]interface{}, len(t.Custom))
+	for _, col := range t.Custom {
+		val, err := col.Value(value.Interface())
+		if err != nil {
+			return fmt.Errorf("column %s: %w", col.Name, err)
+		}
+
+		row[col.Name] = val
+	}
+
+	t.insertRow(row)
+	return nil
+}
+
 func (t *Table) insertValue(value reflect.Value) error {
 	switch value.Kind() {
 	case reflect.Array:
@@ -150,11 +363,15 @@ func (t *Table) insertValue(value reflect.Value) error {
 			}
 		}
 		return nil
-	case reflect.Map:
-		return t.insertMap(value)
-	case reflect.Ptr:
-		fallthrough
-	case reflect.Struct:
+	case reflect.Map, reflect.Ptr, reflect.Struct:
+		if len(t.Custom) != 0 {
+			return t.insertCustom(value)
+		}
+
+		if value.Kind() == reflect.Map {
+			return t.insertMap(value)
+		}
+
 		return t.insertStruct(value)
 	}
 
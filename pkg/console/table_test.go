@@ -0,0 +1,87 @@
+package console
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		separator string
+		rows      [][]string
+		result    string
+	}{
+		{
+			name:      "plain",
+			separator: ",",
+			rows:      [][]string{{"web", "running"}},
+			result:    "NAME,STATUS\nweb,running\n",
+		},
+		{
+			name:      "embedded newline",
+			separator: ",",
+			rows:      [][]string{{"web", "line one\nline two"}},
+			result:    "NAME,STATUS\nweb,\"line one\nline two\"\n",
+		},
+		{
+			name:      "separator and quotes",
+			separator: ",",
+			rows:      [][]string{{"web, db", `say "hi"`}},
+			result:    "NAME,STATUS\n\"web, db\",\"say \"\"hi\"\"\"\n",
+		},
+		{
+			name:      "tab separator",
+			separator: "\t",
+			rows:      [][]string{{"web, db", "a\tb"}},
+			result:    "NAME\tSTATUS\nweb, db\t\"a\tb\"\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := &Table{}
+			table.insertColumns([]string{"name", "status"})
+			table.Rows = test.rows
+
+			file, err := os.Create(filepath.Join(t.TempDir(), "out.csv"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			table.Format(plainWriter{File: file}, test.separator, false)
+
+			data, err := os.ReadFile(file.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(data) != test.result {
+				t.Fatalf("expected %q, got %q", test.result, string(data))
+			}
+
+			// the output has to be readable by csv parsers, including cells spanning multiple lines
+			reader := csv.NewReader(strings.NewReader(string(data)))
+			reader.Comma = []rune(test.separator)[0]
+
+			records, err := reader.ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(records) != len(test.rows)+1 {
+				t.Fatalf("expected %d records, got %d", len(test.rows)+1, len(records))
+			}
+
+			for idx, row := range test.rows {
+				if strings.Join(records[idx+1], "|") != strings.Join(row, "|") {
+					t.Errorf("expected record %q, got %q", row, records[idx+1])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package console
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/term"
+)
+
+// wideRanges contains the east asian wide and fullwidth characters, which occupy two cells in a terminal.
+var wideRanges = []struct{ from, to rune }{
+	{0x1100, 0x115F},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F},
+	{0x1F900, 0x1F9FF},
+	{0x20000, 0x3FFFD},
+}
+
+// RuneWidth returns the number of terminal cells occupied by the rune.
+func RuneWidth(r rune) int {
+	if r == 0 || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r) {
+		return 0
+	}
+
+	if !unicode.IsPrint(r) && r != ' ' {
+		return 0
+	}
+
+	for _, rng := range wideRanges {
+		if r >= rng.from && r <= rng.to {
+			return 2
+		}
+	}
+
+	return 1
+}
+
+// StringWidth returns the number of terminal cells occupied by the string.
+func StringWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += RuneWidth(r)
+	}
+
+	return width
+}
+
+// pad appends spaces to the string until it occupies the given number of cells.
+func pad(s string, width int) string {
+	missing := width - StringWidth(s)
+	if missing <= 0 {
+		return s
+	}
+
+	return s + strings.Repeat(" ", missing)
+}
+
+// truncate shortens the string to the given number of cells, marking the truncation with an ellipsis.
+func truncate(s string, width int) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+
+	if width <= 0 {
+		return ""
+	}
+
+	buf := &strings.Builder{}
+	used := 0
+
+	for _, r := range s {
+		w := RuneWidth(r)
+		if used+w > width-1 {
+			break
+		}
+
+		buf.WriteRune(r)
+		used += w
+	}
+
+	buf.WriteRune('…')
+	return buf.String()
+}
+
+// wrap splits the string into lines of at most the given number of cells. Lines are broken at spaces where possible.
+func wrap(s string, width int) []string {
+	var lines []string
+
+	for _, paragraph := range strings.Split(s, "\n") {
+		if width <= 0 || StringWidth(paragraph) <= width {
+			lines = append(lines, paragraph)
+			continue
+		}
+
+		var (
+			line      []rune
+			lineWidth int
+		)
+
+		for _, r := range paragraph {
+			w := RuneWidth(r)
+
+			if lineWidth+w > width {
+				rest := []rune{}
+
+				// move the last word to the next line if the line contains a space to break at
+				if idx := lastSpace(line); idx > 0 && r != ' ' {
+					rest = append(rest, line[idx+1:]...)
+					line = line[:idx]
+				}
+
+				lines = append(lines, strings.TrimRight(string(line), " "))
+				line = rest
+				lineWidth = StringWidth(string(rest))
+
+				if lineWidth+w > width {
+					lines = append(lines, string(line))
+					line = nil
+					lineWidth = 0
+				}
+
+				if r == ' ' && len(line) == 0 {
+					continue
+				}
+			}
+
+			line = append(line, r)
+			lineWidth += w
+		}
+
+		lines = append(lines, string(line))
+	}
+
+	return lines
+}
+
+func lastSpace(line []rune) int {
+	for i := len(line) - 1; i >= 0; i-- {
+		if line[i] == ' ' {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// TerminalWidth returns the width of the terminal the writer is connected to or zero if the writer is not a terminal.
+func TerminalWidth(out Writer) int {
+	file, ok := out.(interface{ Fd() uintptr })
+	if !ok || !term.IsTerminal(int(file.Fd())) {
+		return 0
+	}
+
+	width, _, err := term.GetSize(int(file.Fd()))
+	if err != nil {
+		return 0
+	}
+
+	return width
+}
@@ -0,0 +1,152 @@
+package console
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuneWidth(t *testing.T) {
+	tests := []struct {
+		r     rune
+		width int
+	}{
+		{r: 'a', width: 1},
+		{r: ' ', width: 1},
+		{r: 'é', width: 1},
+		{r: '中', width: 2},
+		{r: '한', width: 2},
+		{r: 'Ａ', width: 2},
+		{r: '😀', width: 2},
+		{r: '\u0301', width: 0}, // combining acute accent
+		{r: '\u20dd', width: 0}, // combining enclosing circle
+		{r: '\u200d', width: 0}, // zero width joiner
+		{r: '\n', width: 0},
+		{r: 0, width: 0},
+	}
+
+	for _, test := range tests {
+		if width := RuneWidth(test.r); width != test.width {
+			t.Errorf("RuneWidth(%U): expected %d, got %d", test.r, test.width, width)
+		}
+	}
+}
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+	}{
+		{s: "", width: 0},
+		{s: "hello", width: 5},
+		{s: "été", width: 3},
+		{s: "中文", width: 4},
+		{s: "a😀b", width: 4},
+		{s: "e\u0301te\u0301", width: 3},
+		{s: "👍\u200d👍", width: 4},
+	}
+
+	for _, test := range tests {
+		if width := StringWidth(test.s); width != test.width {
+			t.Errorf("StringWidth(%q): expected %d, got %d", test.s, test.width, width)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	tests := []struct {
+		s      string
+		width  int
+		result string
+	}{
+		{s: "abc", width: 5, result: "abc  "},
+		{s: "abc", width: 2, result: "abc"},
+		{s: "中", width: 4, result: "中  "},
+		{s: "é", width: 2, result: "é "},
+	}
+
+	for _, test := range tests {
+		if result := pad(test.s, test.width); result != test.result {
+			t.Errorf("pad(%q, %d): expected %q, got %q", test.s, test.width, test.result, result)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s      string
+		width  int
+		result string
+	}{
+		{s: "hello", width: 5, result: "hello"},
+		{s: "hello world", width: 5, result: "hell…"},
+		{s: "hello", width: 1, result: "…"},
+		{s: "hello", width: 0, result: ""},
+		{s: "中文字", width: 6, result: "中文字"},
+		{s: "中文字", width: 5, result: "中文…"},
+		{s: "中文字", width: 4, result: "中…"},
+		{s: "ééé", width: 2, result: "é…"},
+		{s: "😀😀", width: 3, result: "😀…"},
+		{s: "e\u0301e\u0301e\u0301", width: 2, result: "e\u0301…"},
+	}
+
+	for _, test := range tests {
+		result := truncate(test.s, test.width)
+		if result != test.result {
+			t.Errorf("truncate(%q, %d): expected %q, got %q", test.s, test.width, test.result, result)
+		}
+
+		if width := StringWidth(result); width > test.width {
+			t.Errorf("truncate(%q, %d): result %q is %d cells wide", test.s, test.width, result, width)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		lines []string
+	}{
+		{s: "hello", width: 10, lines: []string{"hello"}},
+		{s: "hello", width: 0, lines: []string{"hello"}},
+		{s: "hello world", width: 5, lines: []string{"hello", "world"}},
+		{s: "the quick brown fox", width: 10, lines: []string{"the quick", "brown fox"}},
+		{s: "abcdefgh", width: 3, lines: []string{"abc", "def", "gh"}},
+		{s: "a verylongword", width: 5, lines: []string{"a", "veryl", "ongwo", "rd"}},
+		{s: "中文字", width: 3, lines: []string{"中", "文", "字"}},
+		{s: "中文 字", width: 5, lines: []string{"中文", "字"}},
+		{s: "line one\nline two", width: 20, lines: []string{"line one", "line two"}},
+		{s: "first line\nsecond", width: 6, lines: []string{"first", "line", "second"}},
+	}
+
+	for _, test := range tests {
+		lines := wrap(test.s, test.width)
+		if strings.Join(lines, "|") != strings.Join(test.lines, "|") {
+			t.Errorf("wrap(%q, %d): expected %q, got %q", test.s, test.width, test.lines, lines)
+		}
+
+		for _, line := range lines {
+			if test.width > 0 && StringWidth(line) > test.width {
+				t.Errorf("wrap(%q, %d): line %q is wider than the limit", test.s, test.width, line)
+			}
+		}
+	}
+}
+
+func TestLastSpace(t *testing.T) {
+	tests := []struct {
+		s   string
+		idx int
+	}{
+		{s: "a b c", idx: 3},
+		{s: "中 文", idx: 1},
+		{s: "abc", idx: -1},
+		{s: "", idx: -1},
+	}
+
+	for _, test := range tests {
+		if idx := lastSpace([]rune(test.s)); idx != test.idx {
+			t.Errorf("lastSpace(%q): expected %d, got %d", test.s, test.idx, idx)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+// Package inventory renders hosts as OpenSSH client configuration or Ansible inventory. Hosts are grouped by their
+// location, product, network and name prefix, e.g. the host web-1 in the location ALP1 is part of the groups
+// location_alp1 and prefix_web.
+package inventory
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatSSHConfig   = "ssh-config"
+	FormatAnsibleINI  = "ansible-ini"
+	FormatAnsibleYAML = "ansible-yaml"
+)
+
+// Formats lists all supported formats.
+var Formats = []string{FormatSSHConfig, FormatAnsibleINI, FormatAnsibleYAML}
+
+// Host is a machine of the inventory.
+type Host struct {
+	Name         string
+	PublicIP     string
+	PrivateIP    string
+	User         string
+	IdentityFile string
+
+	Location string
+	Product  string
+	Network  string
+
+	// Groups are additional groups of the host, e.g. cluster_production for the nodes of a kubernetes cluster.
+	Groups []string
+}
+
+// Address returns the address to connect to, which is the public ip unless there is none or private is set.
+func (h Host) Address(private bool) string {
+	if private || h.PublicIP == "" {
+		return h.PrivateIP
+	}
+
+	return h.PublicIP
+}
+
+func (h Host) groups() []string {
+	groups := []string{
+		GroupName("location", h.Location),
+		GroupName("product", h.Product),
+		GroupName("network", h.Network),
+		GroupName("prefix", NamePrefix(h.Name)),
+	}
+
+	return append(groups, h.Groups...)
+}
+
+func (h Host) vars(private bool) map[string]string {
+	vars := map[string]string{
+		"ansible_host": h.Address(private),
+		"public_ip":    h.PublicIP,
+		"private_ip":   h.PrivateIP,
+		"location":     h.Location,
+		"product":      h.Product,
+		"network":      h.Network,
+	}
+
+	if h.User != "" {
+		vars["ansible_user"] = h.User
+	}
+
+	if h.IdentityFile != "" {
+		vars["ansible_ssh_private_key_file"] = h.IdentityFile
+	}
+
+	for key, value := range vars {
+		if value == "" {
+			delete(vars, key)
+		}
+	}
+
+	return vars
+}
+
+// Options configure the rendering of an inventory.
+type Options struct {
+	Format string
+
+	// Kind is the group containing all hosts, e.g. servers.
+	Kind string
+
+	// PrivateIP uses the private ip of all hosts as their address.
+	PrivateIP bool
+}
+
+// Write renders the hosts in the format given by the options.
+func Write(w io.Writer, hosts []Host, opts Options) error {
+	hosts = append([]Host(nil), hosts...)
+	sort.SliceStable(hosts, func(i, j int) bool {
+		if hosts[i].Location != hosts[j].Location {
+			return hosts[i].Location < hosts[j].Location
+		}
+
+		return hosts[i].Name < hosts[j].Name
+	})
+
+	switch opts.Format {
+	case FormatSSHConfig:
+		return writeSSHConfig(w, hosts, opts)
+	case FormatAnsibleINI:
+		return writeAnsibleINI(w, hosts, opts)
+	case FormatAnsibleYAML:
+		return writeAnsibleYAML(w, hosts, opts)
+	}
+
+	return fmt.Errorf("unknown inventory format %q, allowed values: %s", opts.Format, strings.Join(Formats, ", "))
+}
+
+func writeSSHConfig(w io.Writer, hosts []Host, opts Options) error {
+	buf := &strings.Builder{}
+	location := ""
+
+	for idx, host := range hosts {
+		if idx == 0 || host.Location != location {
+			location = host.Location
+			fmt.Fprintf(buf, "# location %s\n\n", location)
+		}
+
+		address := host.Address(opts.PrivateIP)
+		if address == "" {
+			fmt.Fprintf(buf, "# %s has no address\n\n", host.Name)
+			continue
+		}
+
+		fmt.Fprintf(buf, "# product %s, network %s\n", host.Product, host.Network)
+		fmt.Fprintf(buf, "Host %s\n", host.Name)
+		fmt.Fprintf(buf, "    HostName %s\n", address)
+
+		if host.User != "" {
+			fmt.Fprintf(buf, "    User %s\n", host.User)
+		}
+
+		if host.IdentityFile != "" {
+			fmt.Fprintf(buf, "    IdentityFile %s\n", host.IdentityFile)
+		}
+
+		buf.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+func writeAnsibleINI(w io.Writer, hosts []Host, opts Options) error {
+	buf := &strings.Builder{}
+
+	// host variables are only defined once in the group of the kind, the other groups reference the hosts by name
+	fmt.Fprintf(buf, "[%s]\n", sanitizeGroupName(opts.Kind))
+	for _, host := range hosts {
+		vars := host.vars(opts.PrivateIP)
+
+		keys := make([]string, 0, len(vars))
+		for key := range vars {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buf.WriteString(host.Name)
+		for _, key := range keys {
+			fmt.Fprintf(buf, " %s=%s", key, quoteINI(vars[key]))
+		}
+		buf.WriteString("\n")
+	}
+
+	groups := groupHosts(hosts)
+	for _, group := range sortedKeys(groups) {
+		fmt.Fprintf(buf, "\n[%s]\n", group)
+		for _, name := range groups[group] {
+			fmt.Fprintln(buf, name)
+		}
+	}
+
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+type ansibleGroup struct {
+	Hosts    map[string]interface{}  `yaml:"hosts,omitempty"`
+	Children map[string]ansibleGroup `yaml:"children,omitempty"`
+}
+
+func writeAnsibleYAML(w io.Writer, hosts []Host, opts Options) error {
+	kind := ansibleGroup{Hosts: map[string]interface{}{}}
+	for _, host := range hosts {
+		kind.Hosts[host.Name] = host.vars(opts.PrivateIP)
+	}
+
+	all := ansibleGroup{Children: map[string]ansibleGroup{sanitizeGroupName(opts.Kind): kind}}
+	for group, names := range groupHosts(hosts) {
+		members := ansibleGroup{Hosts: map[string]interface{}{}}
+		for _, name := range names {
+			members.Hosts[name] = nil
+		}
+
+		all.Children[group] = members
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(map[string]ansibleGroup{"all": all}); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// groupHosts returns the names of the hosts in each group.
+func groupHosts(hosts []Host) map[string][]string {
+	groups := map[string][]string{}
+	for _, host := range hosts {
+		for _, group := range host.groups() {
+			if group != "" {
+				groups[group] = append(groups[group], host.Name)
+			}
+		}
+	}
+
+	return groups
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+var invalidGroupChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// GroupName returns a valid ansible group name for the value of a category, e.g. product_b1_1x1 for the product
+// b1.1x1. It is empty if the value is empty.
+func GroupName(category string, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return sanitizeGroupName(category + "_" + value)
+}
+
+func sanitizeGroupName(name string) string {
+	return strings.Trim(invalidGroupChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+}
+
+var nameSuffix = regexp.MustCompile(`[-_.]*[0-9]+$`)
+
+// NamePrefix returns the name without its trailing number, e.g. web for web-01. It is empty if the name does not end
+// in a number.
+func NamePrefix(name string) string {
+	prefix := nameSuffix.ReplaceAllString(name, "")
+	if prefix == name {
+		return ""
+	}
+
+	return prefix
+}
+
+func quoteINI(value string) string {
+	if !strings.ContainsAny(value, " \t\"'=#;") {
+		return value
+	}
+
+	return fmt.Sprintf("%q", value)
+}
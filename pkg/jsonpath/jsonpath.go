@@ -0,0 +1,229 @@
+// Package jsonpath implements the subset of the kubectl JSONPath template syntax, which is useful to extract values
+// from the output of the cli. The data is expected in the form produced by decoding JSON into an interface{}.
+//
+// Supported are literal text, quoted strings ({"\n"}), field access (.name or ['name']), wildcards (.* or [*]), array
+// indices and slices ([0], [-1], [1:3]), filters ([?(@.status.name=="running")]) and {range ...}{end} blocks.
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type Template struct {
+	nodes []node
+}
+
+type node interface{}
+
+type textNode string
+
+type pathNode struct {
+	absolute bool
+	segments []segment
+}
+
+type rangeNode struct {
+	path pathNode
+	body []node
+}
+
+// Parse parses the template text. For convenience, a template without any braces is treated as a single expression.
+func Parse(text string) (*Template, error) {
+	if !strings.Contains(text, "{") {
+		text = "{" + text + "}"
+	}
+
+	nodes, rest, err := parseNodes(text, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if rest != "" {
+		return nil, fmt.Errorf("unexpected {end}")
+	}
+
+	return &Template{nodes: nodes}, nil
+}
+
+func parseNodes(text string, inRange bool) ([]node, string, error) {
+	var nodes []node
+
+	for len(text) != 0 {
+		start := strings.IndexByte(text, '{')
+		if start == -1 {
+			nodes = append(nodes, textNode(text))
+			text = ""
+			break
+		}
+
+		if start > 0 {
+			nodes = append(nodes, textNode(text[:start]))
+		}
+
+		end := findClosingBrace(text, start)
+		if end == -1 {
+			return nil, "", fmt.Errorf("unclosed action in %q", text[start:])
+		}
+
+		action := strings.TrimSpace(text[start+1 : end])
+		text = text[end+1:]
+
+		switch {
+		case action == "end":
+			if !inRange {
+				return nil, "", fmt.Errorf("unexpected {end}")
+			}
+
+			return nodes, text, nil
+		case strings.HasPrefix(action, "range "):
+			path, err := parsePath(strings.TrimSpace(strings.TrimPrefix(action, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+
+			body, rest, err := parseNodes(text, true)
+			if err != nil {
+				return nil, "", err
+			}
+
+			nodes = append(nodes, rangeNode{path: path, body: body})
+			text = rest
+		case strings.HasPrefix(action, `"`) || strings.HasPrefix(action, "'"):
+			literal, err := unquote(action)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid string %s: %w", action, err)
+			}
+
+			nodes = append(nodes, textNode(literal))
+		default:
+			path, err := parsePath(action)
+			if err != nil {
+				return nil, "", err
+			}
+
+			nodes = append(nodes, path)
+		}
+	}
+
+	if inRange {
+		return nil, "", fmt.Errorf("missing {end} of {range}")
+	}
+
+	return nodes, "", nil
+}
+
+func findClosingBrace(text string, start int) int {
+	var quote byte
+
+	for i := start + 1; i < len(text); i++ {
+		c := text[i]
+
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Execute writes the result of the template applied to data to the writer.
+func (t *Template) Execute(w io.Writer, data interface{}) error {
+	buf := &bytes.Buffer{}
+	if err := execute(buf, t.nodes, data, data); err != nil {
+		return err
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func execute(buf *bytes.Buffer, nodes []node, root, current interface{}) error {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case textNode:
+			buf.WriteString(string(n))
+		case pathNode:
+			results, err := n.evaluate(root, current)
+			if err != nil {
+				return err
+			}
+
+			for i, result := range results {
+				if i != 0 {
+					buf.WriteByte(' ')
+				}
+
+				if err = writeValue(buf, result); err != nil {
+					return err
+				}
+			}
+		case rangeNode:
+			results, err := n.path.evaluate(root, current)
+			if err != nil {
+				return err
+			}
+
+			// ranging over a single array iterates its elements, like kubectl does
+			if len(results) == 1 {
+				if items, ok := results[0].([]interface{}); ok {
+					results = items
+				}
+			}
+
+			for _, result := range results {
+				if err = execute(buf, n.body, root, result); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeValue(buf *bytes.Buffer, value interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case string:
+		buf.WriteString(value)
+	case float64:
+		buf.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	case bool:
+		buf.WriteString(strconv.FormatBool(value))
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		buf.Write(data)
+	}
+
+	return nil
+}
+
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("unterminated string")
+		}
+
+		return s[1 : len(s)-1], nil
+	}
+
+	return strconv.Unquote(s)
+}
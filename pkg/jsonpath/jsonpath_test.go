@@ -0,0 +1,107 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testData = `{
+	"kind": "list",
+	"items": [
+		{"name": "web-1", "status": {"name": "running"}, "size": 20, "public": true, "tags": ["a", "b"]},
+		{"name": "web-2", "status": {"name": "stopped"}, "size": 40, "public": false, "tags": []},
+		{"name": "db==1", "status": {"name": "running"}, "size": 100, "public": false, "labels": {"role": "db", "tier": "data"}}
+	]
+}`
+
+func TestExecute(t *testing.T) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(testData), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		result   string
+	}{
+		{name: "field", template: "{.kind}", result: "list"},
+		{name: "without braces", template: ".kind", result: "list"},
+		{name: "absolute field", template: "{$.kind}", result: "list"},
+		{name: "nested field", template: "{.items[0].status.name}", result: "running"},
+		{name: "quoted field", template: "{.items[0]['status'][\"name\"]}", result: "running"},
+		{name: "missing field", template: "{.items[0].missing}", result: ""},
+		{name: "number and bool", template: "{.items[1].size} {.items[1].public}", result: "40 false"},
+		{name: "object", template: "{.items[2].labels}", result: `{"role":"db","tier":"data"}`},
+		{name: "array", template: "{.items[0].tags}", result: `["a","b"]`},
+		{name: "wildcard array", template: "{.items[*].name}", result: "web-1 web-2 db==1"},
+		{name: "wildcard object", template: "{.items[2].labels.*}", result: "db data"},
+		{name: "index", template: "{.items[1].name}", result: "web-2"},
+		{name: "negative index", template: "{.items[-1].name}", result: "db==1"},
+		{name: "index out of range", template: "{.items[3].name}", result: ""},
+		{name: "slice", template: "{.items[0:2].name}", result: "web-1 web-2"},
+		{name: "open slice", template: "{.items[1:].name}", result: "web-2 db==1"},
+		{name: "negative slice", template: "{.items[:-1].name}", result: "web-1 web-2"},
+		{name: "empty slice", template: "{.items[2:1].name}", result: ""},
+		{name: "filter string", template: `{.items[?(@.status.name=="running")].name}`, result: "web-1 db==1"},
+		{name: "filter not equal", template: `{.items[?(@.status.name!="running")].name}`, result: "web-2"},
+		{name: "filter number", template: "{.items[?(@.size>=40)].name}", result: "web-2 db==1"},
+		{name: "filter bool", template: "{.items[?(@.public==true)].name}", result: "web-1"},
+		{name: "filter existence", template: "{.items[?(@.labels)].name}", result: "db==1"},
+		{name: "filter operator in literal", template: `{.items[?(@.name=="db==1")].size}`, result: "100"},
+		{name: "filter single quoted literal", template: "{.items[?(@.name!='db==1')].size}", result: "20 40"},
+		{name: "range", template: `{range .items[*]}{.name}{"\t"}{.size}{"\n"}{end}`, result: "web-1\t20\nweb-2\t40\ndb==1\t100\n"},
+		{name: "range over array", template: `{range .items}[{.name}]{end}`, result: "[web-1][web-2][db==1]"},
+		{name: "nested range", template: `{range .items[*]}{.name}:{range .tags}{@}{end};{end}`, result: "web-1:ab;web-2:;db==1:;"},
+		{name: "range with absolute path", template: `{range .items[0:2]}{$.kind}/{.name} {end}`, result: "list/web-1 list/web-2 "},
+		{name: "literal text", template: "kind: {.kind}", result: "kind: list"},
+		{name: "quoted literal", template: `{.kind}{", "}{'}'}{"ä"}`, result: "list, }ä"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpl, err := Parse(test.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			buf := &strings.Builder{}
+			if err = tmpl.Execute(buf, data); err != nil {
+				t.Fatal(err)
+			}
+
+			if buf.String() != test.result {
+				t.Errorf("expected %q, got %q", test.result, buf.String())
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		template string
+		err      string
+	}{
+		{template: "{.name", err: "unclosed action"},
+		{template: `{"}"`, err: "unclosed action"},
+		{template: "{.items[0}", err: "unclosed bracket"},
+		{template: "{.items[a]}", err: `invalid index "a"`},
+		{template: "{.items[1:b]}", err: `invalid slice "1:b"`},
+		{template: "{.items[?(@.size>big)]}", err: `invalid operand "big"`},
+		{template: "{..name}", err: "recursive descent is not supported"},
+		{template: "{.name}{end}", err: "unexpected {end}"},
+		{template: "{range .items[*]}{.name}", err: "missing {end} of {range}"},
+		{template: `{"\q"}`, err: "invalid string"},
+		{template: "{.items}{)}", err: `unexpected ")"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.template, func(t *testing.T) {
+			_, err := Parse(test.template)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,428 @@
+package jsonpath
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type segment interface {
+	apply(value interface{}, root interface{}) ([]interface{}, error)
+}
+
+type fieldSegment string
+
+type wildcardSegment struct{}
+
+type indexSegment int
+
+type sliceSegment struct {
+	start, end *int
+}
+
+type filterSegment struct {
+	path     pathNode
+	operator string
+	operand  interface{}
+}
+
+var filterOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func parsePath(expr string) (pathNode, error) {
+	path := pathNode{}
+	rest := expr
+
+	switch {
+	case strings.HasPrefix(rest, "$"):
+		path.absolute = true
+		rest = rest[1:]
+	case strings.HasPrefix(rest, "@"):
+		rest = rest[1:]
+	}
+
+	for len(rest) != 0 {
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			return pathNode{}, fmt.Errorf("recursive descent is not supported in %q", expr)
+		case strings.HasPrefix(rest, ".*"):
+			path.segments = append(path.segments, wildcardSegment{})
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+
+			name := fieldName(rest)
+			if name != "" {
+				path.segments = append(path.segments, fieldSegment(name))
+				rest = rest[len(name):]
+			}
+		case strings.HasPrefix(rest, "["):
+			end := findClosingBracket(rest)
+			if end == -1 {
+				return pathNode{}, fmt.Errorf("unclosed bracket in %q", expr)
+			}
+
+			seg, err := parseBracket(strings.TrimSpace(rest[1:end]))
+			if err != nil {
+				return pathNode{}, fmt.Errorf("invalid path %q: %w", expr, err)
+			}
+
+			path.segments = append(path.segments, seg)
+			rest = rest[end+1:]
+		default:
+			name := fieldName(rest)
+			if name == "" {
+				return pathNode{}, fmt.Errorf("unexpected %q in path %q", rest[:1], expr)
+			}
+
+			path.segments = append(path.segments, fieldSegment(name))
+			rest = rest[len(name):]
+		}
+	}
+
+	return path, nil
+}
+
+func fieldName(s string) string {
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return r == '.' || r == '[' || r == ' ' || strings.ContainsRune("=!<>)", r)
+	})
+
+	if end == -1 {
+		return s
+	}
+
+	return s[:end]
+}
+
+func findClosingBracket(s string) int {
+	var quote byte
+	depth := 0
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+			if depth == 0 && c == ']' {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func parseBracket(content string) (segment, error) {
+	switch {
+	case content == "*":
+		return wildcardSegment{}, nil
+	case strings.HasPrefix(content, `"`) || strings.HasPrefix(content, "'"):
+		name, err := unquote(content)
+		return fieldSegment(name), err
+	case strings.HasPrefix(content, "?(") && strings.HasSuffix(content, ")"):
+		return parseFilter(strings.TrimSpace(content[2 : len(content)-1]))
+	case strings.Contains(content, ":"):
+		parts := strings.SplitN(content, ":", 2)
+		seg := sliceSegment{}
+
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+
+			num, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid slice %q", content)
+			}
+
+			if i == 0 {
+				seg.start = &num
+			} else {
+				seg.end = &num
+			}
+		}
+
+		return seg, nil
+	}
+
+	num, err := strconv.Atoi(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid index %q", content)
+	}
+
+	return indexSegment(num), nil
+}
+
+func parseFilter(expr string) (segment, error) {
+	if idx, op := findOperator(expr); idx != -1 {
+		path, err := parsePath(strings.TrimSpace(expr[:idx]))
+		if err != nil {
+			return nil, err
+		}
+
+		operand, err := parseOperand(strings.TrimSpace(expr[idx+len(op):]))
+		if err != nil {
+			return nil, err
+		}
+
+		return filterSegment{path: path, operator: op, operand: operand}, nil
+	}
+
+	path, err := parsePath(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterSegment{path: path}, nil
+}
+
+// findOperator returns the position of the first comparison operator in the filter expression, which is not part of a
+// quoted operand.
+func findOperator(expr string) (int, string) {
+	var quote byte
+
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		default:
+			for _, op := range filterOperators {
+				if strings.HasPrefix(expr[i:], op) {
+					return i, op
+				}
+			}
+		}
+	}
+
+	return -1, ""
+}
+
+func parseOperand(s string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "'"):
+		return unquote(s)
+	case s == "true" || s == "false":
+		return s == "true", nil
+	case s == "null":
+		return nil, nil
+	}
+
+	num, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operand %q", s)
+	}
+
+	return num, nil
+}
+
+func (p pathNode) evaluate(root, current interface{}) ([]interface{}, error) {
+	values := []interface{}{current}
+	if p.absolute {
+		values = []interface{}{root}
+	}
+
+	for _, seg := range p.segments {
+		var next []interface{}
+
+		for _, value := range values {
+			results, err := seg.apply(value, root)
+			if err != nil {
+				return nil, err
+			}
+
+			next = append(next, results...)
+		}
+
+		values = next
+	}
+
+	return values, nil
+}
+
+func (f fieldSegment) apply(value interface{}, root interface{}) ([]interface{}, error) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	field, ok := obj[string(f)]
+	if !ok {
+		return nil, nil
+	}
+
+	return []interface{}{field}, nil
+}
+
+func (w wildcardSegment) apply(value interface{}, root interface{}) ([]interface{}, error) {
+	switch value := value.(type) {
+	case []interface{}:
+		return value, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		results := make([]interface{}, len(keys))
+		for i, key := range keys {
+			results[i] = value[key]
+		}
+
+		return results, nil
+	}
+
+	return nil, nil
+}
+
+func (i indexSegment) apply(value interface{}, root interface{}) ([]interface{}, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	idx := int(i)
+	if idx < 0 {
+		idx += len(items)
+	}
+
+	if idx < 0 || idx >= len(items) {
+		return nil, nil
+	}
+
+	return []interface{}{items[idx]}, nil
+}
+
+func (s sliceSegment) apply(value interface{}, root interface{}) ([]interface{}, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	bound := func(ptr *int, def int) int {
+		if ptr == nil {
+			return def
+		}
+
+		idx := *ptr
+		if idx < 0 {
+			idx += len(items)
+		}
+
+		if idx < 0 {
+			return 0
+		}
+
+		if idx > len(items) {
+			return len(items)
+		}
+
+		return idx
+	}
+
+	start, end := bound(s.start, 0), bound(s.end, len(items))
+	if start >= end {
+		return nil, nil
+	}
+
+	return items[start:end], nil
+}
+
+func (f filterSegment) apply(value interface{}, root interface{}) ([]interface{}, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+
+	var results []interface{}
+	for _, item := range items {
+		matches, err := f.matches(item, root)
+		if err != nil {
+			return nil, err
+		}
+
+		if matches {
+			results = append(results, item)
+		}
+	}
+
+	return results, nil
+}
+
+func (f filterSegment) matches(item interface{}, root interface{}) (bool, error) {
+	values, err := f.path.evaluate(root, item)
+	if err != nil {
+		return false, err
+	}
+
+	if f.operator == "" {
+		return len(values) != 0, nil
+	}
+
+	for _, value := range values {
+		if compare(value, f.operator, f.operand) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func compare(value interface{}, operator string, operand interface{}) bool {
+	if a, ok := value.(float64); ok {
+		if b, ok := operand.(float64); ok {
+			switch operator {
+			case "==":
+				return a == b
+			case "!=":
+				return a != b
+			case "<":
+				return a < b
+			case "<=":
+				return a <= b
+			case ">":
+				return a > b
+			case ">=":
+				return a >= b
+			}
+		}
+	}
+
+	a, b := fmt.Sprint(value), fmt.Sprint(operand)
+	switch operator {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+
+	return false
+}
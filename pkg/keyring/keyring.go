@@ -0,0 +1,164 @@
+// Package keyring implements a local file which stores secrets encrypted with a key derived from a passphrase.
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	version    = 1
+	iterations = 600000
+	saltSize   = 16
+	keySize    = 32
+
+	// minIterations is the lowest number of iterations accepted when opening a keyring. As keyrings are always saved
+	// using iterations, keyrings with fewer iterations have been tampered with or downgraded.
+	minIterations = 600000
+)
+
+var ErrInvalidPassphrase = errors.New("invalid passphrase or corrupted keyring")
+
+type file struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+type Keyring struct {
+	Path string
+
+	secrets map[string]string
+}
+
+// Exists reports whether a keyring has already been saved at the path.
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Open decrypts the keyring at the path using the passphrase. If the keyring does not exist yet, an empty keyring is
+// returned, which is created once it is saved.
+func Open(path string, passphrase string) (*Keyring, error) {
+	keyring := &Keyring{
+		Path:    path,
+		secrets: map[string]string{},
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return keyring, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read keyring: %w", err)
+	}
+
+	encrypted := file{}
+	if err = json.Unmarshal(data, &encrypted); err != nil {
+		return nil, fmt.Errorf("parse keyring: %w", err)
+	}
+
+	if encrypted.Version != version {
+		return nil, fmt.Errorf("unsupported keyring version %d", encrypted.Version)
+	}
+
+	if encrypted.Iterations < minIterations {
+		return nil, fmt.Errorf("keyring uses %d key derivation iterations, at least %d are required", encrypted.Iterations, minIterations)
+	}
+
+	aead, err := newCipher(passphrase, encrypted.Salt, encrypted.Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := aead.Open(nil, encrypted.Nonce, encrypted.Data, nil)
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+
+	if err = json.Unmarshal(plain, &keyring.secrets); err != nil {
+		return nil, fmt.Errorf("parse keyring: %w", err)
+	}
+
+	return keyring, nil
+}
+
+func (k *Keyring) Get(name string) (string, bool) {
+	secret, ok := k.secrets[name]
+	return secret, ok
+}
+
+func (k *Keyring) Set(name string, secret string) {
+	k.secrets[name] = secret
+}
+
+func (k *Keyring) Delete(name string) {
+	delete(k.secrets, name)
+}
+
+// Save encrypts the keyring using the passphrase and writes it to its path. A new salt and nonce is generated on every
+// save.
+func (k *Keyring) Save(passphrase string) error {
+	plain, err := json.Marshal(k.secrets)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err = rand.Read(salt); err != nil {
+		return err
+	}
+
+	aead, err := newCipher(passphrase, salt, iterations)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(file{
+		Version:    version,
+		Iterations: iterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Data:       aead.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(k.Path), 0700); err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(k.Path, data, 0600); err != nil {
+		return fmt.Errorf("write keyring: %w", err)
+	}
+
+	return os.Chmod(k.Path, 0600)
+}
+
+func newCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	if iterations <= 0 || len(salt) == 0 {
+		return nil, ErrInvalidPassphrase
+	}
+
+	block, err := aes.NewCipher(pbkdf2([]byte(passphrase), salt, iterations, keySize))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
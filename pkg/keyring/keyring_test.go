@@ -0,0 +1,70 @@
+package keyring
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKeyring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+
+	ring, err := Open(path, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ring.Set("default", "token")
+	if err = ring.Save("secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	ring, err = Open(path, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token, ok := ring.Get("default"); !ok || token != "token" {
+		t.Errorf("expected token of default to be restored, got %q", token)
+	}
+
+	if _, err = Open(path, "wrong"); !errors.Is(err, ErrInvalidPassphrase) {
+		t.Errorf("expected invalid passphrase, got %v", err)
+	}
+}
+
+func TestKeyringRejectsDowngrade(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+
+	ring, _ := Open(path, "secret")
+	ring.Set("default", "token")
+	if err := ring.Save("secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var encrypted file
+	if err = json.Unmarshal(data, &encrypted); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, count := range []int{0, 1, minIterations - 1} {
+		encrypted.Iterations = count
+		data, _ = json.Marshal(encrypted)
+		if err = os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = Open(path, "secret")
+		if err == nil || !strings.Contains(err.Error(), "at least") {
+			t.Errorf("expected keyring with %d iterations to be rejected, got %v", count, err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+// Bucket is a container of objects.
+type Bucket struct {
+	Name         string    `xml:"Name" json:"name"`
+	CreationDate time.Time `xml:"CreationDate" json:"creation_date"`
+}
+
+type listBucketsResult struct {
+	XMLName xml.Name `xml:"ListAllMyBucketsResult"`
+	Buckets []Bucket `xml:"Buckets>Bucket"`
+}
+
+// ListBuckets returns all buckets owned by the credentials.
+func (c *Client) ListBuckets(ctx context.Context) ([]Bucket, error) {
+	var res listBucketsResult
+	if err := c.doXML(ctx, request{method: http.MethodGet}, &res); err != nil {
+		return nil, err
+	}
+
+	return res.Buckets, nil
+}
+
+// CreateBucket creates a new bucket. Bucket names are global for the endpoint.
+func (c *Client) CreateBucket(ctx context.Context, bucket string) error {
+	_, err := c.doDiscard(ctx, request{method: http.MethodPut, bucket: bucket})
+	return err
+}
+
+// DeleteBucket deletes a bucket, which has to be empty.
+func (c *Client) DeleteBucket(ctx context.Context, bucket string) error {
+	_, err := c.doDiscard(ctx, request{method: http.MethodDelete, bucket: bucket})
+	return err
+}
+
+// HeadBucket checks that the bucket exists and is accessible.
+func (c *Client) HeadBucket(ctx context.Context, bucket string) error {
+	_, err := c.doDiscard(ctx, request{method: http.MethodHead, bucket: bucket})
+	return err
+}
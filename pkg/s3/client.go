@@ -0,0 +1,248 @@
+// Package s3 implements the subset of the S3 API used to manage buckets and objects of the object storage. Requests are
+// signed using AWS signature version 4 and sent using path style addressing, e.g.
+//
+//	client, err := s3.NewClient(credential.Endpoint, s3.Credentials{AccessKey: "...", SecretKey: "..."}, nil)
+//	buckets, err := client.ListBuckets(ctx)
+//
+// which works with any S3 compatible service, including a local stand-in for testing.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultRegion is the region of the signature, if none is given. S3 compatible services usually accept any region.
+const DefaultRegion = "us-east-1"
+
+// Client sends requests to an S3 compatible endpoint.
+type Client struct {
+	endpoint    *url.URL
+	credentials Credentials
+	http        *http.Client
+}
+
+// NewClient creates a client for the endpoint, which defaults to https if it has no scheme. The path of the endpoint is
+// kept as prefix of all requests. The http.DefaultClient is used if httpClient is nil.
+func NewClient(endpoint string, credentials Credentials, httpClient *http.Client) (*Client, error) {
+	u, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{endpoint: u, credentials: credentials, http: httpClient}, nil
+}
+
+// ParseEndpoint parses the url of an endpoint, which defaults to https if it has no scheme. The trailing slash of the
+// path is removed.
+func ParseEndpoint(endpoint string) (*url.URL, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parse endpoint: %w", err)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("parse endpoint: missing host in %q", endpoint)
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+
+	return u, nil
+}
+
+// Endpoint returns the base url of the client.
+func (c *Client) Endpoint() string {
+	return c.endpoint.String()
+}
+
+// Error is the error returned by the service for failed requests.
+type Error struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+	Resource   string `xml:"Resource"`
+	RequestID  string `xml:"RequestId"`
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s (%d)", e.Code, e.StatusCode)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// IsNotFound reports whether the error is caused by a bucket or object which does not exist.
+func IsNotFound(err error) bool {
+	var s3Err *Error
+	return errors.As(err, &s3Err) && s3Err.StatusCode == http.StatusNotFound
+}
+
+type request struct {
+	method string
+	bucket string
+	key    string
+	query  url.Values
+	header http.Header
+
+	// body is read twice, once to compute the payload hash and once to send it.
+	body *io.SectionReader
+}
+
+// url returns the path style url of the bucket and key. The path is encoded the same way as for the signature.
+func (c *Client) url(bucket string, key string, query url.Values) *url.URL {
+	u := *c.endpoint
+
+	path := c.endpoint.Path + "/"
+	if bucket != "" {
+		path += bucket
+		if key != "" {
+			path += "/" + key
+		}
+	}
+
+	u.Path = path
+	u.RawPath = uriEncode(path, false)
+	u.RawQuery = canonicalQuery(query)
+
+	return &u
+}
+
+// do signs and sends the request. Responses with a status other than 2xx are returned as *Error.
+func (c *Client) do(ctx context.Context, r request) (*http.Response, error) {
+	payloadHash := emptyPayloadHash
+	if r.body != nil {
+		var err error
+		if payloadHash, err = hashBody(r.body); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, c.url(r.bucket, r.key, r.query).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+
+	if r.body != nil {
+		setBody(req, r.body)
+	}
+
+	c.credentials.Sign(req, payloadHash, time.Now())
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= 300 {
+		defer res.Body.Close()
+		return nil, parseError(res)
+	}
+
+	return res, nil
+}
+
+// doXML sends the request and decodes the xml response into dest.
+func (c *Client) doXML(ctx context.Context, r request, dest interface{}) error {
+	res, err := c.do(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if err = xml.NewDecoder(res.Body).Decode(dest); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+// doDiscard sends the request and discards the response body.
+func (c *Client) doDiscard(ctx context.Context, r request) (*http.Response, error) {
+	res, err := c.do(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+
+	return res, nil
+}
+
+func parseError(res *http.Response) error {
+	s3Err := &Error{StatusCode: res.StatusCode}
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if len(bytes.TrimSpace(body)) != 0 {
+		_ = xml.Unmarshal(body, s3Err)
+	}
+
+	if s3Err.Code == "" {
+		s3Err.Code = strings.ReplaceAll(http.StatusText(res.StatusCode), " ", "")
+	}
+
+	return s3Err
+}
+
+func hashBody(body *io.SectionReader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(body, 0, body.Size())); err != nil {
+		return "", fmt.Errorf("hash body: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func hashMD5(body *io.SectionReader) ([]byte, error) {
+	hash := md5.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(body, 0, body.Size())); err != nil {
+		return nil, fmt.Errorf("hash body: %w", err)
+	}
+
+	return hash.Sum(nil), nil
+}
+
+// contentMD5 returns the value of the Content-MD5 header, which lets the service verify the integrity of the body.
+func contentMD5(sum []byte) string {
+	return base64.StdEncoding.EncodeToString(sum)
+}
+
+// setBody sets the body of the request. Every read of the body, e.g. by retries or dumps, gets its own reader.
+func setBody(req *http.Request, body *io.SectionReader) {
+	req.ContentLength = body.Size()
+	if body.Size() == 0 {
+		req.Body = http.NoBody
+		return
+	}
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(io.NewSectionReader(body, 0, body.Size())), nil
+	}
+	req.Body, _ = req.GetBody()
+}
@@ -0,0 +1,123 @@
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const maxCORSRules = 100
+
+// CORSConfiguration allows web pages of other origins to access the objects of a bucket.
+type CORSConfiguration struct {
+	XMLName xml.Name   `xml:"CORSConfiguration" json:"-"`
+	Rules   []CORSRule `xml:"CORSRule" json:"CORSRules"`
+}
+
+// CORSRule allows requests using one of the methods from one of the origins.
+type CORSRule struct {
+	ID             string   `xml:"ID,omitempty" json:"ID,omitempty"`
+	AllowedHeaders []string `xml:"AllowedHeader" json:"AllowedHeaders,omitempty"`
+	AllowedMethods []string `xml:"AllowedMethod" json:"AllowedMethods"`
+	AllowedOrigins []string `xml:"AllowedOrigin" json:"AllowedOrigins"`
+	ExposeHeaders  []string `xml:"ExposeHeader" json:"ExposeHeaders,omitempty"`
+	MaxAgeSeconds  *int     `xml:"MaxAgeSeconds,omitempty" json:"MaxAgeSeconds,omitempty"`
+}
+
+// ParseCORSConfiguration decodes and validates a cors configuration given as xml or json.
+func ParseCORSConfiguration(data []byte) (CORSConfiguration, error) {
+	var config CORSConfiguration
+	if err := decodeDocument(data, &config); err != nil {
+		return CORSConfiguration{}, err
+	}
+
+	return config, config.Validate()
+}
+
+// Validate checks the configuration for errors, which would be rejected by the service.
+func (c CORSConfiguration) Validate() error {
+	if len(c.Rules) == 0 {
+		return errors.New("cors configuration has no rules")
+	}
+
+	if len(c.Rules) > maxCORSRules {
+		return fmt.Errorf("cors configuration has %d rules, at most %d are allowed", len(c.Rules), maxCORSRules)
+	}
+
+	for idx, rule := range c.Rules {
+		if err := rule.validate(); err != nil {
+			name := fmt.Sprintf("rule %d", idx+1)
+			if rule.ID != "" {
+				name = fmt.Sprintf("rule %q", rule.ID)
+			}
+
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r CORSRule) validate() error {
+	if len(r.ID) > maxRuleIDLength {
+		return fmt.Errorf("id is longer than %d characters", maxRuleIDLength)
+	}
+
+	if len(r.AllowedMethods) == 0 {
+		return errors.New("rule has no allowed methods")
+	}
+
+	for _, method := range r.AllowedMethods {
+		switch method {
+		case http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodHead:
+		default:
+			return fmt.Errorf("method %q is not allowed, allowed values: GET, PUT, POST, DELETE, HEAD", method)
+		}
+	}
+
+	if len(r.AllowedOrigins) == 0 {
+		return errors.New("rule has no allowed origins")
+	}
+
+	for _, origin := range r.AllowedOrigins {
+		if origin == "" || strings.Count(origin, "*") > 1 {
+			return fmt.Errorf("origin %q must not be empty and may contain at most one wildcard", origin)
+		}
+	}
+
+	for _, header := range r.AllowedHeaders {
+		if strings.Count(header, "*") > 1 {
+			return fmt.Errorf("allowed header %q may contain at most one wildcard", header)
+		}
+	}
+
+	if r.MaxAgeSeconds != nil && *r.MaxAgeSeconds < 0 {
+		return errors.New("max age seconds must not be negative")
+	}
+
+	return nil
+}
+
+// GetBucketCORS returns the cors configuration of the bucket or nil if it has none.
+func (c *Client) GetBucketCORS(ctx context.Context, bucket string) (*CORSConfiguration, error) {
+	config := &CORSConfiguration{}
+
+	err := c.getBucketXML(ctx, bucket, "cors", config)
+	if isErrorCode(err, "NoSuchCORSConfiguration") {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// PutBucketCORS replaces the cors configuration of the bucket.
+func (c *Client) PutBucketCORS(ctx context.Context, bucket string, config CORSConfiguration) error {
+	return c.putBucketXML(ctx, bucket, "cors", config)
+}
@@ -0,0 +1,74 @@
+package s3
+
+import (
+	"testing"
+)
+
+func TestParseCORSConfiguration(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		err      string
+	}{
+		{
+			name:     "json",
+			document: `{"CORSRules": [{"AllowedMethods": ["GET", "HEAD"], "AllowedOrigins": ["https://*.example.com"], "AllowedHeaders": ["*"], "MaxAgeSeconds": 3000}]}`,
+		},
+		{
+			name: "xml",
+			document: `<CORSConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+				<CORSRule>
+					<ID>web</ID>
+					<AllowedOrigin>https://example.com</AllowedOrigin>
+					<AllowedMethod>GET</AllowedMethod>
+					<AllowedMethod>PUT</AllowedMethod>
+					<AllowedHeader>*</AllowedHeader>
+					<ExposeHeader>ETag</ExposeHeader>
+					<MaxAgeSeconds>600</MaxAgeSeconds>
+				</CORSRule>
+			</CORSConfiguration>`,
+		},
+		{
+			name:     "xml unknown element",
+			document: `<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>GET</AllowedMethod><AllowCredentials>true</AllowCredentials></CORSRule></CORSConfiguration>`,
+			err:      "unknown element <AllowCredentials> in <CORSConfiguration>CORSRule>",
+		},
+		{
+			name:     "json unknown field",
+			document: `{"CORSRules": [{"AllowedMethods": ["GET"], "AllowedOrigins": ["*"], "AllowCredentials": true}]}`,
+			err:      `unknown field "AllowCredentials"`,
+		},
+		{
+			name:     "no rules",
+			document: `<CORSConfiguration></CORSConfiguration>`,
+			err:      "no rules",
+		},
+		{
+			name:     "invalid method",
+			document: `{"CORSRules": [{"AllowedMethods": ["PATCH"], "AllowedOrigins": ["*"]}]}`,
+			err:      `method "PATCH" is not allowed`,
+		},
+		{
+			name:     "no origins",
+			document: `{"CORSRules": [{"AllowedMethods": ["GET"]}]}`,
+			err:      "no allowed origins",
+		},
+		{
+			name:     "multiple wildcards",
+			document: `{"CORSRules": [{"ID": "web", "AllowedMethods": ["GET"], "AllowedOrigins": ["https://*.*.example.com"]}]}`,
+			err:      `rule "web": origin "https://*.*.example.com"`,
+		},
+		{
+			name:     "negative max age",
+			document: `<CORSConfiguration><CORSRule><AllowedOrigin>*</AllowedOrigin><AllowedMethod>GET</AllowedMethod><MaxAgeSeconds>-1</MaxAgeSeconds></CORSRule></CORSConfiguration>`,
+			err:      "max age seconds must not be negative",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseCORSConfiguration([]byte(test.document))
+			checkError(t, err, test.err)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// decodeDocument decodes a bucket configuration given either as xml, in the format of the S3 API, or as json, in the
+// format of the AWS CLI. Unknown json fields are rejected, so misspelled settings are not dropped silently.
+func decodeDocument(data []byte, v interface{}) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return errors.New("empty document")
+	}
+
+	if data[0] == '<' {
+		return decodeXML(data, v)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("parse json: %w", err)
+	}
+
+	if decoder.More() {
+		return errors.New("parse json: unexpected data after the document")
+	}
+
+	return nil
+}
+
+// decodeXML decodes the xml document into v. Unlike xml.Unmarshal, elements without a corresponding field of v are
+// rejected, as dropping them silently could e.g. widen the filter of a lifecycle rule to all objects.
+func decodeXML(data []byte, v interface{}) error {
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse xml: %w", err)
+	}
+
+	known := map[string]bool{}
+	xmlElements(reflect.TypeOf(v), "", known)
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var path []string
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("parse xml: %w", err)
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			// the name of the root element is checked by xml.Unmarshal
+			if len(path) != 0 && !known[strings.Join(append(path[1:], token.Name.Local), ">")] {
+				return fmt.Errorf("parse xml: unknown element <%s> in <%s>", token.Name.Local, strings.Join(path, ">"))
+			}
+
+			path = append(path, token.Name.Local)
+		case xml.EndElement:
+			path = path[:len(path)-1]
+		}
+	}
+}
+
+// xmlElements adds the paths of all elements of the type, e.g. Rule>Filter>Prefix, to known.
+func xmlElements(t reflect.Type, prefix string, known map[string]bool) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for idx := 0; idx < t.NumField(); idx++ {
+		field := t.Field(idx)
+		if !field.IsExported() || field.Name == "XMLName" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("xml"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		path := prefix + name
+		known[path] = true
+		xmlElements(field.Type, path+">", known)
+	}
+}
+
+// subresource returns the query selecting a subresource of a bucket, e.g. ?lifecycle.
+func subresource(name string) url.Values {
+	return url.Values{name: {""}}
+}
+
+// getBucketXML decodes the xml configuration of the bucket subresource. Elements unknown to dest are rejected, so a
+// configuration containing settings which are not supported by this client cannot be applied again in a reduced form.
+func (c *Client) getBucketXML(ctx context.Context, bucket string, name string, dest interface{}) error {
+	res, err := c.do(ctx, request{method: http.MethodGet, bucket: bucket, query: subresource(name)})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", name, err)
+	}
+
+	if err = decodeXML(data, dest); err != nil {
+		return fmt.Errorf("decode %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// putBucketXML replaces the configuration of the bucket subresource by the xml encoded value.
+func (c *Client) putBucketXML(ctx context.Context, bucket string, name string, value interface{}) error {
+	data, err := xml.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+
+	return c.putBucketDocument(ctx, bucket, name, append([]byte(xml.Header), data...), "application/xml")
+}
+
+// putBucketDocument replaces the configuration of the bucket subresource. The md5 hash of the document is sent along, as
+// required by some of the subresources.
+func (c *Client) putBucketDocument(ctx context.Context, bucket string, name string, data []byte, contentType string) error {
+	body := io.NewSectionReader(bytes.NewReader(data), 0, int64(len(data)))
+
+	sum, err := hashMD5(body)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{
+		"Content-Md5":  {contentMD5(sum)},
+		"Content-Type": {contentType},
+	}
+
+	_, err = c.doDiscard(ctx, request{method: http.MethodPut, bucket: bucket, query: subresource(name), header: header, body: body})
+	return err
+}
+
+// isErrorCode reports whether the error has been returned by the service with the code.
+func isErrorCode(err error, code string) bool {
+	var s3Err *Error
+	return errors.As(err, &s3Err) && s3Err.Code == code
+}
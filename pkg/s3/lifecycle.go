@@ -0,0 +1,342 @@
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// StatusEnabled enables a lifecycle rule or the versioning of a bucket.
+	StatusEnabled = "Enabled"
+
+	// StatusDisabled disables a lifecycle rule.
+	StatusDisabled = "Disabled"
+
+	// StatusSuspended suspends the versioning of a bucket, which cannot be disabled once it has been enabled.
+	StatusSuspended = "Suspended"
+
+	maxLifecycleRules = 1000
+	maxRuleIDLength   = 255
+)
+
+// LifecycleConfiguration expires the objects of a bucket, e.g. to enforce retention periods.
+type LifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration" json:"-"`
+	Rules   []LifecycleRule `xml:"Rule" json:"Rules"`
+}
+
+// LifecycleRule applies its actions to the objects selected by the filter.
+type LifecycleRule struct {
+	ID     string           `xml:"ID,omitempty" json:"ID,omitempty"`
+	Filter *LifecycleFilter `xml:"Filter,omitempty" json:"Filter,omitempty"`
+	Status string           `xml:"Status" json:"Status"`
+
+	// Prefix selects the objects of rules without filter. It is deprecated in favor of Filter.
+	Prefix *string `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+
+	Expiration                     *LifecycleExpiration            `xml:"Expiration,omitempty" json:"Expiration,omitempty"`
+	Transitions                    []LifecycleTransition           `xml:"Transition,omitempty" json:"Transitions,omitempty"`
+	NoncurrentVersionExpiration    *NoncurrentVersionExpiration    `xml:"NoncurrentVersionExpiration,omitempty" json:"NoncurrentVersionExpiration,omitempty"`
+	NoncurrentVersionTransitions   []NoncurrentVersionTransition   `xml:"NoncurrentVersionTransition,omitempty" json:"NoncurrentVersionTransitions,omitempty"`
+	AbortIncompleteMultipartUpload *AbortIncompleteMultipartUpload `xml:"AbortIncompleteMultipartUpload,omitempty" json:"AbortIncompleteMultipartUpload,omitempty"`
+}
+
+// LifecycleFilter selects objects by a single condition or by all conditions of And. An empty filter selects all
+// objects.
+type LifecycleFilter struct {
+	Prefix                *string       `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+	Tag                   *Tag          `xml:"Tag,omitempty" json:"Tag,omitempty"`
+	ObjectSizeGreaterThan *int64        `xml:"ObjectSizeGreaterThan,omitempty" json:"ObjectSizeGreaterThan,omitempty"`
+	ObjectSizeLessThan    *int64        `xml:"ObjectSizeLessThan,omitempty" json:"ObjectSizeLessThan,omitempty"`
+	And                   *LifecycleAnd `xml:"And,omitempty" json:"And,omitempty"`
+}
+
+// LifecycleAnd selects objects matching all of its conditions.
+type LifecycleAnd struct {
+	Prefix                *string `xml:"Prefix,omitempty" json:"Prefix,omitempty"`
+	Tags                  []Tag   `xml:"Tag,omitempty" json:"Tags,omitempty"`
+	ObjectSizeGreaterThan *int64  `xml:"ObjectSizeGreaterThan,omitempty" json:"ObjectSizeGreaterThan,omitempty"`
+	ObjectSizeLessThan    *int64  `xml:"ObjectSizeLessThan,omitempty" json:"ObjectSizeLessThan,omitempty"`
+}
+
+// Tag is a tag of an object.
+type Tag struct {
+	Key   string `xml:"Key" json:"Key"`
+	Value string `xml:"Value" json:"Value"`
+}
+
+// LifecycleExpiration expires objects either a number of days after their creation or at a date. In versioned buckets,
+// ExpiredObjectDeleteMarker removes delete markers without noncurrent versions instead.
+type LifecycleExpiration struct {
+	Days                      int    `xml:"Days,omitempty" json:"Days,omitempty"`
+	Date                      string `xml:"Date,omitempty" json:"Date,omitempty"`
+	ExpiredObjectDeleteMarker bool   `xml:"ExpiredObjectDeleteMarker,omitempty" json:"ExpiredObjectDeleteMarker,omitempty"`
+}
+
+// LifecycleTransition moves objects to another storage class either a number of days after their creation or at a date.
+type LifecycleTransition struct {
+	Days         int    `xml:"Days,omitempty" json:"Days,omitempty"`
+	Date         string `xml:"Date,omitempty" json:"Date,omitempty"`
+	StorageClass string `xml:"StorageClass" json:"StorageClass"`
+}
+
+// NoncurrentVersionExpiration deletes the versions of an object a number of days after they have been replaced. The
+// newest NewerNoncurrentVersions versions are kept regardless of their age.
+type NoncurrentVersionExpiration struct {
+	NoncurrentDays          int `xml:"NoncurrentDays" json:"NoncurrentDays"`
+	NewerNoncurrentVersions int `xml:"NewerNoncurrentVersions,omitempty" json:"NewerNoncurrentVersions,omitempty"`
+}
+
+// NoncurrentVersionTransition moves the versions of an object to another storage class a number of days after they
+// have been replaced.
+type NoncurrentVersionTransition struct {
+	NoncurrentDays          int    `xml:"NoncurrentDays" json:"NoncurrentDays"`
+	StorageClass            string `xml:"StorageClass" json:"StorageClass"`
+	NewerNoncurrentVersions int    `xml:"NewerNoncurrentVersions,omitempty" json:"NewerNoncurrentVersions,omitempty"`
+}
+
+// AbortIncompleteMultipartUpload aborts multipart uploads, which have not been completed within a number of days.
+type AbortIncompleteMultipartUpload struct {
+	DaysAfterInitiation int `xml:"DaysAfterInitiation" json:"DaysAfterInitiation"`
+}
+
+// ParseLifecycleConfiguration decodes and validates a lifecycle configuration given as xml or json.
+func ParseLifecycleConfiguration(data []byte) (LifecycleConfiguration, error) {
+	var config LifecycleConfiguration
+	if err := decodeDocument(data, &config); err != nil {
+		return LifecycleConfiguration{}, err
+	}
+
+	return config, config.Validate()
+}
+
+// Validate checks the configuration for errors, which would be rejected by the service.
+func (l LifecycleConfiguration) Validate() error {
+	if len(l.Rules) == 0 {
+		return errors.New("lifecycle configuration has no rules")
+	}
+
+	if len(l.Rules) > maxLifecycleRules {
+		return fmt.Errorf("lifecycle configuration has %d rules, at most %d are allowed", len(l.Rules), maxLifecycleRules)
+	}
+
+	ids := map[string]bool{}
+	for idx, rule := range l.Rules {
+		name := fmt.Sprintf("rule %d", idx+1)
+		if rule.ID != "" {
+			name = fmt.Sprintf("rule %q", rule.ID)
+
+			if ids[rule.ID] {
+				return fmt.Errorf("%s: id is not unique", name)
+			}
+
+			ids[rule.ID] = true
+		}
+
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r LifecycleRule) validate() error {
+	if len(r.ID) > maxRuleIDLength {
+		return fmt.Errorf("id is longer than %d characters", maxRuleIDLength)
+	}
+
+	if r.Status != StatusEnabled && r.Status != StatusDisabled {
+		return fmt.Errorf("status must be %s or %s, got %q", StatusEnabled, StatusDisabled, r.Status)
+	}
+
+	if r.Filter != nil && r.Prefix != nil {
+		return errors.New("either a filter or a prefix can be given")
+	}
+
+	if r.Filter != nil {
+		if err := r.Filter.validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Expiration == nil && len(r.Transitions) == 0 && r.NoncurrentVersionExpiration == nil &&
+		len(r.NoncurrentVersionTransitions) == 0 && r.AbortIncompleteMultipartUpload == nil {
+		return errors.New("rule has no action, e.g. an expiration")
+	}
+
+	if r.Expiration != nil {
+		if err := r.Expiration.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, transition := range r.Transitions {
+		if err := transition.validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.NoncurrentVersionExpiration != nil {
+		if r.NoncurrentVersionExpiration.NoncurrentDays < 1 {
+			return errors.New("noncurrent days of the noncurrent version expiration must be positive")
+		}
+
+		if r.NoncurrentVersionExpiration.NewerNoncurrentVersions < 0 {
+			return errors.New("newer noncurrent versions of the noncurrent version expiration must not be negative")
+		}
+	}
+
+	for _, transition := range r.NoncurrentVersionTransitions {
+		if transition.NoncurrentDays < 1 {
+			return errors.New("noncurrent days of the noncurrent version transition must be positive")
+		}
+
+		if transition.StorageClass == "" {
+			return errors.New("noncurrent version transition has no storage class")
+		}
+
+		if transition.NewerNoncurrentVersions < 0 {
+			return errors.New("newer noncurrent versions of the noncurrent version transition must not be negative")
+		}
+	}
+
+	if r.AbortIncompleteMultipartUpload != nil && r.AbortIncompleteMultipartUpload.DaysAfterInitiation < 1 {
+		return errors.New("days after initiation of aborting incomplete multipart uploads must be positive")
+	}
+
+	return nil
+}
+
+func (f LifecycleFilter) validate() error {
+	set := 0
+	for _, ok := range []bool{f.Prefix != nil, f.Tag != nil, f.ObjectSizeGreaterThan != nil, f.ObjectSizeLessThan != nil, f.And != nil} {
+		if ok {
+			set++
+		}
+	}
+
+	if set > 1 {
+		return errors.New("filter can only have a single condition, use And to combine them")
+	}
+
+	if f.Tag != nil && f.Tag.Key == "" {
+		return errors.New("filter tag has no key")
+	}
+
+	if f.And == nil {
+		return validateObjectSize(f.ObjectSizeGreaterThan, f.ObjectSizeLessThan)
+	}
+
+	conditions := len(f.And.Tags)
+	for _, ok := range []bool{f.And.Prefix != nil, f.And.ObjectSizeGreaterThan != nil, f.And.ObjectSizeLessThan != nil} {
+		if ok {
+			conditions++
+		}
+	}
+
+	if conditions == 0 {
+		return errors.New("filter and has no conditions")
+	}
+
+	for _, tag := range f.And.Tags {
+		if tag.Key == "" {
+			return errors.New("filter and tag has no key")
+		}
+	}
+
+	return validateObjectSize(f.And.ObjectSizeGreaterThan, f.And.ObjectSizeLessThan)
+}
+
+func validateObjectSize(greaterThan *int64, lessThan *int64) error {
+	if greaterThan != nil && *greaterThan < 0 {
+		return errors.New("object size greater than must not be negative")
+	}
+
+	if lessThan != nil && *lessThan < 1 {
+		return errors.New("object size less than must be positive")
+	}
+
+	if greaterThan != nil && lessThan != nil && *greaterThan >= *lessThan {
+		return errors.New("object size greater than must be smaller than object size less than")
+	}
+
+	return nil
+}
+
+func (e LifecycleExpiration) validate() error {
+	set := 0
+	for _, ok := range []bool{e.Days != 0, e.Date != "", e.ExpiredObjectDeleteMarker} {
+		if ok {
+			set++
+		}
+	}
+
+	if set != 1 {
+		return errors.New("expiration needs exactly one of days, date or expired object delete marker")
+	}
+
+	if e.Days < 0 {
+		return errors.New("days of the expiration must be positive")
+	}
+
+	return validateLifecycleDate("expiration", e.Date)
+}
+
+func (t LifecycleTransition) validate() error {
+	if (t.Days != 0) == (t.Date != "") {
+		return errors.New("transition needs exactly one of days or date")
+	}
+
+	if t.Days < 0 {
+		return errors.New("days of the transition must be positive")
+	}
+
+	if t.StorageClass == "" {
+		return errors.New("transition has no storage class")
+	}
+
+	return validateLifecycleDate("transition", t.Date)
+}
+
+// validateLifecycleDate checks that the date of an action is given as midnight UTC, which is the only time accepted.
+func validateLifecycleDate(action string, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return fmt.Errorf("date of the %s must be in the format 2006-01-02T00:00:00Z: %w", action, err)
+	}
+
+	if !date.UTC().Truncate(24 * time.Hour).Equal(date) {
+		return fmt.Errorf("date of the %s must be at midnight UTC", action)
+	}
+
+	return nil
+}
+
+// GetBucketLifecycle returns the lifecycle configuration of the bucket or nil if it has none.
+func (c *Client) GetBucketLifecycle(ctx context.Context, bucket string) (*LifecycleConfiguration, error) {
+	config := &LifecycleConfiguration{}
+
+	err := c.getBucketXML(ctx, bucket, "lifecycle", config)
+	if isErrorCode(err, "NoSuchLifecycleConfiguration") {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// PutBucketLifecycle replaces the lifecycle configuration of the bucket.
+func (c *Client) PutBucketLifecycle(ctx context.Context, bucket string, config LifecycleConfiguration) error {
+	return c.putBucketXML(ctx, bucket, "lifecycle", config)
+}
@@ -0,0 +1,168 @@
+package s3
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestParseLifecycleConfiguration(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		err      string
+	}{
+		{
+			name:     "json expiration",
+			document: `{"Rules": [{"ID": "logs", "Filter": {"Prefix": "logs/"}, "Status": "Enabled", "Expiration": {"Days": 90}}]}`,
+		},
+		{
+			name:     "xml expiration",
+			document: `<LifecycleConfiguration><Rule><ID>logs</ID><Filter><Prefix>logs/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>90</Days></Expiration></Rule></LifecycleConfiguration>`,
+		},
+		{
+			name: "xml with namespace and all actions",
+			document: `<?xml version="1.0" encoding="UTF-8"?>
+				<LifecycleConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+					<Rule>
+						<ID>archive</ID>
+						<Filter>
+							<And>
+								<Prefix>logs/</Prefix>
+								<Tag><Key>retention</Key><Value>short</Value></Tag>
+								<Tag><Key>team</Key><Value>ops</Value></Tag>
+								<ObjectSizeGreaterThan>1024</ObjectSizeGreaterThan>
+							</And>
+						</Filter>
+						<Status>Enabled</Status>
+						<Transition><Days>30</Days><StorageClass>GLACIER</StorageClass></Transition>
+						<Expiration><Days>365</Days></Expiration>
+						<NoncurrentVersionTransition><NoncurrentDays>7</NoncurrentDays><StorageClass>GLACIER</StorageClass></NoncurrentVersionTransition>
+						<NoncurrentVersionExpiration><NoncurrentDays>30</NoncurrentDays><NewerNoncurrentVersions>3</NewerNoncurrentVersions></NoncurrentVersionExpiration>
+						<AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload>
+					</Rule>
+				</LifecycleConfiguration>`,
+		},
+		{
+			name:     "json and filter",
+			document: `{"Rules": [{"Filter": {"And": {"Prefix": "logs/", "Tags": [{"Key": "a", "Value": "b"}], "ObjectSizeLessThan": 100}}, "Status": "Enabled", "Expiration": {"Days": 1}}]}`,
+		},
+		{
+			name:     "xml unknown filter element",
+			document: `<LifecycleConfiguration><Rule><Filter><Unknown><Prefix>logs/</Prefix></Unknown></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`,
+			err:      "unknown element <Unknown> in <LifecycleConfiguration>Rule>Filter>",
+		},
+		{
+			name:     "xml unknown action",
+			document: `<LifecycleConfiguration><Rule><Status>Enabled</Status><Expiration><Days>1</Days></Expiration><Archive><Days>1</Days></Archive></Rule></LifecycleConfiguration>`,
+			err:      "unknown element <Archive>",
+		},
+		{
+			name:     "json unknown field",
+			document: `{"Rules": [{"Status": "Enabled", "Expires": {"Days": 1}}]}`,
+			err:      `unknown field "Expires"`,
+		},
+		{
+			name:     "xml wrong root",
+			document: `<CORSConfiguration></CORSConfiguration>`,
+			err:      "expected element type <LifecycleConfiguration>",
+		},
+		{
+			name:     "no rules",
+			document: `{"Rules": []}`,
+			err:      "no rules",
+		},
+		{
+			name:     "invalid status",
+			document: `{"Rules": [{"Status": "on", "Expiration": {"Days": 1}}]}`,
+			err:      `status must be Enabled or Disabled, got "on"`,
+		},
+		{
+			name:     "duplicate id",
+			document: `{"Rules": [{"ID": "a", "Status": "Enabled", "Expiration": {"Days": 1}}, {"ID": "a", "Status": "Enabled", "Expiration": {"Days": 2}}]}`,
+			err:      `rule "a": id is not unique`,
+		},
+		{
+			name:     "no action",
+			document: `{"Rules": [{"Status": "Enabled"}]}`,
+			err:      "no action",
+		},
+		{
+			name:     "multiple filter conditions",
+			document: `{"Rules": [{"Filter": {"Prefix": "a/", "ObjectSizeLessThan": 10}, "Status": "Enabled", "Expiration": {"Days": 1}}]}`,
+			err:      "single condition",
+		},
+		{
+			name:     "empty and",
+			document: `<LifecycleConfiguration><Rule><Filter><And></And></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`,
+			err:      "filter and has no conditions",
+		},
+		{
+			name:     "inverted object size",
+			document: `{"Rules": [{"Filter": {"And": {"ObjectSizeGreaterThan": 10, "ObjectSizeLessThan": 10}}, "Status": "Enabled", "Expiration": {"Days": 1}}]}`,
+			err:      "object size greater than must be smaller",
+		},
+		{
+			name:     "multiple expirations",
+			document: `{"Rules": [{"Status": "Enabled", "Expiration": {"Days": 1, "Date": "2030-01-01T00:00:00Z"}}]}`,
+			err:      "exactly one of days, date",
+		},
+		{
+			name:     "date not at midnight",
+			document: `{"Rules": [{"Status": "Enabled", "Expiration": {"Date": "2030-01-01T12:00:00Z"}}]}`,
+			err:      "midnight UTC",
+		},
+		{
+			name:     "transition without storage class",
+			document: `{"Rules": [{"Status": "Enabled", "Transitions": [{"Days": 30}]}]}`,
+			err:      "transition has no storage class",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseLifecycleConfiguration([]byte(test.document))
+			checkError(t, err, test.err)
+		})
+	}
+}
+
+func TestLifecycleConfigurationRoundTrip(t *testing.T) {
+	document := `{"Rules": [{"ID": "archive", "Filter": {"And": {"Prefix": "logs/", "Tags": [{"Key": "a", "Value": "b"}]}}, "Status": "Enabled", "Transitions": [{"Days": 30, "StorageClass": "GLACIER"}], "Expiration": {"Days": 365}}]}`
+
+	config, err := ParseLifecycleConfiguration([]byte(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the configuration is uploaded as xml and printed as json by get
+	data, err := xml.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := ParseLifecycleConfiguration(data)
+	if err != nil {
+		t.Fatalf("parse %s: %v", data, err)
+	}
+
+	expected, _ := json.Marshal(config)
+	actual, _ := json.Marshal(decoded)
+	if string(expected) != string(actual) {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
+
+func checkError(t *testing.T, err error, expected string) {
+	t.Helper()
+
+	switch {
+	case expected == "" && err != nil:
+		t.Errorf("unexpected error: %v", err)
+	case expected != "" && err == nil:
+		t.Errorf("expected error containing %q", expected)
+	case expected != "" && !strings.Contains(err.Error(), expected):
+		t.Errorf("expected error containing %q, got %v", expected, err)
+	}
+}
@@ -0,0 +1,190 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// MinPartSize is the minimum size of all but the last part of a multipart upload.
+	MinPartSize = 5 << 20
+
+	// MaxParts is the maximum number of parts of a multipart upload.
+	MaxParts = 10000
+)
+
+// Part is an uploaded part of a multipart upload. The ETag is the md5 hash of the part.
+type Part struct {
+	PartNumber int    `xml:"PartNumber" json:"part_number"`
+	ETag       string `xml:"ETag" json:"etag"`
+	Size       int64  `xml:"Size" json:"size"`
+}
+
+type initiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+// CreateMultipartUpload starts a multipart upload and returns its id, which is passed to the other multipart requests.
+func (c *Client) CreateMultipartUpload(ctx context.Context, bucket string, key string, opts PutOptions) (string, error) {
+	header := http.Header{}
+	if opts.ContentType != "" {
+		header.Set("Content-Type", opts.ContentType)
+	}
+
+	var res initiateMultipartUploadResult
+	err := c.doXML(ctx, request{method: http.MethodPost, bucket: bucket, key: key, query: url.Values{"uploads": {""}}, header: header}, &res)
+	if err != nil {
+		return "", err
+	}
+
+	if res.UploadID == "" {
+		return "", fmt.Errorf("missing upload id in response")
+	}
+
+	return res.UploadID, nil
+}
+
+// UploadPart uploads a part of a multipart upload and returns its ETag. Parts are numbered from 1 to MaxParts. The
+// md5 hash of the part is sent along, so the service rejects corrupted parts, and compared to the returned ETag.
+func (c *Client) UploadPart(ctx context.Context, bucket string, key string, uploadID string, number int, body *io.SectionReader) (string, error) {
+	sum, err := hashMD5(body)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+	header := http.Header{"Content-Md5": {contentMD5(sum)}}
+
+	res, err := c.doDiscard(ctx, request{method: http.MethodPut, bucket: bucket, key: key, query: query, header: header, body: body})
+	if err != nil {
+		return "", err
+	}
+
+	etag := trimETag(res.Header.Get("ETag"))
+	if etag != hex.EncodeToString(sum) {
+		return "", fmt.Errorf("verify part %d: etag %q does not match the md5 hash %x", number, etag, sum)
+	}
+
+	return etag, nil
+}
+
+type listPartsResult struct {
+	Parts                []Part `xml:"Part"`
+	IsTruncated          bool   `xml:"IsTruncated"`
+	NextPartNumberMarker int    `xml:"NextPartNumberMarker"`
+}
+
+// ListParts returns the parts uploaded so far. It fails with a not found error if the upload has been completed or
+// aborted.
+func (c *Client) ListParts(ctx context.Context, bucket string, key string, uploadID string) ([]Part, error) {
+	var (
+		parts  []Part
+		marker int
+	)
+
+	for {
+		query := url.Values{"uploadId": {uploadID}}
+		if marker > 0 {
+			query.Set("part-number-marker", strconv.Itoa(marker))
+		}
+
+		var res listPartsResult
+		if err := c.doXML(ctx, request{method: http.MethodGet, bucket: bucket, key: key, query: query}, &res); err != nil {
+			return nil, err
+		}
+
+		for _, part := range res.Parts {
+			part.ETag = trimETag(part.ETag)
+			parts = append(parts, part)
+		}
+
+		if !res.IsTruncated || res.NextPartNumberMarker <= marker {
+			return parts, nil
+		}
+
+		marker = res.NextPartNumberMarker
+	}
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+// completeMultipartUploadResult is either the result or, for requests failing after the response has been started, an
+// error with status 200.
+type completeMultipartUploadResult struct {
+	XMLName xml.Name
+	ETag    string `xml:"ETag"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// CompleteMultipartUpload assembles the parts, which have to be sorted by their number, into the object and returns its
+// ETag.
+func (c *Client) CompleteMultipartUpload(ctx context.Context, bucket string, key string, uploadID string, parts []Part) (string, error) {
+	body := completeMultipartUpload{Parts: make([]completedPart, len(parts))}
+	for idx, part := range parts {
+		body.Parts[idx] = completedPart{PartNumber: part.PartNumber, ETag: `"` + part.ETag + `"`}
+	}
+
+	data, err := xml.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("encode parts: %w", err)
+	}
+
+	r := request{
+		method: http.MethodPost,
+		bucket: bucket,
+		key:    key,
+		query:  url.Values{"uploadId": {uploadID}},
+		header: http.Header{"Content-Type": {"application/xml"}},
+		body:   io.NewSectionReader(bytes.NewReader(data), 0, int64(len(data))),
+	}
+
+	var res completeMultipartUploadResult
+	if err = c.doXML(ctx, r, &res); err != nil {
+		return "", err
+	}
+
+	if res.XMLName.Local == "Error" {
+		return "", &Error{StatusCode: http.StatusOK, Code: res.Code, Message: res.Message}
+	}
+
+	return trimETag(res.ETag), nil
+}
+
+// AbortMultipartUpload aborts a multipart upload and deletes the uploaded parts.
+func (c *Client) AbortMultipartUpload(ctx context.Context, bucket string, key string, uploadID string) error {
+	_, err := c.doDiscard(ctx, request{method: http.MethodDelete, bucket: bucket, key: key, query: url.Values{"uploadId": {uploadID}}})
+	return err
+}
+
+// MultipartETag returns the ETag of an object assembled from parts with the given ETags, which is the md5 hash of the
+// concatenated md5 hashes of the parts followed by the number of parts, e.g. "9b2cf535f27731c974343645a3985328-3".
+func MultipartETag(partETags []string) (string, error) {
+	hash := md5.New()
+	for _, etag := range partETags {
+		sum, err := hex.DecodeString(etag)
+		if err != nil || len(sum) != md5.Size {
+			return "", fmt.Errorf("etag %q is not an md5 hash", etag)
+		}
+
+		hash.Write(sum)
+	}
+
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(hash.Sum(nil)), len(partETags)), nil
+}
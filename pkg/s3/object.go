@@ -0,0 +1,205 @@
+package s3
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Object is a stored object. The ETag is returned without quotes.
+type Object struct {
+	Key          string    `xml:"Key" json:"key"`
+	LastModified time.Time `xml:"LastModified" json:"last_modified"`
+	ETag         string    `xml:"ETag" json:"etag"`
+	Size         int64     `xml:"Size" json:"size"`
+	StorageClass string    `xml:"StorageClass" json:"storage_class,omitempty"`
+	ContentType  string    `xml:"-" json:"content_type,omitempty"`
+}
+
+// ListOptions select the objects of a bucket.
+type ListOptions struct {
+	Prefix string
+
+	// Delimiter groups all keys with the same prefix up to the delimiter into a common prefix, e.g. "/" to list a
+	// single level of a hierarchy.
+	Delimiter string
+
+	ContinuationToken string
+	MaxKeys           int
+}
+
+// ObjectList is a single page of objects.
+type ObjectList struct {
+	Objects               []Object `xml:"Contents"`
+	CommonPrefixes        []string `xml:"CommonPrefixes>Prefix"`
+	IsTruncated           bool     `xml:"IsTruncated"`
+	NextContinuationToken string   `xml:"NextContinuationToken"`
+}
+
+// ListObjects returns a single page of the objects in the bucket.
+func (c *Client) ListObjects(ctx context.Context, bucket string, opts ListOptions) (ObjectList, error) {
+	query := url.Values{"list-type": {"2"}}
+	if opts.Prefix != "" {
+		query.Set("prefix", opts.Prefix)
+	}
+
+	if opts.Delimiter != "" {
+		query.Set("delimiter", opts.Delimiter)
+	}
+
+	if opts.ContinuationToken != "" {
+		query.Set("continuation-token", opts.ContinuationToken)
+	}
+
+	if opts.MaxKeys > 0 {
+		query.Set("max-keys", strconv.Itoa(opts.MaxKeys))
+	}
+
+	var list ObjectList
+	if err := c.doXML(ctx, request{method: http.MethodGet, bucket: bucket, query: query}, &list); err != nil {
+		return ObjectList{}, err
+	}
+
+	for idx := range list.Objects {
+		list.Objects[idx].ETag = trimETag(list.Objects[idx].ETag)
+	}
+
+	return list, nil
+}
+
+// ListAllObjects returns the objects and common prefixes of all pages.
+func (c *Client) ListAllObjects(ctx context.Context, bucket string, opts ListOptions) ([]Object, []string, error) {
+	var (
+		objects  []Object
+		prefixes []string
+	)
+
+	for {
+		list, err := c.ListObjects(ctx, bucket, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		objects = append(objects, list.Objects...)
+		prefixes = append(prefixes, list.CommonPrefixes...)
+
+		if !list.IsTruncated || list.NextContinuationToken == "" {
+			return objects, prefixes, nil
+		}
+
+		opts.ContinuationToken = list.NextContinuationToken
+	}
+}
+
+// HeadObject returns the metadata of an object.
+func (c *Client) HeadObject(ctx context.Context, bucket string, key string) (Object, error) {
+	res, err := c.doDiscard(ctx, request{method: http.MethodHead, bucket: bucket, key: key})
+	if err != nil {
+		return Object{}, err
+	}
+
+	return objectFromHeader(key, res), nil
+}
+
+// GetObject returns the content of an object, which has to be closed by the caller.
+func (c *Client) GetObject(ctx context.Context, bucket string, key string) (io.ReadCloser, Object, error) {
+	res, err := c.do(ctx, request{method: http.MethodGet, bucket: bucket, key: key})
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	return res.Body, objectFromHeader(key, res), nil
+}
+
+// PutOptions are the optional settings of an uploaded object.
+type PutOptions struct {
+	ContentType string
+}
+
+// PutObject uploads the content of an object in a single request and returns its ETag. The md5 hash of the content is
+// sent along, so the service rejects corrupted uploads, and compared to the returned ETag.
+func (c *Client) PutObject(ctx context.Context, bucket string, key string, body *io.SectionReader, opts PutOptions) (string, error) {
+	sum, err := hashMD5(body)
+	if err != nil {
+		return "", err
+	}
+
+	header := http.Header{"Content-Md5": {contentMD5(sum)}}
+	if opts.ContentType != "" {
+		header.Set("Content-Type", opts.ContentType)
+	}
+
+	res, err := c.doDiscard(ctx, request{method: http.MethodPut, bucket: bucket, key: key, header: header, body: body})
+	if err != nil {
+		return "", err
+	}
+
+	etag := trimETag(res.Header.Get("ETag"))
+	if etag != hex.EncodeToString(sum) {
+		return "", fmt.Errorf("verify upload: etag %q does not match the md5 hash %x", etag, sum)
+	}
+
+	return etag, nil
+}
+
+type copyObjectResult struct {
+	ETag string `xml:"ETag"`
+}
+
+// CopyObject copies an object within the service, without downloading it, and returns the ETag of the copy.
+func (c *Client) CopyObject(ctx context.Context, srcBucket string, srcKey string, bucket string, key string) (string, error) {
+	header := http.Header{}
+	header.Set("X-Amz-Copy-Source", uriEncode("/"+srcBucket+"/"+srcKey, false))
+
+	var res copyObjectResult
+	if err := c.doXML(ctx, request{method: http.MethodPut, bucket: bucket, key: key, header: header}, &res); err != nil {
+		return "", err
+	}
+
+	return trimETag(res.ETag), nil
+}
+
+// DeleteObject deletes an object. Deleting an object which does not exist succeeds.
+func (c *Client) DeleteObject(ctx context.Context, bucket string, key string) error {
+	_, err := c.doDiscard(ctx, request{method: http.MethodDelete, bucket: bucket, key: key})
+	return err
+}
+
+// PresignObject returns a url of the object, which allows anyone with the url to send a request with the method, e.g.
+// GET to download or PUT to upload the object, until it expires. The url is signed locally without contacting the
+// service.
+func (c *Client) PresignObject(method string, bucket string, key string, expires time.Duration) (string, error) {
+	if expires < time.Second || expires > MaxPresignExpiry {
+		return "", fmt.Errorf("expiry must be between 1s and %s", MaxPresignExpiry)
+	}
+
+	u := c.url(bucket, key, nil)
+	c.credentials.Presign(method, u, time.Now(), expires)
+
+	return u.String(), nil
+}
+
+func objectFromHeader(key string, res *http.Response) Object {
+	object := Object{
+		Key:         key,
+		ETag:        trimETag(res.Header.Get("ETag")),
+		Size:        res.ContentLength,
+		ContentType: res.Header.Get("Content-Type"),
+	}
+
+	if modified, err := http.ParseTime(res.Header.Get("Last-Modified")); err == nil {
+		object.LastModified = modified
+	}
+
+	return object
+}
+
+func trimETag(etag string) string {
+	return strings.Trim(etag, `"`)
+}
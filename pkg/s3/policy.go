@@ -0,0 +1,141 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const maxPolicySize = 20 << 10
+
+// BucketPolicy grants or denies access to a bucket and its objects. Policies are json documents in the IAM policy
+// language and are passed through unchanged, so only the fields checked before the upload are decoded.
+type BucketPolicy struct {
+	Version   string          `json:"Version"`
+	ID        string          `json:"Id,omitempty"`
+	Statement json.RawMessage `json:"Statement"`
+}
+
+// PolicyStatement is a single statement of a bucket policy.
+type PolicyStatement struct {
+	Sid          string          `json:"Sid,omitempty"`
+	Effect       string          `json:"Effect"`
+	Principal    json.RawMessage `json:"Principal,omitempty"`
+	NotPrincipal json.RawMessage `json:"NotPrincipal,omitempty"`
+	Action       json.RawMessage `json:"Action,omitempty"`
+	NotAction    json.RawMessage `json:"NotAction,omitempty"`
+	Resource     json.RawMessage `json:"Resource,omitempty"`
+	NotResource  json.RawMessage `json:"NotResource,omitempty"`
+	Condition    json.RawMessage `json:"Condition,omitempty"`
+}
+
+// ValidatePolicy checks the json bucket policy for errors, which would be rejected by the service.
+func ValidatePolicy(data []byte) error {
+	if len(data) > maxPolicySize {
+		return fmt.Errorf("policy has %d bytes, at most %d are allowed", len(data), maxPolicySize)
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) != 0 && trimmed[0] != '{' {
+		return errors.New("policy must be a json object")
+	}
+
+	var policy BucketPolicy
+	if err := decodeDocument(data, &policy); err != nil {
+		return err
+	}
+
+	if policy.Version != "2012-10-17" && policy.Version != "2008-10-17" {
+		return fmt.Errorf("version must be 2012-10-17 or 2008-10-17, got %q", policy.Version)
+	}
+
+	statements, err := policy.Statements()
+	if err != nil {
+		return err
+	}
+
+	for idx, statement := range statements {
+		name := fmt.Sprintf("statement %d", idx+1)
+		if statement.Sid != "" {
+			name = fmt.Sprintf("statement %q", statement.Sid)
+		}
+
+		if err := statement.validate(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Statements decodes the statements of the policy, which are either a single object or an array.
+func (p BucketPolicy) Statements() ([]PolicyStatement, error) {
+	data := bytes.TrimSpace(p.Statement)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, errors.New("policy has no statements")
+	}
+
+	if data[0] == '{' {
+		data = append(append([]byte{'['}, data...), ']')
+	}
+
+	var statements []PolicyStatement
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&statements); err != nil {
+		return nil, fmt.Errorf("parse statements: %w", err)
+	}
+
+	if len(statements) == 0 {
+		return nil, errors.New("policy has no statements")
+	}
+
+	return statements, nil
+}
+
+func (s PolicyStatement) validate() error {
+	if s.Effect != "Allow" && s.Effect != "Deny" {
+		return fmt.Errorf("effect must be Allow or Deny, got %q", s.Effect)
+	}
+
+	for _, pair := range []struct {
+		name     string
+		value    json.RawMessage
+		notValue json.RawMessage
+	}{
+		{"principal", s.Principal, s.NotPrincipal},
+		{"action", s.Action, s.NotAction},
+		{"resource", s.Resource, s.NotResource},
+	} {
+		if (len(pair.value) == 0) == (len(pair.notValue) == 0) {
+			return fmt.Errorf("exactly one of %s or not %s is required", pair.name, pair.name)
+		}
+	}
+
+	return nil
+}
+
+// GetBucketPolicy returns the json policy of the bucket or nil if it has none.
+func (c *Client) GetBucketPolicy(ctx context.Context, bucket string) ([]byte, error) {
+	res, err := c.do(ctx, request{method: http.MethodGet, bucket: bucket, query: subresource("policy")})
+	if isErrorCode(err, "NoSuchBucketPolicy") {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	return io.ReadAll(res.Body)
+}
+
+// PutBucketPolicy replaces the policy of the bucket by the json document.
+func (c *Client) PutBucketPolicy(ctx context.Context, bucket string, policy []byte) error {
+	return c.putBucketDocument(ctx, bucket, "policy", policy, "application/json")
+}
@@ -0,0 +1,304 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	signAlgorithm  = "AWS4-HMAC-SHA256"
+	signService    = "s3"
+	signTerminator = "aws4_request"
+	signTimeFormat = "20060102T150405Z"
+	signDateFormat = "20060102"
+
+	headerDate          = "X-Amz-Date"
+	headerContentSHA256 = "X-Amz-Content-Sha256"
+
+	queryAlgorithm     = "X-Amz-Algorithm"
+	queryCredential    = "X-Amz-Credential"
+	queryDate          = "X-Amz-Date"
+	queryExpires       = "X-Amz-Expires"
+	querySignedHeaders = "X-Amz-SignedHeaders"
+	querySignature     = "X-Amz-Signature"
+
+	// MaxPresignExpiry is the longest time a presigned url can be valid.
+	MaxPresignExpiry = 7 * 24 * time.Hour
+
+	// UnsignedPayload is used as payload hash if the body is not part of the signature.
+	UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// emptyPayloadHash is the hex encoded sha256 hash of an empty body.
+var emptyPayloadHash = hashHex(nil)
+
+// Credentials are the keys used to sign requests using AWS signature version 4.
+type Credentials struct {
+	AccessKey string
+	SecretKey string
+
+	// Region is part of the signature scope. Defaults to DefaultRegion.
+	Region string
+}
+
+func (c Credentials) region() string {
+	if c.Region == "" {
+		return DefaultRegion
+	}
+
+	return c.Region
+}
+
+// Sign adds the authorization header to the request. The payload hash is the hex encoded sha256 hash of the body or
+// UnsignedPayload.
+func (c Credentials) Sign(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+
+	req.Header.Set(headerDate, t.Format(signTimeFormat))
+	req.Header.Set(headerContentSHA256, payloadHash)
+
+	signedHeaders := signedHeaderNames(req.Header)
+	scope := c.scope(t)
+
+	canonical := canonicalRequest(req.Method, req.URL.EscapedPath(), req.URL.Query(), requestHost(req), req.Header, signedHeaders, payloadHash)
+	signature := c.signature(t, scope, canonical)
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signAlgorithm, c.AccessKey, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+// Presign adds the signature to the query of the url, which allows anyone with the url to send a request with the
+// method until it expires. Only the host is signed, so the request may have any headers and body.
+func (c Credentials) Presign(method string, u *url.URL, t time.Time, expires time.Duration) {
+	t = t.UTC()
+	scope := c.scope(t)
+
+	query := u.Query()
+	query.Set(queryAlgorithm, signAlgorithm)
+	query.Set(queryCredential, c.AccessKey+"/"+scope)
+	query.Set(queryDate, t.Format(signTimeFormat))
+	query.Set(queryExpires, strconv.Itoa(int(expires/time.Second)))
+	query.Set(querySignedHeaders, "host")
+
+	canonical := canonicalRequest(method, u.EscapedPath(), query, u.Host, nil, []string{"host"}, UnsignedPayload)
+	query.Set(querySignature, c.signature(t, scope, canonical))
+
+	u.RawQuery = canonicalQuery(query)
+}
+
+func (c Credentials) scope(t time.Time) string {
+	return strings.Join([]string{t.Format(signDateFormat), c.region(), signService, signTerminator}, "/")
+}
+
+func (c Credentials) signature(t time.Time, scope string, canonical string) string {
+	stringToSign := strings.Join([]string{signAlgorithm, t.Format(signTimeFormat), scope, hashHex([]byte(canonical))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.SecretKey), t.Format(signDateFormat))
+	key = hmacSHA256(key, c.region())
+	key = hmacSHA256(key, signService)
+	key = hmacSHA256(key, signTerminator)
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// Verify checks the signature in the authorization header or, for presigned urls, the query of a request received by a
+// server. The secret key is looked up by the access key of the signature.
+func Verify(req *http.Request, secretKey func(accessKey string) (string, bool)) error {
+	if req.URL.Query().Has(queryAlgorithm) {
+		return verifyPresigned(req, secretKey, time.Now())
+	}
+
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, signAlgorithm+" ") {
+		return errors.New("missing or unsupported authorization")
+	}
+
+	fields := map[string]string{}
+	for _, field := range strings.Split(strings.TrimPrefix(auth, signAlgorithm+" "), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		fields[name] = value
+	}
+
+	t, err := time.Parse(signTimeFormat, req.Header.Get(headerDate))
+	if err != nil {
+		return fmt.Errorf("invalid %s header", headerDate)
+	}
+
+	credentials, scope, err := parseCredential(fields["Credential"], t, secretKey)
+	if err != nil {
+		return err
+	}
+
+	signedHeaders := strings.Split(fields["SignedHeaders"], ";")
+	canonical := canonicalRequest(req.Method, req.URL.EscapedPath(), req.URL.Query(), req.Host, req.Header, signedHeaders, req.Header.Get(headerContentSHA256))
+
+	if !hmac.Equal([]byte(credentials.signature(t, scope, canonical)), []byte(fields["Signature"])) {
+		return errors.New("signature does not match")
+	}
+
+	return nil
+}
+
+func verifyPresigned(req *http.Request, secretKey func(accessKey string) (string, bool), now time.Time) error {
+	query := req.URL.Query()
+	if query.Get(queryAlgorithm) != signAlgorithm {
+		return errors.New("unsupported signature algorithm")
+	}
+
+	t, err := time.Parse(signTimeFormat, query.Get(queryDate))
+	if err != nil {
+		return fmt.Errorf("invalid %s parameter", queryDate)
+	}
+
+	seconds, err := strconv.Atoi(query.Get(queryExpires))
+	if err != nil || seconds < 1 || time.Duration(seconds)*time.Second > MaxPresignExpiry {
+		return fmt.Errorf("invalid %s parameter", queryExpires)
+	}
+
+	if now.After(t.Add(time.Duration(seconds) * time.Second)) {
+		return errors.New("request has expired")
+	}
+
+	credentials, scope, err := parseCredential(query.Get(queryCredential), t, secretKey)
+	if err != nil {
+		return err
+	}
+
+	signature := query.Get(querySignature)
+	query.Del(querySignature)
+
+	signedHeaders := strings.Split(query.Get(querySignedHeaders), ";")
+	canonical := canonicalRequest(req.Method, req.URL.EscapedPath(), query, req.Host, req.Header, signedHeaders, UnsignedPayload)
+
+	if !hmac.Equal([]byte(credentials.signature(t, scope, canonical)), []byte(signature)) {
+		return errors.New("signature does not match")
+	}
+
+	return nil
+}
+
+func parseCredential(credential string, t time.Time, secretKey func(accessKey string) (string, bool)) (Credentials, string, error) {
+	parts := strings.Split(credential, "/")
+	if len(parts) != 5 || parts[3] != signService || parts[4] != signTerminator {
+		return Credentials{}, "", errors.New("invalid credential scope")
+	}
+
+	if parts[1] != t.Format(signDateFormat) {
+		return Credentials{}, "", errors.New("credential scope does not match the request date")
+	}
+
+	secret, ok := secretKey(parts[0])
+	if !ok {
+		return Credentials{}, "", fmt.Errorf("unknown access key %s", parts[0])
+	}
+
+	credentials := Credentials{AccessKey: parts[0], SecretKey: secret, Region: parts[2]}
+	return credentials, strings.Join(parts[1:], "/"), nil
+}
+
+func canonicalRequest(method string, path string, query url.Values, host string, header http.Header, signedHeaders []string, payloadHash string) string {
+	if path == "" {
+		path = "/"
+	}
+
+	headers := &strings.Builder{}
+	for _, name := range signedHeaders {
+		value := host
+		if name != "host" {
+			value = strings.Join(header.Values(name), ",")
+		}
+
+		fmt.Fprintf(headers, "%s:%s\n", name, strings.Join(strings.Fields(value), " "))
+	}
+
+	return strings.Join([]string{
+		method,
+		path,
+		canonicalQuery(query),
+		headers.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+// signedHeaderNames returns the lower case names of the headers to sign, which are the host, the content type and md5
+// and all amz headers.
+func signedHeaderNames(header http.Header) []string {
+	names := []string{"host"}
+	for name := range header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "content-type" || lower == "content-md5" {
+			names = append(names, lower)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// canonicalQuery encodes the query sorted by key and value as required by the signature.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent encodes all characters except the unreserved ones of RFC 3986. The slash is kept as is unless
+// encodeSlash is set, which is required for query parameters.
+func uriEncode(s string, encodeSlash bool) string {
+	b := &strings.Builder{}
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+func requestHost(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+
+	return req.URL.Host
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,360 @@
+package s3
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultPartSize is the part size of multipart uploads, if none is given.
+const DefaultPartSize = 16 << 20
+
+// Uploader uploads files, which are larger than the part size, in multiple parts sent in parallel. The progress of
+// multipart uploads is stored in the state directory, so an interrupted upload of the same file to the same key
+// continues with the missing parts.
+type Uploader struct {
+	Client *Client
+
+	// PartSize is the size of the parts, which is increased if the file would need more than MaxParts parts. It
+	// defaults to DefaultPartSize.
+	PartSize int64
+
+	// Concurrency is the maximum number of parts uploaded in parallel. It defaults to 1.
+	Concurrency int
+
+	// StateDir is the directory holding the state of unfinished multipart uploads. Uploads cannot be resumed if it is
+	// empty.
+	StateDir string
+
+	// Progress is called with the number of bytes of every uploaded part, including the parts uploaded before an
+	// upload has been resumed.
+	Progress func(n int64)
+}
+
+// uploadState is the state of a multipart upload. The file is identified by its path, size and modification time, so
+// uploads of files changed in the meantime start over.
+type uploadState struct {
+	Endpoint string    `json:"endpoint"`
+	Bucket   string    `json:"bucket"`
+	Key      string    `json:"key"`
+	File     string    `json:"file"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+	PartSize int64     `json:"part_size"`
+	UploadID string    `json:"upload_id"`
+	Parts    []Part    `json:"parts"`
+}
+
+func (s *uploadState) matches(other uploadState) bool {
+	return s.Endpoint == other.Endpoint && s.Bucket == other.Bucket && s.Key == other.Key && s.File == other.File &&
+		s.Size == other.Size && s.Modified.Equal(other.Modified)
+}
+
+// UploadFile uploads the file to the key and returns the ETag of the object. The ETag returned by the service is
+// verified against the md5 hashes of the file.
+func (u *Uploader) UploadFile(ctx context.Context, bucket string, key string, name string, opts PutOptions) (string, error) {
+	name, err := filepath.Abs(name)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	partSize := u.partSize(info.Size())
+	if info.Size() <= partSize {
+		etag, err := u.Client.PutObject(ctx, bucket, key, io.NewSectionReader(file, 0, info.Size()), opts)
+		if err == nil {
+			u.progress(info.Size())
+		}
+
+		return etag, err
+	}
+
+	state := &uploadState{
+		Endpoint: u.Client.Endpoint(),
+		Bucket:   bucket,
+		Key:      key,
+		File:     name,
+		Size:     info.Size(),
+		Modified: info.ModTime(),
+		PartSize: partSize,
+	}
+
+	if err = u.resume(ctx, state); err != nil {
+		return "", err
+	}
+
+	if state.UploadID == "" {
+		if state.UploadID, err = u.Client.CreateMultipartUpload(ctx, bucket, key, opts); err != nil {
+			return "", err
+		}
+
+		if err = u.saveState(state); err != nil {
+			return "", err
+		}
+	}
+
+	if err = u.uploadParts(ctx, file, state); err != nil {
+		return "", err
+	}
+
+	etag, err := u.Client.CompleteMultipartUpload(ctx, bucket, key, state.UploadID, state.Parts)
+	if err != nil {
+		return "", fmt.Errorf("complete upload: %w", err)
+	}
+
+	u.removeState(state)
+
+	partETags := make([]string, len(state.Parts))
+	for idx, part := range state.Parts {
+		partETags[idx] = part.ETag
+	}
+
+	expected, err := MultipartETag(partETags)
+	if err != nil {
+		return "", err
+	}
+
+	if etag != expected {
+		return "", fmt.Errorf("verify upload: etag %q does not match the md5 hashes of the parts %q", etag, expected)
+	}
+
+	return etag, nil
+}
+
+// partSize returns the configured part size or, if the file would need more than MaxParts parts, the smallest
+// multiple of a MiB which fits the file into MaxParts parts.
+func (u *Uploader) partSize(size int64) int64 {
+	partSize := u.PartSize
+	if partSize <= 0 {
+		partSize = DefaultPartSize
+	}
+
+	if partSize < MinPartSize {
+		partSize = MinPartSize
+	}
+
+	if min := (size + MaxParts - 1) / MaxParts; partSize < min {
+		partSize = (min + 1<<20 - 1) &^ (1<<20 - 1)
+	}
+
+	return partSize
+}
+
+// resume loads the state of a previous upload of the same file and keeps the parts, which are still stored by the
+// service. The previous upload is aborted if the file has changed since.
+func (u *Uploader) resume(ctx context.Context, state *uploadState) error {
+	previous, err := u.loadState(state)
+	if err != nil || previous == nil {
+		return err
+	}
+
+	if !previous.matches(*state) {
+		_ = u.Client.AbortMultipartUpload(ctx, previous.Bucket, previous.Key, previous.UploadID)
+		u.removeState(previous)
+		return nil
+	}
+
+	stored, err := u.Client.ListParts(ctx, state.Bucket, state.Key, previous.UploadID)
+	if IsNotFound(err) {
+		u.removeState(previous)
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("fetch parts of upload: %w", err)
+	}
+
+	etags := make(map[int]string, len(stored))
+	for _, part := range stored {
+		etags[part.PartNumber] = part.ETag
+	}
+
+	state.PartSize = previous.PartSize
+	state.UploadID = previous.UploadID
+	for _, part := range previous.Parts {
+		if etags[part.PartNumber] == part.ETag {
+			state.Parts = append(state.Parts, part)
+			u.progress(part.Size)
+		}
+	}
+
+	return nil
+}
+
+// uploadParts uploads all parts missing in the state and adds them to the state, which is saved after every part.
+func (u *Uploader) uploadParts(ctx context.Context, file *os.File, state *uploadState) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(map[int]bool, len(state.Parts))
+	for _, part := range state.Parts {
+		done[part.PartNumber] = true
+	}
+
+	count := int((state.Size + state.PartSize - 1) / state.PartSize)
+	numbers := make(chan int)
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+
+	concurrency := u.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for number := range numbers {
+				offset := int64(number-1) * state.PartSize
+				size := state.PartSize
+				if offset+size > state.Size {
+					size = state.Size - offset
+				}
+
+				etag, err := u.Client.UploadPart(ctx, state.Bucket, state.Key, state.UploadID, number, io.NewSectionReader(file, offset, size))
+
+				mu.Lock()
+				if err == nil {
+					state.Parts = append(state.Parts, Part{PartNumber: number, ETag: etag, Size: size})
+					err = u.saveState(state)
+				}
+
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("upload part %d: %w", number, err)
+					cancel()
+				}
+				mu.Unlock()
+
+				if err == nil {
+					u.progress(size)
+				}
+			}
+		}()
+	}
+
+feed:
+	for number := 1; number <= count; number++ {
+		if done[number] {
+			continue
+		}
+
+		select {
+		case numbers <- number:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+
+	close(numbers)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	sort.Slice(state.Parts, func(i, j int) bool {
+		return state.Parts[i].PartNumber < state.Parts[j].PartNumber
+	})
+
+	return nil
+}
+
+func (u *Uploader) progress(n int64) {
+	if u.Progress != nil {
+		u.Progress(n)
+	}
+}
+
+// statePath returns the path of the state file, which is named after the hash of the destination and the file.
+func (u *Uploader) statePath(state *uploadState) string {
+	hash := sha256.Sum256([]byte(state.Endpoint + "\n" + state.Bucket + "\n" + state.Key + "\n" + state.File))
+	return filepath.Join(u.StateDir, hex.EncodeToString(hash[:16])+".json")
+}
+
+func (u *Uploader) loadState(state *uploadState) (*uploadState, error) {
+	if u.StateDir == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(u.statePath(state))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read upload state: %w", err)
+	}
+
+	previous := &uploadState{}
+	if err = json.Unmarshal(data, previous); err != nil || previous.UploadID == "" || previous.PartSize <= 0 {
+		// a corrupt state only loses the progress of the previous upload
+		u.removeState(state)
+		return nil, nil
+	}
+
+	return previous, nil
+}
+
+// saveState writes the state to a temporary file, which replaces the state file, so an interruption never leaves a
+// partially written state behind.
+func (u *Uploader) saveState(state *uploadState) error {
+	if u.StateDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(u.StateDir, 0700); err != nil {
+		return fmt.Errorf("save upload state: %w", err)
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("save upload state: %w", err)
+	}
+
+	path := u.statePath(state)
+	if err = os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return fmt.Errorf("save upload state: %w", err)
+	}
+
+	if err = os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("save upload state: %w", err)
+	}
+
+	return nil
+}
+
+func (u *Uploader) removeState(state *uploadState) {
+	if u.StateDir != "" {
+		_ = os.Remove(u.statePath(state))
+	}
+}
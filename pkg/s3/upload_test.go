@@ -0,0 +1,331 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// multipartServer implements the multipart upload requests of a single object. Uploading parts fails once failAfter
+// parts have been received, which simulates an interrupted upload.
+type multipartServer struct {
+	mu        sync.Mutex
+	nextID    int
+	uploads   map[string]map[int][]byte
+	failAfter int
+	received  []int
+	aborted   []string
+	object    []byte
+}
+
+func newMultipartServer(t *testing.T) (*multipartServer, *Client) {
+	m := &multipartServer{uploads: map[string]map[int][]byte{}}
+
+	server := httptest.NewServer(m)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, Credentials{AccessKey: "access", SecretKey: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return m, client
+}
+
+func (m *multipartServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	query := r.URL.Query()
+	if r.Method == http.MethodPost && query.Has("uploads") {
+		m.nextID++
+		uploadID := fmt.Sprintf("upload-%d", m.nextID)
+		m.uploads[uploadID] = map[int][]byte{}
+
+		writeXML(w, http.StatusOK, initiateMultipartUploadResult{UploadID: uploadID})
+		return
+	}
+
+	uploadID := query.Get("uploadId")
+	parts, ok := m.uploads[uploadID]
+	if !ok {
+		writeXML(w, http.StatusNotFound, Error{Code: "NoSuchUpload"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if m.failAfter > 0 && len(m.received) >= m.failAfter {
+			writeXML(w, http.StatusInternalServerError, Error{Code: "InternalError", Message: "connection lost"})
+			return
+		}
+
+		data, _ := io.ReadAll(r.Body)
+		number := 0
+		_, _ = fmt.Sscan(query.Get("partNumber"), &number)
+
+		parts[number] = data
+		m.received = append(m.received, number)
+
+		sum := md5.Sum(data)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	case http.MethodGet:
+		res := listPartsResult{}
+		for _, number := range sortedParts(parts) {
+			sum := md5.Sum(parts[number])
+			res.Parts = append(res.Parts, Part{PartNumber: number, ETag: `"` + hex.EncodeToString(sum[:]) + `"`, Size: int64(len(parts[number]))})
+		}
+
+		writeXML(w, http.StatusOK, struct {
+			XMLName xml.Name `xml:"ListPartsResult"`
+			listPartsResult
+		}{listPartsResult: res})
+	case http.MethodPost:
+		var etags []string
+		m.object = nil
+
+		for _, number := range sortedParts(parts) {
+			sum := md5.Sum(parts[number])
+			etags = append(etags, hex.EncodeToString(sum[:]))
+			m.object = append(m.object, parts[number]...)
+		}
+
+		etag, _ := MultipartETag(etags)
+		delete(m.uploads, uploadID)
+
+		writeXML(w, http.StatusOK, struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			ETag    string   `xml:"ETag"`
+		}{ETag: `"` + etag + `"`})
+	case http.MethodDelete:
+		delete(m.uploads, uploadID)
+		m.aborted = append(m.aborted, uploadID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func sortedParts(parts map[int][]byte) []int {
+	numbers := make([]int, 0, len(parts))
+	for number := range parts {
+		numbers = append(numbers, number)
+	}
+
+	sort.Ints(numbers)
+	return numbers
+}
+
+func writeXML(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_ = xml.NewEncoder(w).Encode(body)
+}
+
+func writeRandomFile(t *testing.T, path string, size int) []byte {
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestUploadResume(t *testing.T) {
+	server, client := newMultipartServer(t)
+
+	path := filepath.Join(t.TempDir(), "large.bin")
+	data := writeRandomFile(t, path, 2*MinPartSize+1024)
+
+	uploader := &Uploader{Client: client, PartSize: MinPartSize, StateDir: t.TempDir()}
+
+	server.failAfter = 2
+	if _, err := uploader.UploadFile(context.Background(), "bucket", "large.bin", path, PutOptions{}); err == nil {
+		t.Fatal("expected the interrupted upload to fail")
+	}
+
+	if fmt.Sprint(server.received) != "[1 2]" {
+		t.Fatalf("expected parts [1 2] before the interruption, got %v", server.received)
+	}
+
+	server.failAfter, server.received = 0, nil
+
+	var progress int64
+	uploader.Progress = func(n int64) { progress += n }
+
+	if _, err := uploader.UploadFile(context.Background(), "bucket", "large.bin", path, PutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(server.received) != "[3]" {
+		t.Errorf("expected only the missing part [3] to be uploaded, got %v", server.received)
+	}
+
+	if !bytes.Equal(server.object, data) {
+		t.Errorf("expected the object to match the file")
+	}
+
+	if progress != int64(len(data)) {
+		t.Errorf("expected progress of %d bytes including the resumed parts, got %d", len(data), progress)
+	}
+
+	if entries, _ := os.ReadDir(uploader.StateDir); len(entries) != 0 {
+		t.Errorf("expected the state to be removed after the upload, got %d files", len(entries))
+	}
+}
+
+func TestUploadRestart(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(t *testing.T, path string) []byte
+		aborted []string
+	}{
+		{
+			name: "size changed",
+			change: func(t *testing.T, path string) []byte {
+				return writeRandomFile(t, path, 2*MinPartSize+2048)
+			},
+			aborted: []string{"upload-1"},
+		},
+		{
+			name: "modification time changed",
+			change: func(t *testing.T, path string) []byte {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				modified := time.Now().Add(-time.Hour)
+				if err = os.Chtimes(path, modified, modified); err != nil {
+					t.Fatal(err)
+				}
+
+				return data
+			},
+			aborted: []string{"upload-1"},
+		},
+		{
+			name: "upload gone",
+			change: func(t *testing.T, path string) []byte {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return data
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server, client := newMultipartServer(t)
+
+			path := filepath.Join(t.TempDir(), "large.bin")
+			writeRandomFile(t, path, 2*MinPartSize+1024)
+
+			uploader := &Uploader{Client: client, PartSize: MinPartSize, StateDir: t.TempDir()}
+
+			server.failAfter = 1
+			if _, err := uploader.UploadFile(context.Background(), "bucket", "large.bin", path, PutOptions{}); err == nil {
+				t.Fatal("expected the interrupted upload to fail")
+			}
+
+			data := test.change(t, path)
+			if test.aborted == nil {
+				// the upload has been aborted or completed by someone else
+				delete(server.uploads, "upload-1")
+			}
+
+			server.failAfter, server.received = 0, nil
+			if _, err := uploader.UploadFile(context.Background(), "bucket", "large.bin", path, PutOptions{}); err != nil {
+				t.Fatal(err)
+			}
+
+			if fmt.Sprint(server.aborted) != fmt.Sprint(test.aborted) {
+				t.Errorf("expected aborted uploads %v, got %v", test.aborted, server.aborted)
+			}
+
+			if fmt.Sprint(server.received) != "[1 2 3]" {
+				t.Errorf("expected all parts to be uploaded again, got %v", server.received)
+			}
+
+			if !bytes.Equal(server.object, data) {
+				t.Errorf("expected the object to match the changed file")
+			}
+		})
+	}
+}
+
+func TestUploadState(t *testing.T) {
+	uploader := &Uploader{StateDir: filepath.Join(t.TempDir(), "uploads")}
+	state := &uploadState{
+		Endpoint: "https://os.example.com",
+		Bucket:   "bucket",
+		Key:      "large.bin",
+		File:     "/data/large.bin",
+		Size:     12 << 20,
+		Modified: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+		PartSize: MinPartSize,
+		UploadID: "upload-1",
+		Parts:    []Part{{PartNumber: 1, ETag: "9b2cf535f27731c974343645a3985328", Size: MinPartSize}},
+	}
+
+	if err := uploader.saveState(state); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := uploader.loadState(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded == nil || !loaded.matches(*state) || loaded.UploadID != state.UploadID || fmt.Sprint(loaded.Parts) != fmt.Sprint(state.Parts) {
+		t.Fatalf("expected the saved state %+v, got %+v", state, loaded)
+	}
+
+	// the state of another key is stored separately
+	other := *state
+	other.Key = "other.bin"
+	if loaded, err = uploader.loadState(&other); err != nil || loaded != nil {
+		t.Errorf("expected no state for another key, got %+v, %v", loaded, err)
+	}
+
+	// a corrupt state is discarded
+	if err = os.WriteFile(uploader.statePath(state), []byte(`{"upload_id": `), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded, err = uploader.loadState(state); err != nil || loaded != nil {
+		t.Errorf("expected a corrupt state to be ignored, got %+v, %v", loaded, err)
+	}
+
+	if _, err = os.Stat(uploader.statePath(state)); !os.IsNotExist(err) {
+		t.Errorf("expected the corrupt state to be removed, got %v", err)
+	}
+
+	// without a state directory, nothing is stored
+	uploader.StateDir = ""
+	if err = uploader.saveState(state); err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded, err = uploader.loadState(state); err != nil || loaded != nil {
+		t.Errorf("expected no state without a state directory, got %+v, %v", loaded, err)
+	}
+}
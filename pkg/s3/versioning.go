@@ -0,0 +1,54 @@
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+)
+
+// VersioningConfiguration keeps the previous versions of replaced and deleted objects if it is enabled.
+type VersioningConfiguration struct {
+	XMLName xml.Name `xml:"VersioningConfiguration" json:"-"`
+	Status  string   `xml:"Status,omitempty" json:"Status,omitempty"`
+
+	// MFADelete is only accepted to report it as unsupported instead of rejecting it as an unknown field.
+	MFADelete string `xml:"MfaDelete,omitempty" json:"MFADelete,omitempty"`
+}
+
+// ParseVersioningConfiguration decodes and validates a versioning configuration given as xml or json.
+func ParseVersioningConfiguration(data []byte) (VersioningConfiguration, error) {
+	var config VersioningConfiguration
+	if err := decodeDocument(data, &config); err != nil {
+		return VersioningConfiguration{}, err
+	}
+
+	return config, config.Validate()
+}
+
+// Validate checks the configuration for errors, which would be rejected by the service.
+func (v VersioningConfiguration) Validate() error {
+	if v.Status != StatusEnabled && v.Status != StatusSuspended {
+		return fmt.Errorf("status must be %s or %s, got %q", StatusEnabled, StatusSuspended, v.Status)
+	}
+
+	if v.MFADelete != "" {
+		return errors.New("mfa delete is not supported")
+	}
+
+	return nil
+}
+
+// GetBucketVersioning returns the versioning configuration of the bucket. The status is empty if versioning has never
+// been enabled.
+func (c *Client) GetBucketVersioning(ctx context.Context, bucket string) (VersioningConfiguration, error) {
+	var config VersioningConfiguration
+	err := c.getBucketXML(ctx, bucket, "versioning", &config)
+
+	return config, err
+}
+
+// PutBucketVersioning enables or suspends the versioning of the bucket.
+func (c *Client) PutBucketVersioning(ctx context.Context, bucket string, config VersioningConfiguration) error {
+	return c.putBucketXML(ctx, bucket, "versioning", config)
+}